@@ -0,0 +1,797 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"openx/internal/core"
+	"openx/lib"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// command is an openx subcommand: openx <name> [options] [args]
+type command struct {
+	name    string
+	args    string // the arguments after the options, for usage
+	summary string
+
+	// flags registers the command's options; nil when it takes none
+	flags func(o *options, fs *flag.FlagSet)
+
+	// passArgs hands everything after the first argument through
+	// untouched, options included, as run does for the launched app
+	passArgs bool
+
+	// noConfig commands run before openx creates a missing config
+	noConfig bool
+
+	// raw commands get their arguments unparsed; hidden ones are left
+	// out of usage
+	raw, hidden bool
+
+	run func(ox *lib.OpenX, o *options, args []string)
+}
+
+// commands returns the subcommands, in the order usage lists them
+func commands() []command {
+	return []command{
+		{name: "run", args: "<alias|group> [args...]", summary: "Launch an app with arguments, or every app in a group",
+			flags: func(o *options, fs *flag.FlagSet) {
+				o.launchFlags(fs)
+				o.jsonFlag(fs)
+			}, passArgs: true, run: runRun},
+		{name: "open", args: "<file|url> [--with alias]", summary: "Open a file or URL, by default or with an app",
+			flags: func(o *options, fs *flag.FlagSet) {
+				fs.StringVar(&o.with, "with", "", "Alias or app path to open the target with")
+			}, run: runOpen},
+		{name: "proj", args: "[name]", summary: "Open a project in its editor, or list projects", run: runProject},
+		{name: "kill", args: "[alias|group...]", summary: "Close apps or groups; --tag, --all, --session or --idle pick them instead",
+			flags: killCommandFlags, run: runKill},
+		{name: "restart", args: "<alias|group...>", summary: "Close and relaunch apps, groups in dependency order",
+			flags: func(o *options, fs *flag.FlagSet) {
+				o.killFlags(fs)
+				fs.IntVar(&o.jobs, "jobs", o.jobs, "Maximum number of group members launched at once")
+			}, run: runRestart},
+		{name: "kill-test", args: "<alias...>", summary: "Show the processes kill would stop, killing nothing",
+			flags: (*options).jsonFlag, run: runKillTest},
+		{name: "status", summary: "Show the config, app counts, running apps and groups, and doctor's problems at a glance",
+			flags: (*options).jsonFlag, run: runStatus},
+		{name: "ps", summary: "Show which apps are running, with pids, groups and usage",
+			flags: func(o *options, fs *flag.FlagSet) {
+				o.jsonFlag(fs)
+				fs.BoolVar(&o.stats, "stats", o.stats, "Show CPU, memory and open files of running apps")
+			}, run: runPS},
+		{name: "doctor", summary: "Check the health of configured apps",
+			flags: func(o *options, fs *flag.FlagSet) {
+				o.doctorFlags(fs)
+				o.jsonFlag(fs)
+				fs.BoolVar(&o.yes, "yes", o.yes, "With --fix, take every match without asking")
+			}, run: runDoctor},
+		{name: "recent", summary: "List the latest distinct launches; openx - relaunches the newest",
+			flags: (*options).jsonFlag, run: runRecent},
+		{name: "history", args: "[app]", summary: "List past launches and kills, oldest first; --since and --until pick a time span",
+			flags: func(o *options, fs *flag.FlagSet) {
+				fs.StringVar(&o.since, "since", "", "Only entries from this time on: 2h, 3d, today, yesterday 09:00 or 2006-01-02 15:04")
+				fs.StringVar(&o.until, "until", "", "Only entries before this time, in the same forms as --since")
+				o.jsonFlag(fs)
+			}, run: runHistory},
+		{name: "list", summary: "List the configured apps, aliases and groups",
+			flags: (*options).jsonFlag, run: runList},
+		{name: "which", args: "<alias|group>", summary: "Print the path an app launches from, or the members of a group",
+			flags: (*options).jsonFlag, run: runWhich},
+		{name: "search", args: "<text>", summary: "Find apps by name, alias, path or tag",
+			flags: (*options).jsonFlag, run: runSearch},
+		{name: "add", args: "<name> [path]", summary: "Add an app, finding its path among installed applications when not given",
+			flags: func(o *options, fs *flag.FlagSet) {
+				fs.StringVar(&o.aliases, "alias", "", "Comma separated aliases for the app, instead of asking")
+				fs.BoolVar(&o.yes, "yes", o.yes, "Take the installed application found without asking, and add no aliases unless --alias")
+			}, run: runAdd},
+		{name: "remove", args: "<app...>", summary: "Delete apps with their aliases and group references",
+			flags: func(o *options, fs *flag.FlagSet) {
+				fs.BoolVar(&o.yes, "yes", o.yes, "Remove without asking")
+			}, run: runRemove},
+		{name: "alias", args: "add <alias> <app> | rm <alias> | list", summary: "Add, remove or list the config's aliases",
+			flags: (*options).jsonFlag, run: runAlias},
+		{name: "group", args: "create <group> <app...> | add <group> <app...> | remove <group> <app...> | delete <group> | list", summary: "Create, change, delete or list workspace groups",
+			flags: (*options).jsonFlag, run: runGroup},
+		{name: "config", args: "path|edit", summary: "Print the config file's path, or open it in $EDITOR", run: runConfig},
+		{name: "edit", args: "<alias|group|project>", summary: "Open the config in $EDITOR at the entry of an app, group or project", run: runEdit},
+		{name: "discover", summary: "List the applications installed on this machine",
+			flags: (*options).jsonFlag, run: runDiscover},
+		{name: "hooks", args: "install-logout <group>", summary: "Close a group whenever you log out; uninstall-logout removes the hook", run: runHooks},
+		{name: "init", summary: "Create a starter config, or one from installed applications",
+			flags: func(o *options, fs *flag.FlagSet) {
+				fs.StringVar(&o.template, "template", o.template, "Starter template ("+strings.Join(core.TemplateNames(), "|")+")")
+				fs.BoolVar(&o.scan, "scan", false, "Build the config from applications installed on this machine")
+				fs.BoolVar(&o.overwrite, "force", false, "Overwrite an existing config")
+				fs.BoolVar(&o.yes, "yes", o.yes, "With --scan, write every application found without asking which to leave out")
+			}, noConfig: true, run: runInit},
+		{name: "completion", args: "bash|zsh|fish|powershell", summary: "Print the shell completion script",
+			noConfig: true, run: runCompletion},
+		{name: "shellenv", args: "bash|zsh|fish|powershell", summary: "Print shell aliases launching each app and group, with kill-<name> functions, to eval",
+			run: runShellenv},
+		{name: completeCommand, summary: "Complete the words of an openx command line, for the completion scripts",
+			noConfig: true, raw: true, hidden: true, run: runComplete},
+		{name: "version", summary: "Print the version, commit, build date and Go version",
+			flags: (*options).jsonFlag, noConfig: true, run: runVersion},
+		{name: "gen-docs", args: "<dir>", summary: "Write man pages and markdown docs of every command to a directory, for packaging",
+			noConfig: true, run: runGenDocs},
+		{name: "help", args: "[command]", summary: "Show the commands, or the options of one", noConfig: true, run: runHelp},
+	}
+}
+
+// lookupCommand finds a subcommand by name
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// flagSet returns the command's flag set, printing its own usage
+func (c command) flagSet(o *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+	if c.flags != nil {
+		c.flags(o, fs)
+	}
+	o.colorFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: openx %s\n\n%s\n", strings.TrimSpace(c.name+" [options] "+c.args), c.summary)
+		if c.flags != nil {
+			fmt.Fprintf(os.Stderr, "\nOptions:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// execute parses the command's options and runs it
+func (c command) execute(ox *lib.OpenX, o *options, args []string) {
+	args = c.parse(o, args)
+	if o.noColor {
+		ox.SetColor(false)
+	}
+	c.run(ox, o, args)
+}
+
+// parse parses the command's options and returns its arguments. Options
+// may follow the arguments, as in `openx kill chrome --force`, except
+// after the first argument of a passArgs command; "--" ends them.
+func (c command) parse(o *options, args []string) []string {
+	if c.raw {
+		return args
+	}
+	fs := c.flagSet(o)
+	var positional []string
+	for {
+		fs.Parse(args)
+		rest := fs.Args()
+		consumed := args[:len(args)-len(rest)]
+		if len(rest) == 0 || c.passArgs || (len(consumed) > 0 && consumed[len(consumed)-1] == "--") {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// runRun handles the run subcommand
+func runRun(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx run [options] <alias|group> [args...]\n")
+		os.Exit(exitUsage)
+	}
+	runLaunch(ox, o, args)
+}
+
+// killCommandFlags registers the options of the kill subcommand, which
+// also selects the apps to close the way --kill-all and friends do
+func killCommandFlags(o *options, fs *flag.FlagSet) {
+	o.killFlags(fs)
+	fs.BoolVar(&o.wait, "wait", o.wait, "Return once no matching processes remain")
+	fs.StringVar(&o.tag, "tag", o.tag, "Close every application with this tag")
+	fs.BoolVar(&o.all, "all", o.all, "Close every configured application that is running")
+	fs.BoolVar(&o.session, "session", o.session, "Close every application openx launched since the last kill --session")
+	fs.BoolVar(&o.idle, "idle", o.idle, "Close applications whose processes used next to no CPU for --threshold")
+	fs.DurationVar(&o.threshold, "threshold", o.threshold, "How long an application must be idle for --idle")
+	o.jsonFlag(fs)
+}
+
+// runKill closes the named apps and groups, or the apps --all, --session,
+// --idle or --tag pick
+func runKill(ox *lib.OpenX, o *options, names []string) {
+	killOpts := o.killOptions()
+	quietForJSON(ox, o)
+	// cat apps.txt | openx kill - closes the apps and groups listed
+	if len(names) == 1 && names[0] == "-" {
+		names = stdinNames("openx kill")
+	}
+	switch {
+	case o.all:
+		results, err := ox.KillAll(killOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error killing apps: %v\n", err)
+		}
+		printKillResults(o, results)
+		exitWith(killExitCode(results, err))
+		return
+	case o.session:
+		if o.json {
+			fmt.Fprintf(os.Stderr, "Error: --json can't be combined with --session\n")
+			os.Exit(exitUsage)
+		}
+		if err := ox.KillSession(killOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error killing session: %v\n", err)
+			os.Exit(exitCode(err, exitError))
+		}
+		return
+	case o.idle:
+		results, err := ox.KillIdle(o.threshold, killOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error killing idle apps: %v\n", err)
+		}
+		printKillResults(o, results)
+		exitWith(killExitCode(results, err))
+		return
+	case o.tag != "":
+		results, err := ox.KillTag(o.tag, killOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing apps tagged %s: %v\n", o.tag, err)
+		}
+		printKillResults(o, results)
+		exitWith(killExitCode(results, err))
+		return
+	case len(names) == 0:
+		fmt.Fprintf(os.Stderr, "Usage: openx kill [options] <alias|group>... | --tag name | --all | --session | --idle\n")
+		os.Exit(exitUsage)
+	}
+
+	var all []core.CloseResult
+	code := exitOK
+	var apps []string
+	for _, alias := range names {
+		// Apps win over groups of the same name, as when launching
+		if !isValidAlias(alias) && ox.IsGroup(alias) {
+			results, err := ox.KillGroup(alias, killOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error closing group %s: %v\n", alias, err)
+				code = cmp.Or(code, killExitCode(results, err))
+			}
+			all = append(all, results...)
+			continue
+		}
+		apps = append(apps, alias)
+	}
+
+	// Several apps close side by side, each printing its own errors
+	if len(apps) == 1 {
+		report, err := ox.KillWithOptions(apps[0], killOpts)
+		if errors.Is(err, core.ErrKillNoMatch) {
+			err = nil // nothing killed has its own exit code
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error killing %s: %v\n", apps[0], err)
+		}
+		all = append(all, core.CloseResult{Alias: apps[0], Running: report.Killed(), Report: report, Err: err})
+		if err != nil {
+			code = cmp.Or(code, exitCode(err, exitError))
+		}
+	} else if len(apps) > 1 {
+		results, err := ox.KillApps(apps, killOpts)
+		if err != nil {
+			code = cmp.Or(code, killExitCode(results, err))
+		}
+		all = append(all, results...)
+	}
+	printKillResults(o, all)
+	exitWith(cmp.Or(code, killExitCode(all, nil)))
+}
+
+// printKillResults prints what kill closed as JSON with --json: one
+// core.CloseResult per app, with its kill report and any error
+func printKillResults(o *options, results []core.CloseResult) {
+	if !o.json {
+		return
+	}
+	if results == nil {
+		results = []core.CloseResult{}
+	}
+	printJSON(results)
+}
+
+// runDoctor checks the configured apps, repairing paths first with --fix
+func runDoctor(ox *lib.OpenX, o *options, args []string) {
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: doctor takes no arguments, got %v\n", args)
+		os.Exit(exitUsage)
+	}
+	if o.fix {
+		if err := ox.DoctorFix(o.yes); err != nil {
+			fmt.Fprintf(os.Stderr, "Doctor fix failed: %v\n", err)
+			os.Exit(exitCode(err, exitError))
+		}
+		fmt.Println()
+	}
+
+	format := o.format
+	if format == "" && o.json {
+		format = "json"
+	}
+	var err error
+	switch {
+	case o.diff:
+		err = ox.DoctorDiff()
+	case o.watch && o.quiet:
+		err = ox.DoctorWatchProblems(format, o.interval)
+	case o.watch:
+		err = ox.DoctorWatch(format, o.interval)
+	case o.quiet:
+		err = ox.DoctorProblems(format)
+	default:
+		err = ox.DoctorFormat(format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Doctor check failed: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runOpen handles the open subcommand. --with may come before or after
+// the target.
+func runOpen(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx open <file|url> [--with alias]\n")
+		os.Exit(exitUsage)
+	}
+	target := args[0]
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Error: open takes a single target, got extra arguments %v\n", args[1:])
+		os.Exit(exitUsage)
+	}
+
+	if err := ox.Open(target, o.with); err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", target, err)
+		os.Exit(exitCode(err, exitLaunchFailed))
+	}
+}
+
+// runStatus shows the state of the environment at a glance
+func runStatus(ox *lib.OpenX, o *options, args []string) {
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: status takes no arguments, got %v\n", args)
+		os.Exit(exitUsage)
+	}
+	if err := ox.Status(o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runPS lists the configured apps with their running status
+func runPS(ox *lib.OpenX, o *options, args []string) {
+	var err error
+	if o.json {
+		err = ox.PSJSON(o.stats)
+	} else {
+		err = ox.PS(o.stats)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing apps: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runRecent lists the latest distinct launches
+func runRecent(ox *lib.OpenX, o *options, args []string) {
+	if err := ox.Recent(o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing recent launches: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runHistory lists the launches and kills, of one app when given
+func runHistory(ox *lib.OpenX, o *options, args []string) {
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx history [--since when] [--until when] [--json] [app]\n")
+		os.Exit(exitUsage)
+	}
+	var filter core.HistoryFilter
+	if len(args) == 1 {
+		filter.App = args[0]
+	}
+	now := time.Now()
+	var err error
+	if o.since != "" {
+		if filter.Since, err = core.ParseHistoryTime(o.since, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+	if o.until != "" {
+		if filter.Until, err = core.ParseHistoryTime(o.until, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --until: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	if err := ox.History(filter, o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing history: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runList lists the configured apps, aliases and groups
+func runList(ox *lib.OpenX, o *options, args []string) {
+	if err := ox.List(o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing apps: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runWhich prints what a name launches
+func runWhich(ox *lib.OpenX, o *options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx which [--json] <alias|group>\n")
+		os.Exit(exitUsage)
+	}
+	if err := ox.Which(args[0], o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runSearch prints the apps matching the text
+func runSearch(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx search [--json] <text>\n")
+		os.Exit(exitUsage)
+	}
+	if err := ox.Search(strings.Join(args, " "), o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error searching apps: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runAdd adds an app to the config
+func runAdd(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "Usage: openx add [--alias a,b] [--yes] <name> [path]\n")
+		os.Exit(exitUsage)
+	}
+	opts := core.AddAppOptions{Yes: o.yes}
+	if len(args) == 2 {
+		opts.Path = args[1]
+	}
+	if o.aliases != "" {
+		opts.Aliases = strings.Split(o.aliases, ",")
+	}
+	if err := ox.AddApp(args[0], opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error adding %s: %v\n", args[0], err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runRemove deletes each named app from the config
+func runRemove(ox *lib.OpenX, o *options, names []string) {
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx remove [--yes] <app>...\n")
+		os.Exit(exitUsage)
+	}
+	for _, name := range names {
+		if err := ox.RemoveApp(name, o.yes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error removing %s: %v\n", name, err)
+			os.Exit(exitCode(err, exitError))
+		}
+	}
+}
+
+// runAlias adds, removes or lists the aliases in the config
+func runAlias(ox *lib.OpenX, o *options, args []string) {
+	var err error
+	switch {
+	case len(args) == 3 && args[0] == "add":
+		if err = ox.AddAlias(args[1], args[2]); err == nil {
+			aliases, _ := ox.ListAliases()
+			alias := strings.ToLower(args[1])
+			fmt.Printf("Added alias %s → %s\n", alias, aliases[alias])
+		}
+	case len(args) == 2 && (args[0] == "rm" || args[0] == "remove"):
+		if err = ox.RemoveAlias(args[1]); err == nil {
+			fmt.Printf("Removed alias %s\n", strings.ToLower(args[1]))
+		}
+	case len(args) == 1 && args[0] == "list":
+		var aliases map[string]string
+		if aliases, err = ox.ListAliases(); err == nil {
+			printAliases(aliases, o.json)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: openx alias add <alias> <app> | rm <alias> | list [--json]\n")
+		os.Exit(exitUsage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runGroup creates, changes, deletes or lists the groups in the config
+func runGroup(ox *lib.OpenX, o *options, args []string) {
+	var err error
+	switch {
+	case len(args) >= 3 && args[0] == "create":
+		if err = ox.CreateGroup(args[1], args[2:]...); err == nil {
+			printGroup(ox, "Created group", args[1])
+		}
+	case len(args) >= 3 && args[0] == "add":
+		if err = ox.AddGroupMembers(args[1], args[2:]...); err == nil {
+			printGroup(ox, "Updated group", args[1])
+		}
+	case len(args) >= 3 && (args[0] == "remove" || args[0] == "rm"):
+		if err = ox.RemoveGroupMembers(args[1], args[2:]...); err == nil {
+			printGroup(ox, "Updated group", args[1])
+		}
+	case len(args) == 2 && args[0] == "delete":
+		if err = ox.DeleteGroup(args[1]); err == nil {
+			fmt.Printf("Deleted group %s\n", strings.ToLower(args[1]))
+		}
+	case len(args) == 1 && args[0] == "list":
+		var groups map[string][]string
+		if groups, err = ox.ListGroups(); err == nil {
+			printGroups(groups, o.json)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: openx group create <group> <app...> | add <group> <app...> | remove <group> <app...> | delete <group> | list [--json]\n")
+		os.Exit(exitUsage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// printGroup prints a group's members after a change, or that removing
+// its last members deleted it
+func printGroup(ox *lib.OpenX, action, name string) {
+	name = strings.ToLower(name)
+	groups, _ := ox.ListGroups()
+	members, exists := groups[name]
+	if !exists {
+		fmt.Printf("Deleted group %s, it has no apps left\n", name)
+		return
+	}
+	fmt.Printf("%s %s: %s\n", action, name, strings.Join(members, ", "))
+}
+
+// printGroups prints groups sorted, as group: apps lines or JSON
+func printGroups(groups map[string][]string, asJSON bool) {
+	if asJSON {
+		data, _ := json.MarshalIndent(groups, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(groups) == 0 {
+		fmt.Println("No groups configured")
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		fmt.Printf("%s: %s\n", name, strings.Join(groups[name], ", "))
+	}
+}
+
+// printAliases prints aliases sorted, as alias → app lines or JSON
+func printAliases(aliases map[string]string, asJSON bool) {
+	if asJSON {
+		data, _ := json.MarshalIndent(aliases, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(aliases) == 0 {
+		fmt.Println("No aliases configured")
+		return
+	}
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		fmt.Printf("%s → %s\n", alias, aliases[alias])
+	}
+}
+
+// runConfig prints the config file's path, or opens it in $VISUAL or
+// $EDITOR, the system's default editor when neither is set
+func runConfig(ox *lib.OpenX, o *options, args []string) {
+	path := ox.ConfigPath()
+	switch {
+	case len(args) == 1 && args[0] == "path":
+		fmt.Println(path)
+	case len(args) == 1 && args[0] == "edit":
+		editConfig(ox, path, 0)
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: openx config path|edit\n")
+		os.Exit(exitUsage)
+	}
+}
+
+// runEdit opens the config in $VISUAL or $EDITOR at the entry of an app,
+// group or project
+func runEdit(ox *lib.OpenX, o *options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx edit <alias|group|project>\n")
+		os.Exit(exitUsage)
+	}
+	line, err := ox.ConfigEntryLine(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+	editConfig(ox, ox.ConfigPath(), line)
+}
+
+// editConfig opens the config file in $VISUAL or $EDITOR at line, or the
+// whole file when line is 0. Without either the system's default editor
+// opens it, at the top.
+func editConfig(ox *lib.OpenX, path string, line int) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	var err error
+	if editor == "" {
+		if line > 0 {
+			fmt.Printf("%s:%d\n", path, line)
+		}
+		err = ox.Open(path, "")
+	} else {
+		// The editor may come with options, as in "code --wait"
+		fields := strings.Fields(editor)
+		cmd := exec.Command(fields[0], append(fields[1:], editorArgs(fields[0], path, line)...)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err = cmd.Run()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error editing %s: %v\n", path, err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// editorArgs returns the arguments opening path at line in editor. Most
+// terminal editors take +line; the GUI ones have their own ways.
+func editorArgs(editor, path string, line int) []string {
+	if line <= 0 {
+		return []string{path}
+	}
+	// The editor may be a path, with / or \ as on Windows
+	name := strings.ToLower(editor[strings.LastIndexAny(editor, `/\`)+1:])
+	name = strings.TrimSuffix(name, ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		return []string{"--goto", fmt.Sprintf("%s:%d", path, line)}
+	case "subl", "zed", "hx", "helix", "atom":
+		return []string{fmt.Sprintf("%s:%d", path, line)}
+	case "idea", "goland", "pycharm", "webstorm", "clion", "rider", "rubymine", "phpstorm", "studio":
+		return []string{"--line", fmt.Sprint(line), path}
+	case "notepad++":
+		return []string{fmt.Sprintf("-n%d", line), path}
+	case "notepad":
+		return []string{path}
+	}
+	return []string{fmt.Sprintf("+%d", line), path}
+}
+
+// runDiscover lists the applications installed on this machine
+func runDiscover(ox *lib.OpenX, o *options, args []string) {
+	if err := ox.Discover(o.json); err != nil {
+		fmt.Fprintf(os.Stderr, "Error discovering apps: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runHooks installs or removes the hook closing a group at logout
+func runHooks(ox *lib.OpenX, o *options, args []string) {
+	var err error
+	switch {
+	case len(args) == 2 && args[0] == "install-logout":
+		err = ox.InstallLogoutHook(args[1])
+	case len(args) == 1 && args[0] == "uninstall-logout":
+		err = ox.UninstallLogoutHook()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: openx hooks install-logout <group> | uninstall-logout\n")
+		os.Exit(exitUsage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runKillTest shows what kill would stop for each alias
+func runKillTest(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx kill-test [--json] <alias>...\n")
+		os.Exit(exitUsage)
+	}
+	for _, alias := range args {
+		if err := ox.KillTest(alias, o.json); err != nil {
+			fmt.Fprintf(os.Stderr, "Error testing %s: %v\n", alias, err)
+			os.Exit(exitCode(err, exitError))
+		}
+	}
+}
+
+// runRestart restarts each named app or group in turn
+func runRestart(ox *lib.OpenX, o *options, names []string) {
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: openx restart <alias|group>...\n")
+		os.Exit(exitUsage)
+	}
+
+	opts := o.killOptions()
+	for _, name := range names {
+		// Apps win over groups of the same name, as when launching
+		if !isValidAlias(name) && ox.IsGroup(name) {
+			if _, err := ox.RestartGroup(name, opts, o.jobs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error restarting group %s: %v\n", name, err)
+				os.Exit(exitCode(err, exitError))
+			}
+			continue
+		}
+		if err := ox.Restart(name, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error restarting %s: %v\n", name, err)
+			os.Exit(exitCode(err, exitError))
+		}
+	}
+}
+
+// runProject opens a project, or lists projects when no name is given
+func runProject(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		names, err := ox.ListProjects()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing projects: %v\n", err)
+			os.Exit(exitCode(err, exitError))
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	if err := ox.RunProject(args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening project %s: %v\n", args[0], err)
+		os.Exit(exitCode(err, exitLaunchFailed))
+	}
+}
+
+// runInit handles the init subcommand
+func runInit(ox *lib.OpenX, o *options, args []string) {
+	var err error
+	if o.scan {
+		err = ox.InitScannedConfig(o.overwrite, o.yes)
+	} else {
+		err = ox.InitConfig(o.template, o.overwrite)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+}
+
+// runHelp prints the usage of openx, or of one command
+func runHelp(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		flag.Usage()
+		return
+	}
+	cmd, ok := lookupCommand(args[0])
+	if !ok || cmd.hidden {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n", args[0])
+		os.Exit(exitUsage)
+	}
+	cmd.flagSet(o).Usage()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCommandParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		args     []string
+		wantArgs []string
+		check    func(o *options) bool
+	}{
+		{
+			name:     "options after the arguments",
+			command:  "kill",
+			args:     []string{"chrome", "--force", "slack", "--kill-timeout", "20s"},
+			wantArgs: []string{"chrome", "slack"},
+			check:    func(o *options) bool { return o.force && o.timeout == 20*time.Second },
+		},
+		{
+			name:     "double dash ends the options",
+			command:  "kill",
+			args:     []string{"--yes", "--", "--force"},
+			wantArgs: []string{"--force"},
+			check:    func(o *options) bool { return o.yes && !o.force },
+		},
+		{
+			name:     "run passes the app's options through",
+			command:  "run",
+			args:     []string{"--wait", "vim", "--clean", "notes.md"},
+			wantArgs: []string{"vim", "--clean", "notes.md"},
+			check:    func(o *options) bool { return o.wait },
+		},
+		{
+			name:     "open with after the target",
+			command:  "open",
+			args:     []string{"notes.md", "--with", "vim"},
+			wantArgs: []string{"notes.md"},
+			check:    func(o *options) bool { return o.with == "vim" },
+		},
+		{
+			name:     "options given before the command are the defaults",
+			command:  "ps",
+			args:     []string{"--stats"},
+			wantArgs: nil,
+			check:    func(o *options) bool { return o.json && o.stats },
+		},
+		{
+			name:     "no-color on a command with its own options",
+			command:  "ps",
+			args:     []string{"--no-color"},
+			wantArgs: nil,
+			check:    func(o *options) bool { return o.noColor },
+		},
+		{
+			name:     "no-color on search",
+			command:  "search",
+			args:     []string{"chrome", "--no-color"},
+			wantArgs: []string{"chrome"},
+			check:    func(o *options) bool { return o.noColor },
+		},
+		{
+			name:     "no-color on kill-test",
+			command:  "kill-test",
+			args:     []string{"--no-color", "chrome"},
+			wantArgs: []string{"chrome"},
+			check:    func(o *options) bool { return o.noColor },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := lookupCommand(tt.command)
+			if !ok {
+				t.Fatalf("lookupCommand(%q) found nothing", tt.command)
+			}
+			// As if --json came before the command
+			o := &options{json: true}
+			got := cmd.parse(o, tt.args)
+			if !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("parse(%v) = %q, want %q", tt.args, got, tt.wantArgs)
+			}
+			if !tt.check(o) {
+				t.Errorf("parse(%v) options = %+v", tt.args, *o)
+			}
+		})
+	}
+}
+
+func TestCommandsUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, cmd := range commands() {
+		if seen[cmd.name] {
+			t.Errorf("command %q is defined twice", cmd.name)
+		}
+		seen[cmd.name] = true
+		if cmd.run == nil {
+			t.Errorf("command %q has nothing to run", cmd.name)
+		}
+	}
+	if _, ok := lookupCommand("vscode"); ok {
+		t.Error("lookupCommand(vscode) found a command, aliases must fall through to run")
+	}
+}
+
+func TestEditorArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		want   []string
+	}{
+		{"vim", 12, []string{"+12", "config.yaml"}},
+		{"/usr/bin/nano", 12, []string{"+12", "config.yaml"}},
+		{"code", 12, []string{"--goto", "config.yaml:12"}},
+		{`C:\Tools\Cursor.exe`, 12, []string{"--goto", "config.yaml:12"}},
+		{"subl", 12, []string{"config.yaml:12"}},
+		{"idea", 12, []string{"--line", "12", "config.yaml"}},
+		{"vim", 0, []string{"config.yaml"}},
+	}
+	for _, tt := range tests {
+		if got := editorArgs(tt.editor, "config.yaml", tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("editorArgs(%q, %d) = %q, want %q", tt.editor, tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"openx/internal/core"
+	"openx/lib"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// completeCommand is the hidden command the completion scripts call with
+// the words typed after openx, the last one being completed
+const completeCommand = "__complete"
+
+// completionShells are the shells openx completion writes scripts for
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// runCompletion prints the completion script of a shell
+func runCompletion(ox *lib.OpenX, o *options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx completion %s\n", strings.Join(completionShells, "|"))
+		os.Exit(exitUsage)
+	}
+	script, ok := map[string]string{
+		"bash":       bashCompletion,
+		"zsh":        zshCompletion,
+		"fish":       fishCompletion,
+		"powershell": powershellCompletion,
+	}[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: no completion for %q (use %s)\n", args[0], strings.Join(completionShells, ", "))
+		os.Exit(exitUsage)
+	}
+	fmt.Print(strings.ReplaceAll(script, "%s", completeCommand))
+}
+
+// runComplete prints the candidates for the last of args, one per line.
+// Nothing printed has the shell complete file names instead.
+func runComplete(ox *lib.OpenX, o *options, args []string) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	names, err := ox.Completions()
+	if err != nil {
+		// A broken config still completes commands and flags
+		names = &core.Completions{}
+	}
+	for _, candidate := range completeWords(args, names) {
+		fmt.Println(candidate)
+	}
+}
+
+// completeWords completes the last of words, the words typed after openx:
+// commands, flags and their values, and the names each command takes
+func completeWords(words []string, names *core.Completions) []string {
+	current, typed := words[len(words)-1], words[:len(words)-1]
+
+	// The global flags may come before the command
+	global := flag.NewFlagSet("openx", flag.ContinueOnError)
+	o := newOptions()
+	o.globalFlags(global)
+	command, args, pending := splitFlags(global, typed)
+	if pending != nil {
+		return filterPrefix(flagValues(pending.Name, names), current)
+	}
+	global.SetOutput(io.Discard)
+	global.Parse(typed)
+
+	cmd, isCommand := lookupCommand(command)
+	if command != "" && isCommand && !cmd.hidden {
+		return completeCommandArgs(cmd, args, current, names)
+	}
+
+	switch {
+	case command != "":
+		// openx <alias> args... hands the args to the app; --kill takes
+		// more names
+		if o.killNames {
+			return filterPrefix(names.Names, current)
+		}
+		return nil
+	case strings.HasPrefix(current, "-"):
+		return filterPrefix(flagNames(global), current)
+	case o.killNames:
+		return filterPrefix(names.Names, current)
+	}
+
+	var candidates []string
+	for _, cmd := range commands() {
+		if !cmd.hidden {
+			candidates = append(candidates, cmd.name)
+		}
+	}
+	return filterPrefix(append(candidates, names.Names...), current)
+}
+
+// completeCommandArgs completes the word after a command and its args
+func completeCommandArgs(cmd command, typed []string, current string, names *core.Completions) []string {
+	fs := cmd.flagSet(newOptions())
+	var args []string
+	for len(typed) > 0 {
+		first, rest, pending := splitFlags(fs, typed)
+		if pending != nil {
+			return filterPrefix(flagValues(pending.Name, names), current)
+		}
+		if first == "" {
+			break
+		}
+		args = append(args, first)
+		typed = rest
+		if cmd.passArgs {
+			// Everything after run's alias belongs to the app
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		return filterPrefix(flagNames(fs), current)
+	}
+
+	var candidates []string
+	switch cmd.name {
+	case "run", "kill", "restart", "kill-test":
+		candidates = names.Names
+	case "edit":
+		if len(args) == 0 {
+			candidates = append(slices.Clone(names.Names), names.Projects...)
+		}
+	case "history", "which":
+		if len(args) == 0 {
+			candidates = names.Names
+		}
+	case "remove":
+		candidates = names.Apps
+	case "proj":
+		if len(args) == 0 {
+			candidates = names.Projects
+		}
+	case "alias":
+		switch {
+		case len(args) == 0:
+			candidates = []string{"add", "rm", "list"}
+		case len(args) == 1 && (args[0] == "rm" || args[0] == "remove"):
+			candidates = names.Aliases
+		case len(args) == 2 && args[0] == "add":
+			candidates = names.Names
+		}
+	case "group":
+		switch {
+		case len(args) == 0:
+			candidates = []string{"create", "add", "remove", "delete", "list"}
+		case len(args) == 1 && args[0] != "create" && args[0] != "list":
+			candidates = names.Groups
+		case len(args) >= 2 && args[0] != "delete" && args[0] != "list":
+			candidates = append(slices.Clone(names.Apps), names.Aliases...)
+		}
+	case "config":
+		if len(args) == 0 {
+			candidates = []string{"path", "edit"}
+		}
+	case "hooks":
+		switch {
+		case len(args) == 0:
+			candidates = []string{"install-logout", "uninstall-logout"}
+		case len(args) == 1 && args[0] == "install-logout":
+			candidates = names.Groups
+		}
+	case "completion", "shellenv":
+		if len(args) == 0 {
+			candidates = completionShells
+		}
+	case "help":
+		if len(args) == 0 {
+			for _, c := range commands() {
+				if !c.hidden {
+					candidates = append(candidates, c.name)
+				}
+			}
+		}
+	}
+	return filterPrefix(candidates, current)
+}
+
+// splitFlags skips the flags at the start of words, with the values of
+// those taking one, and returns the first other word and the words after
+// it. pending is the flag still waiting for its value when words run out.
+func splitFlags(fs *flag.FlagSet, words []string) (first string, rest []string, pending *flag.Flag) {
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			if i+1 < len(words) {
+				return words[i+1], words[i+2:], nil
+			}
+			return "", nil, nil
+		}
+		if !strings.HasPrefix(word, "-") || word == "-" {
+			return word, words[i+1:], nil
+		}
+
+		name := strings.TrimLeft(word, "-")
+		if strings.Contains(name, "=") {
+			// --format=json carries its value; so does the rest of this
+			// word when completing it
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil || isBoolFlag(f) {
+			continue
+		}
+		if i+1 == len(words) {
+			return "", nil, f
+		}
+		i++
+	}
+	return "", nil, nil
+}
+
+// isBoolFlag reports whether a flag takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// flagNames returns the flags of fs as --name
+func flagNames(fs *flag.FlagSet) []string {
+	var flags []string
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "--"+f.Name)
+	})
+	return flags
+}
+
+// flagValues returns the values a flag takes, when they are known
+func flagValues(name string, names *core.Completions) []string {
+	switch name {
+	case "format":
+		return []string{core.DoctorFormatHuman, core.DoctorFormatJSON, core.DoctorFormatYAML, core.DoctorFormatCSV, core.DoctorFormatMarkdown, core.DoctorFormatPrometheus}
+	case "template":
+		return core.TemplateNames()
+	case "tag":
+		return names.Tags
+	case "with":
+		return names.Names
+	case "signal":
+		return []string{"TERM", "INT", "HUP", "KILL"}
+	}
+	return nil
+}
+
+// filterPrefix returns the candidates starting with prefix, sorted
+func filterPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// The completion scripts hand every word after openx to openx __complete,
+// the word being completed last, even when empty. %s is the hidden command.
+
+const bashCompletion = `# openx completion for bash
+# Add to ~/.bashrc: source <(openx completion bash)
+_openx() {
+    local IFS=$'\n'
+    COMPREPLY=($(openx %s "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _openx openx
+`
+
+const zshCompletion = `#compdef openx
+# openx completion for zsh
+# Add to ~/.zshrc after compinit: source <(openx completion zsh)
+_openx() {
+    local -a candidates
+    candidates=("${(@f)$(openx %s "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    if [[ -n "${candidates[1]}" ]]; then
+        compadd -a candidates
+    else
+        _files
+    fi
+}
+compdef _openx openx
+`
+
+const fishCompletion = `# openx completion for fish
+# Save as ~/.config/fish/completions/openx.fish: openx completion fish > ~/.config/fish/completions/openx.fish
+function __openx_complete
+    set -l words (commandline -opc)
+    openx %s $words[2..-1] (commandline -ct | string collect --allow-empty) 2>/dev/null
+end
+function __openx_has_candidates
+    test (count (__openx_complete)) -gt 0
+end
+complete -c openx -n __openx_has_candidates -f -a '(__openx_complete)'
+`
+
+const powershellCompletion = `# openx completion for PowerShell
+# Add to $PROFILE: openx completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName openx -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -le $cursorPosition } | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') {
+        # Windows PowerShell drops empty arguments to native commands
+        $words += if ($PSVersionTable.PSVersion -ge [version]'7.3') { '' } else { '""' }
+    }
+    & openx %s @words 2>$null | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"openx/internal/core"
+)
+
+func TestCompleteWords(t *testing.T) {
+	names := &core.Completions{
+		Names:    []string{"chrome", "pm", "postman", "web", "work"},
+		Apps:     []string{"chrome", "postman"},
+		Aliases:  []string{"web"},
+		Groups:   []string{"work"},
+		Projects: []string{"api"},
+		Tags:     []string{"browsers"},
+	}
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"app names and commands", []string{"po"}, []string{"postman"}},
+		{"commands by prefix", []string{"ki"}, []string{"kill", "kill-test"}},
+		{"kill takes names", []string{"kill", "chrome", "p"}, []string{"pm", "postman"}},
+		{"flag style kill", []string{"--kill", "chrome", "wo"}, []string{"work"}},
+		{"global flags before a command", []string{"--jobs", "8", "r"}, []string{"recent", "remove", "restart", "run"}},
+		{"run leaves the app's args to the shell", []string{"run", "postman", "p"}, nil},
+		{"launching leaves the args to the shell", []string{"postman", "p"}, nil},
+		{"command flags", []string{"doctor", "--q"}, []string{"--quiet"}},
+		{"flag values", []string{"doctor", "--format", "m"}, []string{"markdown"}},
+		{"tags", []string{"kill", "--tag", ""}, []string{"browsers"}},
+		{"projects", []string{"proj", ""}, []string{"api"}},
+		{"groups for the logout hook", []string{"hooks", "install-logout", ""}, []string{"work"}},
+		{"alias actions", []string{"alias", ""}, []string{"add", "list", "rm"}},
+		{"aliases to remove", []string{"alias", "rm", ""}, []string{"web"}},
+		{"group actions", []string{"group", ""}, []string{"add", "create", "delete", "list", "remove"}},
+		{"groups to add to", []string{"group", "add", ""}, []string{"work"}},
+		{"apps to add to a group", []string{"group", "add", "work", ""}, []string{"chrome", "postman", "web"}},
+		{"apps an alias points at", []string{"alias", "add", "goo", "p"}, []string{"pm", "postman"}},
+		{"hidden commands stay hidden", []string{"__"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completeWords(tt.words, names); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completeWords(%q) = %q, want %q", tt.words, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"openx/lib"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docPage is openx or one of its commands, as the generated docs describe it
+type docPage struct {
+	name     string // openx, or openx-<command> for a command
+	synopsis string
+	summary  string
+	options  []docOption
+}
+
+// docOption is one option of a docPage
+type docOption struct {
+	name, arg, usage, value string // value is the default, empty when it's the zero value
+}
+
+// runGenDocs writes man pages to <dir>/man and markdown to <dir>/markdown,
+// one page for openx and one per command
+func runGenDocs(ox *lib.OpenX, o *options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx gen-docs <dir>\n")
+		os.Exit(exitUsage)
+	}
+	if err := genDocs(args[0], docVersion()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating docs: %v\n", err)
+		os.Exit(exitCode(err, exitError))
+	}
+	fmt.Printf("Wrote man pages to %s and markdown to %s\n", filepath.Join(args[0], "man"), filepath.Join(args[0], "markdown"))
+}
+
+// docVersion is the version the generated docs name
+func docVersion() string {
+	if version != "" {
+		return version
+	}
+	return lib.GetVersion()
+}
+
+// genDocs writes the man and markdown pages of openx and its commands
+func genDocs(dir, release string) error {
+	manDir, markdownDir := filepath.Join(dir, "man"), filepath.Join(dir, "markdown")
+	for _, d := range []string{manDir, markdownDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return err
+		}
+	}
+
+	root, pages := docPages()
+	for _, page := range append([]docPage{root}, pages...) {
+		if err := writeDoc(filepath.Join(manDir, page.name+".1"), func(w io.Writer) { writeManPage(w, page, root, pages, release) }); err != nil {
+			return err
+		}
+		if err := writeDoc(filepath.Join(markdownDir, page.name+".md"), func(w io.Writer) { writeMarkdownPage(w, page, root, pages) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDoc creates path with what write writes
+func writeDoc(path string, write func(w io.Writer)) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	write(f)
+	return f.Close()
+}
+
+// docPages describes openx and its visible commands from the command table
+func docPages() (docPage, []docPage) {
+	global := flag.NewFlagSet("openx", flag.ContinueOnError)
+	newOptions().globalFlags(global)
+	root := docPage{
+		name:     "openx",
+		synopsis: "openx [options] <command> [args...]\nopenx [options] <alias|group> [args...]",
+		summary:  "Developer environment control tool: launch, close and check the apps, groups and projects in the config",
+		options:  docOptions(global),
+	}
+
+	var pages []docPage
+	for _, cmd := range commands() {
+		if cmd.hidden {
+			continue
+		}
+		page := docPage{name: "openx-" + cmd.name, synopsis: "openx " + cmd.name, summary: cmd.summary}
+		if cmd.flags != nil {
+			page.options = docOptions(cmd.flagSet(newOptions()))
+			page.synopsis += " [options]"
+		}
+		page.synopsis = strings.TrimSpace(page.synopsis + " " + cmd.args)
+		pages = append(pages, page)
+	}
+	return root, pages
+}
+
+// docOptions lists the options of fs in name order
+func docOptions(fs *flag.FlagSet) []docOption {
+	var options []docOption
+	fs.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		option := docOption{name: f.Name, arg: arg, usage: usage}
+		switch f.DefValue {
+		case "", "false", "0", "0s":
+		default:
+			option.value = f.DefValue
+		}
+		options = append(options, option)
+	})
+	return options
+}
+
+// flagName returns how an option is typed: -v for one letter, else --name
+func (opt docOption) flagName() string {
+	if len(opt.name) == 1 {
+		return "-" + opt.name
+	}
+	return "--" + opt.name
+}
+
+// writeManPage writes page as a man(1) page
+func writeManPage(w io.Writer, page, root docPage, pages []docPage, release string) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"openx %s\" \"User Commands\"\n", strings.ToUpper(page.name), roff(release))
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", roff(page.name), roff(page.summary))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n")
+	for _, line := range strings.Split(page.synopsis, "\n") {
+		fmt.Fprintf(w, ".B %s\n.br\n", roff(line))
+	}
+	if page.name == root.name {
+		fmt.Fprintf(w, ".SH DESCRIPTION\nAnything other than a command launches an app or group, as\n.B openx run\ndoes. With no arguments on a terminal, openx lets you search for one to launch or kill.\n")
+		fmt.Fprintf(w, ".SH COMMANDS\n")
+		for _, p := range pages {
+			fmt.Fprintf(w, ".TP\n.B %s\n%s\n", roff(strings.TrimPrefix(p.name, "openx-")), roff(p.summary))
+		}
+	} else {
+		fmt.Fprintf(w, ".SH DESCRIPTION\n%s.\n", roff(page.summary))
+	}
+	if len(page.options) > 0 {
+		fmt.Fprintf(w, ".SH OPTIONS\n")
+		for _, opt := range page.options {
+			fmt.Fprintf(w, ".TP\n.B %s", roff(opt.flagName()))
+			if opt.arg != "" {
+				fmt.Fprintf(w, " \\fI%s\\fR", roff(opt.arg))
+			}
+			fmt.Fprintf(w, "\n%s", roff(opt.usage))
+			if opt.value != "" {
+				fmt.Fprintf(w, " (default %s)", roff(opt.value))
+			}
+			fmt.Fprintf(w, "\n")
+		}
+	}
+	if page.name == root.name {
+		fmt.Fprintf(w, ".SH EXIT STATUS\nWith \\-\\-wait, the launched app's own exit code. Otherwise:\n")
+		for _, status := range exitStatuses {
+			fmt.Fprintf(w, ".TP\n.B %d\n%s\n", status.code, roff(status.meaning))
+		}
+	}
+	fmt.Fprintf(w, ".SH SEE ALSO\n")
+	if page.name == root.name {
+		var refs []string
+		for _, p := range pages {
+			refs = append(refs, fmt.Sprintf(".BR %s (1)", roff(p.name)))
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(refs, ",\n"))
+	} else {
+		fmt.Fprintf(w, ".BR openx (1)\n")
+	}
+}
+
+// roff escapes text for a man page line
+func roff(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// writeMarkdownPage writes page as markdown, linking the other pages
+func writeMarkdownPage(w io.Writer, page, root docPage, pages []docPage) {
+	fmt.Fprintf(w, "# %s\n\n%s\n\n", page.name, page.summary)
+	fmt.Fprintf(w, "## Synopsis\n\n```\n%s\n```\n", page.synopsis)
+	if page.name == root.name {
+		fmt.Fprintf(w, "\nAnything other than a command launches an app or group, as `openx run` does. With no arguments on a terminal, openx lets you search for one to launch or kill.\n")
+		fmt.Fprintf(w, "\n## Commands\n\n| Command | Description |\n| --- | --- |\n")
+		for _, p := range pages {
+			fmt.Fprintf(w, "| [%s](%s.md) | %s |\n", strings.TrimPrefix(p.name, "openx-"), p.name, markdownCell(p.summary))
+		}
+	}
+	if len(page.options) > 0 {
+		fmt.Fprintf(w, "\n## Options\n\n| Option | Description |\n| --- | --- |\n")
+		for _, opt := range page.options {
+			name := "`" + opt.flagName()
+			if opt.arg != "" {
+				name += " " + opt.arg
+			}
+			name += "`"
+			usage := opt.usage
+			if opt.value != "" {
+				usage += " (default `" + opt.value + "`)"
+			}
+			fmt.Fprintf(w, "| %s | %s |\n", name, markdownCell(usage))
+		}
+	}
+	if page.name == root.name {
+		fmt.Fprintf(w, "\n## Exit status\n\nWith `--wait`, the launched app's own exit code. Otherwise:\n\n| Code | Meaning |\n| --- | --- |\n")
+		for _, status := range exitStatuses {
+			fmt.Fprintf(w, "| %d | %s |\n", status.code, markdownCell(status.meaning))
+		}
+	} else {
+		fmt.Fprintf(w, "\n## See also\n\n[openx](openx.md)\n")
+	}
+}
+
+// markdownCell escapes text for a markdown table cell
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenDocs(t *testing.T) {
+	dir := t.TempDir()
+	if err := genDocs(dir, "1.4.0"); err != nil {
+		t.Fatalf("genDocs() unexpected error: %v", err)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Fatalf("genDocs() didn't write %s: %v", path, err)
+		}
+		return string(data)
+	}
+
+	// Every visible command gets its pages and a line in the main ones
+	man, markdown := read("man/openx.1"), read("markdown/openx.md")
+	for _, cmd := range commands() {
+		page := "openx-" + cmd.name
+		_, err := os.Stat(filepath.Join(dir, "man", page+".1"))
+		if cmd.hidden {
+			if err == nil {
+				t.Errorf("genDocs() wrote a man page for hidden command %s", cmd.name)
+			}
+			continue
+		}
+		read("markdown/" + page + ".md")
+		if !strings.Contains(man, ".BR "+roff(page)+" (1)") {
+			t.Errorf("openx.1 doesn't refer to %s(1)", page)
+		}
+		if !strings.Contains(markdown, "("+page+".md)") {
+			t.Errorf("openx.md doesn't link %s.md", page)
+		}
+	}
+
+	kill := read("man/openx-kill.1")
+	for _, want := range []string{
+		`.TH OPENX-KILL 1 "" "openx 1.4.0" "User Commands"`,
+		`.B openx kill [options] [alias|group...]`,
+		".B \\-\\-threshold \\fIduration\\fR\nHow long an application must be idle for \\-\\-idle (default 30m0s)",
+	} {
+		if !strings.Contains(kill, want) {
+			t.Errorf("openx-kill.1 lacks %q:\n%s", want, kill)
+		}
+	}
+	if want := "| `--signal string` | Signal --kill sends: TERM, INT, HUP or KILL |"; !strings.Contains(read("markdown/openx-kill.md"), want) {
+		t.Errorf("openx-kill.md lacks %q", want)
+	}
+}
+
+func TestRoff(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"--kill-timeout", `\-\-kill\-timeout`},
+		{`C:\Program Files`, `C:\eProgram Files`},
+		{".hidden starts a request", `\&.hidden starts a request`},
+	}
+	for _, tt := range tests {
+		if got := roff(tt.in); got != tt.want {
+			t.Errorf("roff(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
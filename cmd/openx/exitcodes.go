@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"openx/internal/core"
+)
+
+// Exit codes openx returns, stable for scripts to branch on. With --wait a
+// launched app's own exit code is returned instead.
+const (
+	exitOK            = 0
+	exitError         = 1 // any other failure
+	exitUsage         = 2 // bad command line, as the flag package reports too
+	exitConfig        = 3 // the config is missing, unreadable or invalid
+	exitUnknownName   = 4 // no app, alias, group or project of that name
+	exitLaunchFailed  = 5 // the app was found but didn't start
+	exitPartialGroup  = 6 // some members of a group failed, others didn't
+	exitNothingKilled = 7 // kill found nothing running to close
+)
+
+// exitStatuses describe the exit codes for the generated docs
+var exitStatuses = []struct {
+	code    int
+	meaning string
+}{
+	{exitOK, "Success"},
+	{exitError, "Any other failure"},
+	{exitUsage, "Bad command line: unknown command or option, missing arguments"},
+	{exitConfig, "The config file is missing, unreadable or invalid"},
+	{exitUnknownName, "No app, alias, group or project of that name"},
+	{exitLaunchFailed, "The app was found but failed to launch"},
+	{exitPartialGroup, "Some members of a group, or some of several apps, failed while others succeeded"},
+	{exitNothingKilled, "kill found nothing running to close"},
+}
+
+// exitCode returns the exit code for err: the config and unknown name
+// codes when err comes from those, else fallback
+func exitCode(err error, fallback int) int {
+	var unknown *core.UnknownNameError
+	var parseErr *core.ParseError
+	switch {
+	case errors.As(err, &unknown):
+		return exitUnknownName
+	case errors.Is(err, core.ErrConfigNotFound), errors.As(err, &parseErr):
+		return exitConfig
+	}
+	return fallback
+}
+
+// groupExitCode returns the exit code of a group launch or kill that
+// failed for failed of its total members: fallback when all failed
+func groupExitCode(failed, total, fallback int) int {
+	if failed > 0 && failed < total {
+		return exitPartialGroup
+	}
+	return fallback
+}
+
+// killExitCode returns the exit code of closing apps: exitNothingKilled
+// when none of them was running, and when some failed the code of their
+// failure, exitPartialGroup when others closed
+func killExitCode(results []core.CloseResult, err error) int {
+	if err == nil {
+		for _, result := range results {
+			if result.Running {
+				return exitOK
+			}
+		}
+		return exitNothingKilled
+	}
+
+	failed, code := 0, exitError
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			code = exitCode(result.Err, code)
+		}
+	}
+	return groupExitCode(failed, len(results), exitCode(err, code))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"openx/internal/core"
+)
+
+func TestExitCode(t *testing.T) {
+	unknown := &core.UnknownNameError{Kind: "app", Name: "chrm"}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unknown name", fmt.Errorf("failed: %w", unknown), exitUnknownName},
+		{"missing config", fmt.Errorf("failed to load config: %w", core.ErrConfigNotFound), exitConfig},
+		{"invalid config", fmt.Errorf("failed to load config: %w", &core.ParseError{Path: "config.yaml"}), exitConfig},
+		{"anything else", errors.New("no launch path configured"), exitLaunchFailed},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err, exitLaunchFailed); got != tt.want {
+			t.Errorf("exitCode(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKillExitCode(t *testing.T) {
+	failed := errors.New("processes still running")
+	unknown := &core.UnknownNameError{Kind: "app", Name: "chrm"}
+	tests := []struct {
+		name    string
+		results []core.CloseResult
+		err     error
+		want    int
+	}{
+		{"closed", []core.CloseResult{{Alias: "chrome", Running: true}, {Alias: "slack"}}, nil, exitOK},
+		{"nothing running", []core.CloseResult{{Alias: "chrome"}, {Alias: "slack"}}, nil, exitNothingKilled},
+		{"nothing to close", nil, nil, exitNothingKilled},
+		{"some failed", []core.CloseResult{{Alias: "chrome", Running: true}, {Alias: "slack", Err: failed}}, failed, exitPartialGroup},
+		{"all failed", []core.CloseResult{{Alias: "slack", Err: failed}}, failed, exitError},
+		{"unknown app", []core.CloseResult{{Alias: "chrm", Err: unknown}}, errors.New("1 apps failed to close"), exitUnknownName},
+		{"no results", nil, fmt.Errorf("failed to load config: %w", core.ErrConfigNotFound), exitConfig},
+	}
+	for _, tt := range tests {
+		if got := killExitCode(tt.results, tt.err); got != tt.want {
+			t.Errorf("killExitCode(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
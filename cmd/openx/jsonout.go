@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"openx/internal/core"
+	"openx/lib"
+	"os"
+)
+
+// groupLaunch is the --json output of launching a group
+type groupLaunch struct {
+	Group   string              `json:"group"`
+	Members []core.LaunchResult `json:"members"`
+}
+
+// quietForJSON silences progress lines such as "Launched:", which would
+// mix with the JSON on stdout
+func quietForJSON(ox *lib.OpenX, o *options) {
+	if o.json {
+		ox.SetLogLevel(slog.LevelWarn)
+	}
+}
+
+// printJSON prints v as indented JSON on stdout
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+	}
+}
@@ -1,109 +1,384 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"openx/internal/core"
 	"openx/lib"
 	"os"
-	"os/exec"
-	"runtime"
 	"strings"
+	"time"
 )
 
-func main() {
-	var (
-		killFlag   = flag.Bool("kill", false, "Kill the specified application(s)")
-		doctorFlag = flag.Bool("doctor", false, "Check health status of configured applications")
-		jsonFlag   = flag.Bool("json", false, "Output in JSON format (for doctor command)")
-	)
-
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] alias [args...]\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "openx - Developer environment control tool\n\n")
-		fmt.Fprintf(os.Stderr, "Commands:\n")
-		fmt.Fprintf(os.Stderr, "  openx alias [args...]     Launch single application by alias\n")
-		fmt.Fprintf(os.Stderr, "  openx --kill alias...     Kill application(s) by alias\n")
-		fmt.Fprintf(os.Stderr, "  openx --doctor [--json]   Check health of configured apps\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  openx code myproject/      # Launch VS Code with project\n")
-		fmt.Fprintf(os.Stderr, "  openx --kill chrome firefox # Kill Chrome and Firefox\n")
-		fmt.Fprintf(os.Stderr, "  openx --doctor --json      # Health check in JSON format\n")
-		fmt.Fprintf(os.Stderr, "\nLibrary version: %s\n", lib.GetVersion())
+// options holds every command line option. Subcommands register the
+// groups they take on their own flag set, defaulting to what was given
+// before the command, so `openx --json ps` keeps working.
+type options struct {
+	// Launching
+	wait, log, newInstance, admin, detach, attach, terminal, follow bool
+	check                                                           time.Duration
+	jobs                                                            int
+	with                                                            string // open --with
+
+	// Killing
+	yes, force         bool
+	signal, tag        string
+	timeout, threshold time.Duration
+	all, session, idle bool
+	killNames, doctor  bool // the flag-style --kill and --doctor
+
+	// Doctor
+	fix, watch, diff, quiet bool
+	format                  string
+	interval                time.Duration
+
+	// Output
+	json, stats     bool
+	verbose, silent bool // -v and -q
+	noColor         bool
+
+	// init
+	template        string
+	scan, overwrite bool
+
+	// add
+	aliases string
+
+	// history
+	since, until string
+}
+
+// launchFlags registers the options of launching an app or group
+func (o *options) launchFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.wait, "wait", o.wait, "Wait for the launched application to exit and return its exit code; with --kill, until no matching processes remain")
+	fs.BoolVar(&o.log, "log", o.log, "Capture the launched application's output in its log file")
+	fs.BoolVar(&o.newInstance, "new-instance", o.newInstance, "Open a new window/instance of single-instance apps")
+	fs.BoolVar(&o.admin, "admin", o.admin, "Launch the application with administrator privileges")
+	fs.BoolVar(&o.detach, "detach", o.detach, "Run the application in its own session so it survives the terminal closing")
+	fs.BoolVar(&o.attach, "attach", o.attach, "Keep the application tied to openx's terminal session")
+	fs.BoolVar(&o.terminal, "terminal", o.terminal, "Run a CLI tool inside a terminal emulator window")
+	fs.DurationVar(&o.check, "check", o.check, "Fail the launch if the application exits or shows no window within this time, e.g. 5s")
+	fs.BoolVar(&o.follow, "follow", o.follow, "Capture the application's output and print it until Ctrl-C, leaving the app running")
+	fs.IntVar(&o.jobs, "jobs", o.jobs, "Maximum number of group members launched at once")
+}
+
+// killFlags registers the options of closing apps
+func (o *options) killFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.yes, "yes", o.yes, "Kill without asking when a kill pattern matches unrelated processes")
+	fs.BoolVar(&o.force, "force", o.force, "Kill applications marked protected too")
+	fs.StringVar(&o.signal, "signal", o.signal, "Signal --kill sends: TERM, INT, HUP or KILL")
+	fs.DurationVar(&o.timeout, "kill-timeout", o.timeout, "How long --kill waits after a graceful quit before force killing (default 5s)")
+}
+
+// doctorFlags registers the options of the health check
+func (o *options) doctorFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.fix, "fix", o.fix, "With --doctor, find missing apps and rewrite their paths")
+	fs.StringVar(&o.format, "format", o.format, "Doctor report format: human, json, yaml, csv, markdown or prometheus")
+	fs.BoolVar(&o.watch, "watch", o.watch, "With --doctor, re-run the checks on an interval and on config changes")
+	fs.BoolVar(&o.diff, "diff", o.diff, "With --doctor, show what changed since the last doctor run")
+	fs.BoolVar(&o.quiet, "quiet", o.quiet, "With --doctor, list only missing, unconfigured and conflicting entries")
+	fs.DurationVar(&o.interval, "interval", o.interval, "How often --doctor --watch re-runs the checks")
+}
+
+// colorFlag registers --no-color, which every command takes
+func (o *options) colorFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.noColor, "no-color", o.noColor, "Print without colors, as when NO_COLOR is set or output isn't a terminal")
+}
+
+// jsonFlag registers --json
+func (o *options) jsonFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&o.json, "json", o.json, "Output in JSON format")
+}
+
+// newOptions returns the options' defaults
+func newOptions() *options {
+	return &options{jobs: 4, interval: 5 * time.Second, threshold: 30 * time.Minute, template: "default"}
+}
+
+// globalFlags registers the options taken before a command: every
+// option, plus the modes of the flag-style commands that predate
+// subcommands, kept for scripts using them
+func (o *options) globalFlags(fs *flag.FlagSet) {
+	o.launchFlags(fs)
+	o.killFlags(fs)
+	o.doctorFlags(fs)
+	o.colorFlag(fs)
+	fs.BoolVar(&o.json, "json", o.json, "Output in JSON format, for launching, kill, which, list, ps and doctor")
+	fs.BoolVar(&o.killNames, "kill", o.killNames, "Kill the specified application(s); same as openx kill")
+	fs.StringVar(&o.tag, "tag", o.tag, "Kill every application with this tag, with --kill")
+	fs.BoolVar(&o.all, "kill-all", o.all, "Kill every configured application that is running; same as openx kill --all")
+	fs.BoolVar(&o.session, "kill-session", o.session, "Kill every application openx launched since the last --kill-session")
+	fs.BoolVar(&o.idle, "kill-idle", o.idle, "Kill applications whose processes used next to no CPU for --threshold")
+	fs.DurationVar(&o.threshold, "threshold", o.threshold, "How long an application must be idle for --kill-idle")
+	fs.BoolVar(&o.doctor, "doctor", o.doctor, "Check health status of configured applications; same as openx doctor")
+	fs.BoolVar(&o.verbose, "v", o.verbose, "Show how names resolve and the commands openx runs")
+	fs.BoolVar(&o.verbose, "verbose", o.verbose, "Same as -v")
+	fs.BoolVar(&o.silent, "q", o.silent, "Print only results, warnings and errors, not progress such as \"Launched:\"")
+}
+
+// launchOptions returns the options of launching an app for core,
+// exiting on options that can't be combined
+func (o *options) launchOptions() core.LaunchOptions {
+	opts := core.LaunchOptions{Wait: o.wait, Log: o.log, NewInstance: o.newInstance, Elevated: o.admin, Terminal: o.terminal, Follow: o.follow, StartupCheck: o.check}
+	if o.follow && o.wait {
+		fmt.Fprintf(os.Stderr, "Error: --follow and --wait can't be combined\n")
+		os.Exit(exitUsage)
 	}
+	switch {
+	case o.detach && o.attach:
+		fmt.Fprintf(os.Stderr, "Error: --detach and --attach can't be combined\n")
+		os.Exit(exitUsage)
+	case o.detach:
+		opts.Mode = core.LaunchModeDetached
+	case o.attach:
+		opts.Mode = core.LaunchModeAttached
+	}
+	return opts
+}
+
+// killOptions returns the options of closing apps for core
+func (o *options) killOptions() core.KillOptions {
+	return core.KillOptions{Timeout: o.timeout, Signal: o.signal, Yes: o.yes, Wait: o.wait, Force: o.force}
+}
+
+func main() {
+	o := newOptions()
+	o.globalFlags(flag.CommandLine)
+	flag.Usage = usage
 
 	flag.Parse()
 
 	// Create library instance
 	ox := lib.New()
+	switch {
+	case o.verbose && o.silent:
+		fmt.Fprintf(os.Stderr, "Error: -v and -q can't be combined\n")
+		os.Exit(exitUsage)
+	case o.verbose:
+		ox.SetLogLevel(slog.LevelDebug)
+	case o.silent:
+		ox.SetLogLevel(slog.LevelWarn)
+	}
+	if o.noColor {
+		ox.SetColor(false)
+	}
+
+	cmd, isCommand := lookupCommand(flag.Arg(0))
+	if isCommand && cmd.noConfig {
+		cmd.execute(ox, o, flag.Args()[1:])
+		return
+	}
 
 	// Ensure config exists
 	if err := ox.EnsureConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error setting up config: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitConfig)
 	}
 
-	// Handle doctor command
-	if *doctorFlag {
-		var err error
-		if *jsonFlag {
-			err = ox.DoctorJSON()
-		} else {
-			err = ox.Doctor()
-		}
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Doctor check failed: %v\n", err)
-			os.Exit(1)
+	if isCommand {
+		cmd.execute(ox, o, flag.Args()[1:])
+		return
+	}
+
+	// The flag-style commands
+	switch {
+	case o.doctor:
+		runDoctor(ox, o, nil)
+		return
+	case o.all, o.session, o.idle, o.killNames && o.tag != "":
+		runKill(ox, o, nil)
+		return
+	case o.killNames:
+		if flag.NArg() == 0 {
+			flag.Usage()
+			os.Exit(exitUsage)
 		}
+		runKill(ox, o, flag.Args())
+		return
+	}
+
+	// openx <alias|group> [args...] is openx run
+	if flag.NArg() == 0 {
+		runPicker(ox, o)
+		return
+	}
+	// openx foo runs the openx-foo plugin unless foo is configured
+	name := flag.Arg(0)
+	if path, ok := pluginPath(name); ok && !isValidAlias(name) && !ox.IsGroup(name) {
+		exitWith(runPlugin(ox, path, flag.Args()[1:]))
 		return
 	}
+	runLaunch(ox, o, flag.Args())
+}
 
-	// Check for aliases
-	aliases := flag.Args()
-	if len(aliases) == 0 {
+// runPicker lets the user pick an app or group to launch or kill, and
+// prints usage when there is no terminal to pick on
+func runPicker(ox *lib.OpenX, o *options) {
+	choice, err := ox.Pick()
+	if errors.Is(err, core.ErrNotTerminal) {
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitError))
 	}
 
-	// Handle kill command
-	if *killFlag {
-		for _, alias := range aliases {
-			if err := ox.Kill(alias); err != nil {
-				fmt.Fprintf(os.Stderr, "Error killing %s: %v\n", alias, err)
-				os.Exit(1)
+	switch {
+	case choice == nil:
+		return
+	case choice.Action == core.PickKill:
+		runKill(ox, o, []string{choice.Name})
+	default:
+		runLaunch(ox, o, []string{choice.Name})
+	}
+}
+
+// usage prints the commands and the global options
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] <command> [args...]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s [OPTIONS] <alias|group> [args...]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "openx - Developer environment control tool\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	for _, cmd := range commands() {
+		if cmd.hidden {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %-32s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+	}
+	if names := plugins(); len(names) > 0 {
+		fmt.Fprintf(os.Stderr, "\nPlugins (openx-<name> on PATH):\n  %s\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "\nAnything else launches an app or group, as openx run does.\n")
+	fmt.Fprintf(os.Stderr, "With no arguments on a terminal, openx lets you search for one to launch or kill.\n")
+	fmt.Fprintf(os.Stderr, "Run 'openx help <command>' for the options of a command.\n\n")
+	fmt.Fprintf(os.Stderr, "Options, before the command:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  openx code myproject/        # Launch VS Code with project\n")
+	fmt.Fprintf(os.Stderr, "  openx -                      # Relaunch the last app with its arguments\n")
+	fmt.Fprintf(os.Stderr, "  openx kill chrome firefox    # Kill Chrome and Firefox\n")
+	fmt.Fprintf(os.Stderr, "  openx doctor --json          # Health check in JSON format\n")
+	fmt.Fprintf(os.Stderr, "  openx --kill chrome firefox  # The flag style works too\n")
+	fmt.Fprintf(os.Stderr, "\nLibrary version: %s\n", lib.GetVersion())
+}
+
+// runLaunch launches an app with arguments, or every member of a group
+func runLaunch(ox *lib.OpenX, o *options, args []string) {
+	alias := args[0]
+	args = args[1:]
+
+	// openx - relaunches the last app, shell-history style, on a terminal;
+	// with names piped in it launches each of them
+	if alias == "-" {
+		if !stdinPiped() {
+			exitWith(relaunchLast(ox, o))
+			return
+		}
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: arguments can't be given to apps read from stdin, got %v\n", args)
+			os.Exit(exitUsage)
+		}
+		names := stdinNames("openx")
+		failed, code := 0, exitOK
+		for _, name := range names {
+			if c := launch(ox, o, name, nil); c != exitOK {
+				failed++
+				code = cmp.Or(code, c)
 			}
 		}
+		exitWith(groupExitCode(failed, len(names), code))
 		return
 	}
+	exitWith(launch(ox, o, alias, args))
+}
 
-	// Handle launch command - single app with arguments
-	alias := aliases[0]
-	args := aliases[1:]
+// relaunchLast launches the most recent app again and returns the exit code
+func relaunchLast(ox *lib.OpenX, o *options) int {
+	if !o.json {
+		return launchExitCode("the last app", ox.RelaunchLast(o.launchOptions()))
+	}
 
-	// First check if the alias exists in our configuration
-	if isValidAlias(alias) {
-		// It's a valid alias, use normal launch
-		if err := ox.RunAlias(alias, args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Error launching %s: %v\n", alias, err)
-			os.Exit(1)
+	// The launch report needs the app, which RelaunchLast picks itself
+	recent, err := ox.RecentLaunches()
+	if err == nil && len(recent) == 0 {
+		err = core.ErrNothingLaunched
+	}
+	if err != nil {
+		return launchExitCode("the last app", err)
+	}
+	return launch(ox, o, recent[0].Alias, recent[0].Args)
+}
+
+// launch launches an app with arguments, or every member of a group,
+// printing what went wrong, and returns the exit code. With --json it
+// prints a core.LaunchReport, or the group's results.
+func launch(ox *lib.OpenX, o *options, alias string, args []string) int {
+	quietForJSON(ox, o)
+
+	// Configured aliases and paths to executables launch; files and URLs
+	// go through openx open
+	if isValidAlias(alias) || strings.ContainsAny(alias, `/\`) {
+		if o.json {
+			report, err := ox.RunAliasReport(alias, o.launchOptions(), args...)
+			printJSON(report)
+			return launchExitCode(alias, err)
 		}
-	} else {
-		// Not a valid alias, use fallback based on arguments
-		if len(aliases) == 1 {
-			// Single argument - use system default open command
-			if err := openWithSystemDefault(alias); err != nil {
-				fmt.Fprintf(os.Stderr, "Error opening %s: %v\n", alias, err)
-				os.Exit(1)
-			}
-		} else {
-			// Multiple arguments - treat first as app path, rest as args
-			if err := openWithAppAndArgs(alias, args); err != nil {
-				fmt.Fprintf(os.Stderr, "Error launching %s: %v\n", alias, err)
-				os.Exit(1)
+		return launchExitCode(alias, ox.RunAliasWithOptions(alias, o.launchOptions(), args...))
+	}
+	if ox.IsGroup(alias) {
+		// It's a workspace group, launch every member
+		results, err := ox.RunGroupWithConcurrency(alias, o.jobs)
+		if o.json {
+			printJSON(groupLaunch{Group: alias, Members: results})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error launching group %s: %v\n", alias, err)
+			failed := 0
+			for _, result := range results {
+				if result.Err != nil {
+					failed++
+				}
 			}
+			return groupExitCode(failed, len(results), exitCode(err, exitLaunchFailed))
+		}
+		return exitOK
+	}
+
+	// chrm launches chrome when nothing else comes close
+	name, err := ox.ResolveFuzzy(alias)
+	if err != nil {
+		if o.json {
+			printJSON(core.LaunchReport{Alias: alias, Status: core.LaunchStatusFailed, Error: err.Error()})
 		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Use 'openx open %s' for files and URLs\n", alias)
+		return exitCode(err, exitUnknownName)
+	}
+	return launch(ox, o, name, args)
+}
+
+// launchExitCode returns the app's exit code when --wait saw it fail, or
+// reports why it couldn't be launched
+func launchExitCode(name string, err error) int {
+	var exitErr *core.AppExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error launching %s: %v\n", name, err)
+		return exitCode(err, exitLaunchFailed)
+	}
+	return exitOK
+}
+
+// exitWith exits with code unless it is exitOK
+func exitWith(code int) {
+	if code != exitOK {
+		os.Exit(code)
 	}
 }
 
@@ -115,6 +390,11 @@ func isValidAlias(alias string) bool {
 		return false
 	}
 
+	// chrome@work names the chrome alias with a browser profile
+	if _, exists := config.Apps[strings.ToLower(alias)]; !exists {
+		alias, _ = core.SplitAliasProfile(alias)
+	}
+
 	// Check if it's directly in apps
 	if _, exists := config.Apps[strings.ToLower(alias)]; exists {
 		return true
@@ -130,46 +410,3 @@ func isValidAlias(alias string) bool {
 	_, resolved := resolver.Resolve(alias)
 	return resolved
 }
-
-// openWithSystemDefault opens a file or URL using the system's default application
-func openWithSystemDefault(target string) error {
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("open", target)
-	case "linux":
-		// Try xdg-open first, fallback to gio open
-		cmd = exec.Command("xdg-open", target)
-		if err := cmd.Run(); err != nil {
-			cmd = exec.Command("gio", "open", target)
-		}
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", "", target)
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-	}
-
-	return cmd.Run()
-}
-
-// openWithAppAndArgs opens using the specified application path with arguments
-func openWithAppAndArgs(appPath string, args []string) error {
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "darwin":
-		// On macOS, use 'open -a' for applications
-		cmdArgs := []string{"-a", appPath}
-		cmdArgs = append(cmdArgs, args...)
-		cmd = exec.Command("open", cmdArgs...)
-	case "linux", "windows":
-		// On Linux/Windows, execute directly
-		cmdArgs := append([]string{appPath}, args...)
-		cmd = exec.Command(cmdArgs[0], cmdArgs[1:]...)
-	default:
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-	}
-
-	return cmd.Run()
-}
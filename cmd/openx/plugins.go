@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"openx/lib"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// pluginPrefix starts the names of the executables that extend openx:
+// openx foo runs openx-foo from PATH, as git does
+const pluginPrefix = "openx-"
+
+// pluginName matches the commands that may be plugins, keeping paths and
+// options out of the PATH lookup
+var pluginName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// pluginPath returns the executable of the plugin command name, if any
+func pluginPath(name string) (string, bool) {
+	if !pluginName.MatchString(name) {
+		return "", false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	return path, err == nil
+}
+
+// runPlugin runs a plugin with args and returns its exit code. It learns
+// the config path and the global options given to openx from the
+// environment: OPENX_CONFIG, OPENX_BIN for calling back into openx, and
+// OPENX_<OPTION> for each option set, such as OPENX_JSON=true.
+func runPlugin(ox *lib.OpenX, path string, args []string) int {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), pluginEnv(ox.ConfigPath(), flag.CommandLine)...)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running %s: %v\n", filepath.Base(path), err)
+		return exitError
+	}
+	return exitOK
+}
+
+// pluginEnv returns the environment passing the config path, openx
+// itself and the options of fs that were set to a plugin
+func pluginEnv(configPath string, fs *flag.FlagSet) []string {
+	env := []string{"OPENX_CONFIG=" + configPath}
+	if self, err := os.Executable(); err == nil {
+		env = append(env, "OPENX_BIN="+self)
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		if value := f.Value.String(); value != f.DefValue {
+			name := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+			env = append(env, "OPENX_"+name+"="+value)
+		}
+	})
+	return env
+}
+
+// plugins returns the names of the plugin commands on PATH, sorted
+func plugins() []string {
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), pluginPrefix)
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if _, found := pluginPath(name); found {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
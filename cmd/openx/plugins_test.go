@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script plugins on Windows")
+	}
+	dir := t.TempDir()
+	for name, mode := range map[string]os.FileMode{"openx-hello": 0755, "openx-notes": 0644, "other": 0755} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	if path, ok := pluginPath("hello"); !ok || path != filepath.Join(dir, "openx-hello") {
+		t.Errorf("pluginPath(hello) = %q, %v, want the openx-hello script", path, ok)
+	}
+	for _, name := range []string{"notes", "other", "../hello", "-hello"} {
+		if _, ok := pluginPath(name); ok {
+			t.Errorf("pluginPath(%q) found a plugin, want none", name)
+		}
+	}
+	if got := plugins(); !reflect.DeepEqual(got, []string{"hello"}) {
+		t.Errorf("plugins() = %v, want [hello]", got)
+	}
+}
+
+func TestPluginEnv(t *testing.T) {
+	fs := flag.NewFlagSet("openx", flag.ContinueOnError)
+	fs.Bool("json", false, "")
+	fs.Bool("no-color", false, "")
+	fs.Int("jobs", 4, "")
+	if err := fs.Parse([]string{"--no-color", "--jobs", "8"}); err != nil {
+		t.Fatal(err)
+	}
+
+	env := pluginEnv("/home/me/.config/openx/config.yaml", fs)
+	for _, want := range []string{"OPENX_CONFIG=/home/me/.config/openx/config.yaml", "OPENX_NO_COLOR=true", "OPENX_JOBS=8"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("pluginEnv() = %q, missing %s", env, want)
+		}
+	}
+	if slices.Contains(env, "OPENX_JSON=false") {
+		t.Errorf("pluginEnv() = %q, want options left at their default out", env)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"openx/lib"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// shellenvName matches the names that make safe shell aliases and
+// functions without quoting
+var shellenvName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// shellenvFormats write the launch alias and kill function of a name in
+// each shell. Existing commands, aliases and functions of the same name
+// win, so nothing the user relies on is shadowed.
+var shellenvFormats = map[string]struct{ header, launch, kill string }{
+	"bash": {
+		header: "# openx shell aliases for bash\n# Add to ~/.bashrc: eval \"$(openx shellenv bash)\"\n",
+		launch: "command -v %[1]s >/dev/null 2>&1 || alias %[1]s='openx run %[1]s'\n",
+		kill:   "command -v kill-%[1]s >/dev/null 2>&1 || kill-%[1]s() { openx kill %[1]s \"$@\"; }\n",
+	},
+	"zsh": {
+		header: "# openx shell aliases for zsh\n# Add to ~/.zshrc: eval \"$(openx shellenv zsh)\"\n",
+		launch: "(( $+commands[%[1]s] || $+aliases[%[1]s] || $+functions[%[1]s] )) || alias %[1]s='openx run %[1]s'\n",
+		kill:   "(( $+commands[kill-%[1]s] || $+functions[kill-%[1]s] )) || kill-%[1]s() { openx kill %[1]s \"$@\"; }\n",
+	},
+	"fish": {
+		header: "# openx shell aliases for fish\n# Add to ~/.config/fish/config.fish: openx shellenv fish | source\n",
+		launch: "type -q %[1]s; or function %[1]s; openx run %[1]s $argv; end\n",
+		kill:   "type -q kill-%[1]s; or function kill-%[1]s; openx kill %[1]s $argv; end\n",
+	},
+	"powershell": {
+		header: "# openx shell aliases for PowerShell\n# Add to $PROFILE: openx shellenv powershell | Out-String | Invoke-Expression\n",
+		launch: "if (-not (Get-Command %[1]s -ErrorAction SilentlyContinue)) { function global:%[1]s { openx run %[1]s @args } }\n",
+		kill:   "if (-not (Get-Command kill-%[1]s -ErrorAction SilentlyContinue)) { function global:kill-%[1]s { openx kill %[1]s @args } }\n",
+	},
+}
+
+// runShellenv prints shell code defining a launch alias and a kill
+// function for every app, alias and group, to eval in a shell's startup
+func runShellenv(ox *lib.OpenX, o *options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: openx shellenv %s\n", strings.Join(completionShells, "|"))
+		os.Exit(exitUsage)
+	}
+	names, err := ox.Completions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitCode(err, exitConfig))
+	}
+
+	script, err := shellenv(args[0], slices.Concat(names.Apps, names.Aliases, names.Groups))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	fmt.Print(script)
+}
+
+// shellenv returns the shell code for names in shell, skipping the names
+// that aren't safe as shell words
+func shellenv(shell string, names []string) (string, error) {
+	format, ok := shellenvFormats[shell]
+	if !ok {
+		return "", fmt.Errorf("no shellenv for %q (use %s)", shell, strings.Join(completionShells, ", "))
+	}
+
+	var b strings.Builder
+	b.WriteString(format.header)
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		if !shellenvName.MatchString(name) {
+			continue
+		}
+		fmt.Fprintf(&b, format.launch, name)
+		fmt.Fprintf(&b, format.kill, name)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellenv(t *testing.T) {
+	names := []string{"work", "chrome", "my app", "chrome@work", "work"}
+
+	script, err := shellenv("bash", names)
+	if err != nil {
+		t.Fatalf("shellenv(bash) unexpected error: %v", err)
+	}
+	want := []string{
+		"command -v chrome >/dev/null 2>&1 || alias chrome='openx run chrome'",
+		`command -v kill-chrome >/dev/null 2>&1 || kill-chrome() { openx kill chrome "$@"; }`,
+		"command -v work >/dev/null 2>&1 || alias work='openx run work'",
+		`command -v kill-work >/dev/null 2>&1 || kill-work() { openx kill work "$@"; }`,
+	}
+	lines := strings.Split(strings.TrimSpace(script), "\n")
+	if got := lines[2:]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("shellenv(bash) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for _, shell := range completionShells {
+		script, err := shellenv(shell, names)
+		if err != nil {
+			t.Errorf("shellenv(%s) unexpected error: %v", shell, err)
+			continue
+		}
+		if strings.Contains(script, "my app") || strings.Contains(script, "chrome@work") {
+			t.Errorf("shellenv(%s) kept a name that isn't a shell word:\n%s", shell, script)
+		}
+	}
+
+	if _, err := shellenv("tcsh", names); err == nil {
+		t.Error("shellenv(tcsh) succeeded, want an error")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinPiped reports whether stdin is a pipe or file rather than a
+// terminal, so - can stand for names read from it
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readNames reads one alias or group per line: the first word, so lines
+// of openx list or ps output piped through fzf work as they are. Blank
+// lines and # comments are skipped.
+func readNames(r io.Reader) ([]string, error) {
+	var names []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		names = append(names, fields[0])
+	}
+	return names, scanner.Err()
+}
+
+// stdinNames returns the names piped to openx for -, exiting with a usage
+// error when stdin is a terminal or holds no names
+func stdinNames(command string) []string {
+	if !stdinPiped() {
+		fmt.Fprintf(os.Stderr, "Error: %s - reads names from stdin, one per line; pipe them in\n", command)
+		os.Exit(exitUsage)
+	}
+	names, err := readNames(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading names from stdin: %v\n", err)
+		os.Exit(exitError)
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no names on stdin\n")
+		os.Exit(exitUsage)
+	}
+	return names
+}
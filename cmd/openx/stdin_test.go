@@ -0,0 +1,19 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadNames(t *testing.T) {
+	input := "chrome\n  slack  \n\n# work apps\nvscode  app  /usr/bin/code\nweb\tgroup\tchrome, slack\n"
+	got, err := readNames(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readNames() unexpected error: %v", err)
+	}
+	want := []string{"chrome", "slack", "vscode", "web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readNames() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"openx/lib"
+	"runtime"
+	"runtime/debug"
+)
+
+// Release builds set these with -ldflags -X, see .goreleaser.yaml. Other
+// builds fall back to versions.txt and the VCS details go build stamps.
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// versionInfo describes the running openx binary
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // built from a tree with uncommitted changes
+	Date      string `json:"date,omitempty"`     // of the release build, else of the commit
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// runVersion prints the version, commit, build date and Go version
+func runVersion(ox *lib.OpenX, o *options, args []string) {
+	info, _ := debug.ReadBuildInfo()
+	v := versionOf(info)
+	if o.json {
+		data, _ := json.MarshalIndent(v, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("openx %s\n", v.Version)
+	if v.Commit != "" {
+		modified := ""
+		if v.Modified {
+			modified = " (modified)"
+		}
+		fmt.Printf("  commit: %s%s\n", v.Commit, modified)
+	}
+	if v.Date != "" {
+		fmt.Printf("  built:  %s\n", v.Date)
+	}
+	fmt.Printf("  go:     %s %s\n", v.GoVersion, v.Platform)
+}
+
+// versionOf puts together the version details, preferring what the
+// release build set over the build info, when there is any
+func versionOf(info *debug.BuildInfo) versionInfo {
+	v := versionInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if info != nil {
+		if info.GoVersion != "" {
+			v.GoVersion = info.GoVersion
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if v.Commit == "" {
+					v.Commit = setting.Value
+				}
+			case "vcs.time":
+				if v.Date == "" {
+					v.Date = setting.Value
+				}
+			case "vcs.modified":
+				v.Modified = commit == "" && setting.Value == "true"
+			}
+		}
+	}
+	if v.Version == "" {
+		v.Version = lib.GetVersion()
+	}
+	if len(v.Commit) > 12 {
+		v.Commit = v.Commit[:12]
+	}
+	return v
+}
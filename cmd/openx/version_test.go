@@ -0,0 +1,46 @@
+package main
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"openx/lib"
+)
+
+func TestVersionOf(t *testing.T) {
+	stamped := &debug.BuildInfo{
+		GoVersion: "go1.23.7",
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		ldVersion, ldCommit   string
+		info                  *debug.BuildInfo
+		wantVersion, wantHash string
+		wantDate              string
+		wantModified          bool
+	}{
+		{"no build info", "", "", nil, lib.GetVersion(), "", "", false},
+		{"go build in a checkout", "", "", stamped, lib.GetVersion(), "0123456789ab", "2026-10-01T12:00:00Z", true},
+		{"release build", "1.4.0", "fedcba987654", stamped, "1.4.0", "fedcba987654", "2026-10-01T12:00:00Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, commit = tt.ldVersion, tt.ldCommit
+			defer func() { version, commit = "", "" }()
+
+			v := versionOf(tt.info)
+			if v.Version != tt.wantVersion || v.Commit != tt.wantHash || v.Date != tt.wantDate || v.Modified != tt.wantModified {
+				t.Errorf("versionOf() = %+v, want version %q, commit %q, date %q, modified %v", v, tt.wantVersion, tt.wantHash, tt.wantDate, tt.wantModified)
+			}
+			if v.GoVersion == "" || v.Platform == "" {
+				t.Errorf("versionOf() = %+v, want the Go version and platform", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// AddAppOptions are the choices AddApp would otherwise ask about
+type AddAppOptions struct {
+	// Path is the app's launch path on this OS; when empty it is looked
+	// up among the installed applications
+	Path string
+
+	// Aliases to add for the app; nil asks for them on a terminal
+	Aliases []string
+
+	// Yes takes a discovered path without asking and skips the alias
+	// question
+	Yes bool
+}
+
+// AddApp adds an app to the config with its path for this OS, found among
+// the installed applications when none is given, and aliases for it. Kill
+// patterns are left to be derived from the path, and shown.
+func AddApp(name string, opts AddAppOptions) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err := validateEntryName(name); err != nil {
+		return err
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := checkNameFree(config, name); err != nil {
+		return err
+	}
+
+	path := opts.Path
+	if path == "" {
+		installed, err := DiscoverApps()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		found, ok := findReplacement(name, "", installed)
+		if !ok {
+			return fmt.Errorf("no installed application matches %s, give its path: openx add %s <path>", name, name)
+		}
+		fmt.Printf("%s: found %s\n", name, found)
+		if !opts.Yes && !confirm("Use it?") {
+			return fmt.Errorf("%s not added", name)
+		}
+		path = found
+	} else {
+		path = absoluteAppPath(path)
+		if !appExists(expandTilde(path)) {
+			return fmt.Errorf("%s not found: not an existing path or a command on PATH", path)
+		}
+	}
+
+	aliases := opts.Aliases
+	if aliases == nil && !opts.Yes {
+		aliases = strings.Fields(strings.ReplaceAll(prompt(fmt.Sprintf("Aliases for %s, space separated (Enter for none):", name)), ",", " "))
+	}
+	for i, alias := range aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if err := validateEntryName(alias); err != nil {
+			return err
+		}
+		if alias == name {
+			return fmt.Errorf("alias %s is the app's own name", alias)
+		}
+		if err := checkNameFree(config, alias); err != nil {
+			return err
+		}
+		aliases[i] = alias
+	}
+
+	app := &App{Paths: map[string]string{runtime.GOOS: path}}
+	if config.Apps == nil {
+		config.Apps = map[string]*App{}
+	}
+	config.Apps[name] = app
+	if len(aliases) > 0 && config.Aliases == nil {
+		config.Aliases = map[string]string{}
+	}
+	for _, alias := range aliases {
+		config.Aliases[alias] = name
+	}
+	if err := saveConfig(config); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added %s: %s\n", name, path)
+	if patterns := app.GetKillPatterns(); len(patterns) > 0 {
+		fmt.Printf("  kill: %s (derived from the path, set kill: in the config to change)\n", strings.Join(patterns, ", "))
+	}
+	if len(aliases) > 0 {
+		fmt.Printf("  aliases: %s\n", strings.Join(aliases, ", "))
+	}
+	return nil
+}
+
+// validateEntryName rejects names that can't be typed as one openx
+// argument
+func validateEntryName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	if strings.ContainsAny(name, " \t/\\@") {
+		return fmt.Errorf("invalid name %q: no spaces, slashes or @, which names a browser profile", name)
+	}
+	return nil
+}
+
+// checkNameFree fails when name is already an app, alias or group
+func checkNameFree(cfg *Config, name string) error {
+	if _, exists := cfg.Apps[name]; exists {
+		return fmt.Errorf("%s is already an app, edit it with openx config edit", name)
+	}
+	if target, exists := cfg.Aliases[name]; exists {
+		return fmt.Errorf("%s is already an alias of %s", name, target)
+	}
+	if _, exists := cfg.Groups[name]; exists {
+		return fmt.Errorf("%s is already a group", name)
+	}
+	return nil
+}
+
+// absoluteAppPath makes a relative file path absolute, leaving commands,
+// ~ paths, URLs and the prefixed forms (uwp:, snap:, ...) as they are
+func absoluteAppPath(path string) string {
+	if !strings.ContainsAny(path, `/\`) || isURL(path) || strings.HasPrefix(path, "~") || filepath.IsAbs(path) {
+		return path
+	}
+	if strings.Contains(path, ":") && !strings.HasPrefix(path, ".") {
+		// snap:code, desktop:org.gnome.Terminal.desktop, C:\... handled above
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// prompt asks a question on the terminal and returns the answer, "" when
+// there is no terminal to ask on; tests replace it
+var prompt = func(question string) string {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return ""
+	}
+
+	fmt.Fprintf(os.Stderr, "%s ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer)
+}
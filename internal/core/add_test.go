@@ -0,0 +1,97 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestAddApp(t *testing.T) {
+	tool := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := setupTestConfig(t, `
+apps:
+  editor:
+    darwin: "/bin/ls"
+    linux: "/bin/ls"
+    windows: "notepad.exe"
+aliases:
+  ed: editor
+groups:
+  work: [editor]`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		app     string
+		opts    AddAppOptions
+		wantErr string
+	}{
+		{"taken by an app", "editor", AddAppOptions{Path: tool, Aliases: []string{}}, "already an app"},
+		{"taken by an alias", "ed", AddAppOptions{Path: tool, Aliases: []string{}}, "already an alias"},
+		{"taken by a group", "work", AddAppOptions{Path: tool, Aliases: []string{}}, "already a group"},
+		{"invalid name", "my tool", AddAppOptions{Path: tool, Aliases: []string{}}, "invalid name"},
+		{"missing path", "ghost", AddAppOptions{Path: filepath.Join(t.TempDir(), "ghost"), Aliases: []string{}}, "not found"},
+		{"alias taken", "tool", AddAppOptions{Path: tool, Aliases: []string{"ed"}}, "already an alias"},
+		{"added", "Tool", AddAppOptions{Path: tool, Aliases: []string{"t", " TL "}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AddApp(tt.app, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("AddApp(%q) error = %v, want %q", tt.app, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddApp(%q) error: %v", tt.app, err)
+			}
+		})
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app, ok := config.Apps["tool"]; !ok || app.Paths[runtime.GOOS] != tool {
+		t.Errorf("tool not added with its path: %+v", config.Apps["tool"])
+	}
+	if config.Aliases["t"] != "tool" || config.Aliases["tl"] != "tool" || config.Aliases["ed"] != "editor" {
+		t.Errorf("aliases = %v", config.Aliases)
+	}
+	if _, ok := config.Apps["ghost"]; ok {
+		t.Error("a failed add was written to the config")
+	}
+}
+
+func TestAbsoluteAppPath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"code", "code"},
+		{"~/bin/tool", "~/bin/tool"},
+		{"https://example.com/app", "https://example.com/app"},
+		{"snap:code", "snap:code"},
+		{filepath.Join(wd, "tool"), filepath.Join(wd, "tool")},
+		{"bin/tool", filepath.Join(wd, "bin", "tool")},
+	}
+	for _, tt := range tests {
+		if got := absoluteAppPath(tt.path); got != tt.want {
+			t.Errorf("absoluteAppPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
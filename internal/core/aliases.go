@@ -1,7 +1,9 @@
 package core
 
 import (
+	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 
 	"openx/shared/config"
@@ -80,6 +82,12 @@ func (a *AliasResolver) initializeSynonyms() {
 	a.synonyms["ad"] = "anydesk"
 }
 
+// SynonymTarget returns the app a built-in synonym such as code stands for
+func SynonymTarget(name string) (string, bool) {
+	target, ok := newAliasResolver(nil).synonyms[strings.ToLower(name)]
+	return target, ok
+}
+
 func (a *AliasResolver) Resolve(alias string) (string, bool) {
 	base := strings.ToLower(alias)
 	if v, ok := a.synonyms[base]; ok {
@@ -97,3 +105,70 @@ func (a *AliasResolver) Resolve(alias string) (string, bool) {
 
 	return "", false
 }
+
+// lookupApp finds an app by name or config alias, returning its canonical name
+func lookupApp(cfg *config.Config, alias string) (string, *config.App, error) {
+	if app, exists := cfg.Apps[alias]; exists {
+		return alias, app, nil
+	}
+
+	// Check if it's an alias
+	canonical, ok := cfg.Aliases[alias]
+	if !ok {
+		return "", nil, UnknownName(cfg, ListKindApp, alias)
+	}
+
+	app, exists := cfg.Apps[canonical]
+	if !exists {
+		return "", nil, fmt.Errorf("alias '%s' points to %w '%s'", alias, ErrUnknownApp, canonical)
+	}
+	return canonical, app, nil
+}
+
+// AliasIssue is an alias or built-in synonym that doesn't lead to a
+// launchable app
+type AliasIssue struct {
+	Alias   string `json:"alias" yaml:"alias"`
+	Target  string `json:"target" yaml:"target"`
+	Synonym bool   `json:"synonym,omitempty" yaml:"synonym,omitempty"` // built in, not from the config
+	Problem string `json:"problem" yaml:"problem"`
+}
+
+// checkAliases lists the config aliases and built-in synonyms that dangle:
+// their target isn't a configured app, is another alias (aliases don't
+// chain), or has no path on this OS. Aliases come first, each sorted.
+// Aliases hidden by an app name are conflicts, see checkConflicts.
+func checkAliases(cfg *config.Config) []AliasIssue {
+	problem := func(target string) string {
+		app, exists := cfg.Apps[target]
+		if !exists {
+			if _, isAlias := cfg.Aliases[target]; isAlias {
+				return fmt.Sprintf("points to alias %s, aliases don't chain", target)
+			}
+			return "points to unknown app " + target
+		}
+		if !isDockerApp(app) && app.GetLaunchPath() == "" {
+			return fmt.Sprintf("%s has no path for %s", target, runtime.GOOS)
+		}
+		return ""
+	}
+
+	var aliases, synonyms []AliasIssue
+	for alias, target := range cfg.Aliases {
+		if p := problem(target); p != "" {
+			aliases = append(aliases, AliasIssue{Alias: alias, Target: target, Problem: p})
+		}
+	}
+	for synonym, target := range newAliasResolver(cfg).synonyms {
+		if p := problem(target); p != "" {
+			synonyms = append(synonyms, AliasIssue{Alias: synonym, Target: target, Synonym: true, Problem: p})
+		}
+	}
+
+	byAlias := func(issues []AliasIssue) {
+		sort.Slice(issues, func(i, j int) bool { return issues[i].Alias < issues[j].Alias })
+	}
+	byAlias(aliases)
+	byAlias(synonyms)
+	return append(aliases, synonyms...)
+}
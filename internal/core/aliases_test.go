@@ -1,6 +1,7 @@
 package core
 
 import (
+	"reflect"
 	"runtime"
 	"testing"
 
@@ -148,3 +149,53 @@ func getExpectedVSCodePath() string {
 		return "code"
 	}
 }
+
+func TestCheckAliases(t *testing.T) {
+	cfg := &config.Config{
+		Apps: map[string]*config.App{
+			"vscode":   {Paths: map[string]string{runtime.GOOS: "code"}},
+			"postman":  {Paths: map[string]string{"plan9": "postman"}},
+			"postgres": {Type: "docker", Image: "postgres:16"},
+			"edit":     {Paths: map[string]string{runtime.GOOS: "vim"}},
+		},
+		Aliases: map[string]string{
+			"c":    "vscode",
+			"pg":   "postgres",
+			"post": "postman",
+			"gone": "atom",
+			"cc":   "c",
+			"edit": "vscode",
+		},
+	}
+
+	issues := checkAliases(cfg)
+
+	var aliases []AliasIssue
+	synonyms := map[string]AliasIssue{}
+	for _, issue := range issues {
+		if issue.Synonym {
+			synonyms[issue.Alias] = issue
+		} else {
+			aliases = append(aliases, issue)
+		}
+	}
+
+	want := []AliasIssue{
+		{Alias: "cc", Target: "c", Problem: "points to alias c, aliases don't chain"},
+		{Alias: "gone", Target: "atom", Problem: "points to unknown app atom"},
+		{Alias: "post", Target: "postman", Problem: "postman has no path for " + runtime.GOOS},
+	}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("checkAliases() aliases = %+v, want %+v", aliases, want)
+	}
+
+	if _, dangling := synonyms["code"]; dangling {
+		t.Error("checkAliases() reported synonym code, whose app is configured")
+	}
+	if issue, dangling := synonyms["pm"]; !dangling || issue.Target != "postman" {
+		t.Errorf("checkAliases() synonym pm = %+v, want it dangling to postman", issue)
+	}
+	if _, dangling := synonyms["gc"]; !dangling {
+		t.Error("checkAliases() did not report synonym gc, whose app isn't configured")
+	}
+}
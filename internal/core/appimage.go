@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// appImageDirs returns the directories searched for AppImages
+func appImageDirs() []string {
+	home := getHomeDir()
+	return []string{
+		filepath.Join(home, "Applications"),
+		filepath.Join(home, "Downloads"),
+	}
+}
+
+// discoverAppImage finds the AppImage for name and makes sure it can run
+func discoverAppImage(name string) (string, error) {
+	path, ok := findAppImage(name, appImageDirs())
+	if !ok {
+		return "", fmt.Errorf("no AppImage matching %q in %s", name, strings.Join(appImageDirs(), ", "))
+	}
+	if err := ensureExecutable(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// findAppImage returns the most recently modified AppImage in dirs whose
+// file name contains name, so versioned downloads like
+// Obsidian-1.5.3.AppImage are found without naming the version
+func findAppImage(name string, dirs []string) (string, bool) {
+	want := normalizeAppName(name)
+	var best string
+	var bestTime int64
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			fileName := entry.Name()
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(fileName), ".appimage") {
+				continue
+			}
+			if !strings.Contains(normalizeAppName(fileName), want) {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if best == "" || info.ModTime().UnixNano() > bestTime {
+				best = filepath.Join(dir, fileName)
+				bestTime = info.ModTime().UnixNano()
+			}
+		}
+	}
+
+	return best, best != ""
+}
+
+// ensureExecutable sets the executable bits on a downloaded file
+func ensureExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&0111 != 0 {
+		return nil
+	}
+	if err := os.Chmod(path, info.Mode()|0111); err != nil {
+		return fmt.Errorf("failed to make %s executable: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFindAppImage(t *testing.T) {
+	apps := t.TempDir()
+	downloads := t.TempDir()
+
+	files := map[string]time.Duration{
+		filepath.Join(apps, "Obsidian-1.4.16.AppImage"):        -2 * time.Hour,
+		filepath.Join(downloads, "Obsidian-1.5.3.AppImage"):    -time.Hour,
+		filepath.Join(downloads, "obsidian-notes.txt"):         0,
+		filepath.Join(downloads, "balenaEtcher-1.18.AppImage"): -time.Hour,
+	}
+	for path, age := range files {
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"obsidian", filepath.Join(downloads, "Obsidian-1.5.3.AppImage")},
+		{"Balena Etcher", filepath.Join(downloads, "balenaEtcher-1.18.AppImage")},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := findAppImage(tt.name, []string{apps, downloads})
+			if path != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("findAppImage(%q) = %q, %v, want %q", tt.name, path, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnsureExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no executable bits")
+	}
+
+	path := filepath.Join(t.TempDir(), "App.AppImage")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ensureExecutable(path); err != nil {
+		t.Fatalf("ensureExecutable() failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Errorf("mode = %v, want executable", info.Mode())
+	}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// rosettaRuntime exists once Rosetta 2 is installed on Apple Silicon
+const rosettaRuntime = "/Library/Apple/usr/share/rosetta/rosetta"
+
+// plistExecutable matches the bundle executable in an XML Info.plist
+var plistExecutable = regexp.MustCompile(`<key>CFBundleExecutable</key>\s*<string>([^<]*)</string>`)
+
+// archNames are how doctor names architectures, after Go's GOARCH values
+var archNames = map[string]string{
+	"amd64": "x86_64",
+	"386":   "32-bit x86",
+	"arm64": "arm64",
+	"arm":   "32-bit ARM",
+}
+
+// appArchMismatch checks an app's executable against this machine's
+// architecture and says why it won't run, or will only run emulated;
+// it returns "" when the app matches or its executable can't be read
+func appArchMismatch(path string) string {
+	executable := appExecutable(path)
+	if executable == "" {
+		return ""
+	}
+	archs, err := binaryArchs(executable)
+	if err != nil {
+		return ""
+	}
+	return archMismatch(runtime.GOOS, hostArch(), archs, exists(rosettaRuntime))
+}
+
+// hostArch is the machine's architecture, looked up once
+var hostArch = sync.OnceValue(detectHostArch)
+
+// appExecutable finds the binary behind a launch path: the bundle
+// executable of a macOS app, the file itself, or a command on PATH
+func appExecutable(path string) string {
+	if isURL(path) || strings.HasPrefix(path, snapPrefix) || strings.HasPrefix(path, appImagePrefix) {
+		return ""
+	}
+	if _, ok := uwpAppID(path); ok {
+		return ""
+	}
+	if _, ok := desktopEntryID(path); ok {
+		return ""
+	}
+
+	if runtime.GOOS == "darwin" && strings.HasSuffix(strings.TrimSuffix(path, "/"), ".app") {
+		name := strings.TrimSuffix(filepath.Base(path), ".app")
+		if data, err := os.ReadFile(filepath.Join(path, "Contents", "Info.plist")); err == nil {
+			if match := plistExecutable.FindStringSubmatch(string(data)); match != nil {
+				name = strings.TrimSpace(match[1])
+			}
+		}
+		return filepath.Join(path, "Contents", "MacOS", name)
+	}
+	if strings.ContainsAny(path, `/\`) {
+		return path
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return ""
+	}
+	return resolved
+}
+
+// binaryArchs reads the architectures a Mach-O (thin or universal), PE or
+// ELF executable is built for, as GOARCH names
+func binaryArchs(path string) ([]string, error) {
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		var archs []string
+		for _, arch := range fat.Arches {
+			archs = append(archs, machoArch(arch.Cpu))
+		}
+		return archs, nil
+	}
+	if file, err := macho.Open(path); err == nil {
+		defer file.Close()
+		return []string{machoArch(file.Cpu)}, nil
+	}
+	if file, err := pe.Open(path); err == nil {
+		defer file.Close()
+		return []string{peArch(file.Machine)}, nil
+	}
+	if file, err := elf.Open(path); err == nil {
+		defer file.Close()
+		return []string{elfArch(file.Machine)}, nil
+	}
+	return nil, fmt.Errorf("%s is not a Mach-O, PE or ELF executable", path)
+}
+
+// machoArch names a Mach-O CPU type
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuArm:
+		return "arm"
+	}
+	return strings.ToLower(cpu.String())
+}
+
+// peArch names a PE machine type
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		return "arm"
+	}
+	return fmt.Sprintf("machine 0x%x", machine)
+}
+
+// elfArch names an ELF machine type
+func elfArch(machine elf.Machine) string {
+	switch machine {
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_386:
+		return "386"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_ARM:
+		return "arm"
+	}
+	return strings.ToLower(strings.TrimPrefix(machine.String(), "EM_"))
+}
+
+// detectHostArch returns the machine's architecture, which differs from
+// GOARCH when openx itself runs translated by Rosetta or emulated on
+// Windows on ARM
+func detectHostArch() string {
+	switch runtime.GOOS {
+	case "darwin":
+		if output, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output(); err == nil && strings.TrimSpace(string(output)) == "1" {
+			return "arm64"
+		}
+	case "windows":
+		// PROCESSOR_ARCHITEW6432 is set for emulated processes only
+		for _, name := range []string{"PROCESSOR_ARCHITEW6432", "PROCESSOR_ARCHITECTURE"} {
+			switch strings.ToUpper(os.Getenv(name)) {
+			case "ARM64":
+				return "arm64"
+			case "AMD64":
+				return "amd64"
+			}
+		}
+	}
+	return runtime.GOARCH
+}
+
+// archMismatch explains why a binary built for archs won't run natively
+// on a host; a binary that includes the host's architecture matches
+func archMismatch(goos, host string, archs []string, rosetta bool) string {
+	if len(archs) == 0 || slices.Contains(archs, host) {
+		return ""
+	}
+	built := make([]string, len(archs))
+	for i, arch := range archs {
+		built[i] = archName(arch)
+	}
+	only := strings.Join(built, "/") + " only"
+
+	switch {
+	case goos == "darwin" && host == "arm64" && slices.Contains(archs, "amd64"):
+		if rosetta {
+			return only + ", runs translated by Rosetta"
+		}
+		return only + " and Rosetta is not installed (softwareupdate --install-rosetta)"
+	case goos == "windows" && host == "arm64" && (slices.Contains(archs, "amd64") || slices.Contains(archs, "386")):
+		return only + ", runs emulated on ARM"
+	case goos == "windows" && host == "amd64" && slices.Contains(archs, "386"):
+		return "" // WOW64 runs 32-bit apps natively
+	case goos == "linux" && host == "amd64" && slices.Contains(archs, "386"):
+		return only + ", needs the 32-bit libraries installed"
+	}
+	return fmt.Sprintf("%s, can't run on %s", only, archName(host))
+}
+
+// archName is how doctor shows a GOARCH value
+func archName(arch string) string {
+	if name, ok := archNames[arch]; ok {
+		return name
+	}
+	return arch
+}
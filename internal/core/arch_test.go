@@ -0,0 +1,57 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestArchMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		goos    string
+		host    string
+		archs   []string
+		rosetta bool
+		want    string
+	}{
+		{"native", "darwin", "arm64", []string{"arm64"}, false, ""},
+		{"universal", "darwin", "arm64", []string{"amd64", "arm64"}, false, ""},
+		{"rosetta", "darwin", "arm64", []string{"amd64"}, true, "x86_64 only, runs translated by Rosetta"},
+		{"no rosetta", "darwin", "arm64", []string{"amd64"}, false, "x86_64 only and Rosetta is not installed (softwareupdate --install-rosetta)"},
+		{"arm app on intel mac", "darwin", "amd64", []string{"arm64"}, false, "arm64 only, can't run on x86_64"},
+		{"32-bit on windows arm", "windows", "arm64", []string{"386"}, false, "32-bit x86 only, runs emulated on ARM"},
+		{"wow64", "windows", "amd64", []string{"386"}, false, ""},
+		{"arm exe on windows x64", "windows", "amd64", []string{"arm64"}, false, "arm64 only, can't run on x86_64"},
+		{"32-bit on linux", "linux", "amd64", []string{"386"}, false, "32-bit x86 only, needs the 32-bit libraries installed"},
+		{"unknown binary", "linux", "amd64", nil, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := archMismatch(tt.goos, tt.host, tt.archs, tt.rosetta); got != tt.want {
+				t.Errorf("archMismatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryArchs(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip("test executable unavailable")
+	}
+
+	archs, err := binaryArchs(exe)
+	if err != nil {
+		t.Fatalf("binaryArchs(%s) unexpected error: %v", exe, err)
+	}
+	if !slices.Contains(archs, runtime.GOARCH) {
+		t.Errorf("binaryArchs(%s) = %v, want %s", exe, archs, runtime.GOARCH)
+	}
+
+	if _, err := binaryArchs("/definitely/does/not/exist"); err == nil {
+		t.Error("binaryArchs() expected error for a missing file")
+	}
+}
@@ -1,82 +1,748 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// CloseApp closes an application by killing its processes
-func CloseApp(alias string) error {
+// defaultKillTimeout is how long a graceful close may take before openx
+// force kills what is left
+const defaultKillTimeout = 5 * time.Second
+
+// broadKillThreshold is how many processes a kill pattern may match
+// without confirmation when openx can't tell which belong to the app
+const broadKillThreshold = 10
+
+// defaultKillConcurrency is how many apps CloseApps closes at once
+const defaultKillConcurrency = 4
+
+// confirmMu keeps concurrent kills from asking over each other
+var confirmMu sync.Mutex
+
+// killPollInterval is how often openx checks whether closed processes are gone
+var killPollInterval = 200 * time.Millisecond
+
+// KillOptions controls how an application is closed
+type KillOptions struct {
+	// Timeout is how long the app gets to exit after a graceful quit
+	// before it is force killed; zero uses the app's kill_timeout
+	Timeout time.Duration
+
+	// Signal replaces the graceful quit: TERM or INT, then a force kill
+	// after Timeout; HUP alone, for apps that reload on it; KILL at once.
+	// Empty uses the app's signal, or the platform's graceful quit.
+	Signal string
+
+	// Yes kills without asking when a pattern matches broadly
+	Yes bool
+
+	// Wait returns only once nothing matches the killed patterns any more,
+	// erroring after killWaitTimeout, so scripts can clean up safely
+	Wait bool
+
+	// Force closes apps marked protected, which kills refuse otherwise
+	Force bool
+
+	// exclude protects processes a kill pattern would otherwise match
+	exclude []string
+
+	// saveDocuments saves the app's open documents before stopping it
+	saveDocuments bool
+
+	// prefix starts the progress lines of the kill, naming the app while
+	// several close at once
+	prefix string
+}
+
+// progress prints a progress line of the kill like info, prefixed with
+// the app's alias while several apps close at once
+func (o KillOptions) progress(format string, args ...any) {
+	info("%s"+format, append([]any{o.prefix}, args...)...)
+}
+
+// killWaitTimeout bounds how long a kill with Wait waits for matching
+// processes to disappear
+var killWaitTimeout = 30 * time.Second
+
+// saveDocumentsTimeout bounds how long an app may take to save its
+// documents before a kill goes ahead
+const saveDocumentsTimeout = 20 * time.Second
+
+// forceKillWait is how long force killed processes get to disappear
+// before the kill is reported as failed
+const forceKillWait = 2 * time.Second
+
+// Ways a kill pattern's processes were handled, as reported in PatternReport
+const (
+	KillMethodGraceful = "graceful" // quit on request within the timeout
+	KillMethodForced   = "forced"   // force killed, at once or after the timeout
+	KillMethodSignal   = "signal"   // HUP sent, the processes keep running
+	KillMethodSkipped  = "skipped"  // the kill wasn't confirmed, nothing was sent
+)
+
+// KillReport is the outcome of closing one application
+type KillReport struct {
+	Alias    string          `json:"alias"`
+	Patterns []PatternReport `json:"patterns"`
+
+	// Protected is set when the app was left alone because it is marked
+	// protected and the kill wasn't forced
+	Protected bool `json:"protected,omitempty"`
+}
+
+// PatternReport is what one kill pattern matched and how it was stopped.
+// Docker and Store apps report their container or package as the pattern.
+type PatternReport struct {
+	Pattern string `json:"pattern"`
+	Matched bool   `json:"matched"`          // whether anything was running
+	PIDs    []int  `json:"pids,omitempty"`   // matched processes, unknown without a process table
+	Method  string `json:"method,omitempty"` // one of the KillMethod values, empty when nothing matched
+	Stopped bool   `json:"stopped"`          // the matched processes are gone
+	Err     error  `json:"-"`
+}
+
+// Killed reports whether any of the app's processes were stopped or
+// signalled
+func (r *KillReport) Killed() bool {
+	if r == nil {
+		return false
+	}
+	for _, p := range r.Patterns {
+		if p.Matched && p.Method != KillMethodSkipped {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultProtectedProcesses are never killed, whatever a pattern matches:
+// remote development daemons that share names with desktop editors, and
+// the SSH server a remote session runs under
+var defaultProtectedProcesses = []string{"vscode-server", "code-server", "sshd"}
+
+// Signals accepted by --signal and the per-app signal option
+const (
+	signalTerm = "TERM"
+	signalInt  = "INT"
+	signalHup  = "HUP"
+	signalKill = "KILL"
+)
+
+// parseSignal normalizes a signal name such as sigterm or HUP
+func parseSignal(name string) (string, error) {
+	signal := strings.TrimPrefix(strings.ToUpper(name), "SIG")
+	switch signal {
+	case "", signalTerm, signalInt, signalHup, signalKill:
+		return signal, nil
+	default:
+		return "", fmt.Errorf("unsupported signal %q (use TERM, INT, HUP or KILL)", name)
+	}
+}
+
+// CloseApp closes an application, asking it to quit before force killing it
+func CloseApp(alias string) (*KillReport, error) {
+	return CloseAppWithOptions(alias, KillOptions{})
+}
+
+// CloseAppWithOptions closes an application with the given kill options
+// and reports what each kill pattern matched and how it was stopped
+func CloseAppWithOptions(alias string, opts KillOptions) (*KillReport, error) {
 	config, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	app, exists := config.Apps[alias]
-	if !exists {
-		// Check if it's an alias
-		if canonical, ok := config.Aliases[alias]; ok {
-			app, exists = config.Apps[canonical]
-			if !exists {
-				return fmt.Errorf("alias '%s' points to unknown app '%s'", alias, canonical)
-			}
-		} else {
-			return fmt.Errorf("unknown app: %s", alias)
+	return closeApp(config, alias, opts)
+}
+
+// closeApp closes an application. The report is returned along with the
+// error when some of the app's processes could not be stopped, or with
+// ErrKillNoMatch when none were running.
+func closeApp(config *Config, alias string, opts KillOptions) (report *KillReport, err error) {
+	name, app, err := lookupApp(config, alias)
+	if err != nil {
+		return nil, err
+	}
+	report = &KillReport{Alias: alias}
+	defer func() {
+		if report.Killed() {
+			recordHistory(HistoryKill, alias, name, nil)
 		}
+	}()
+
+	if app.Protected && !opts.Force {
+		report.Protected = true
+		return report, fmt.Errorf("%s is protected, use --force to close it", alias)
 	}
 
-	killPatterns := app.GetKillPatterns()
+	if isDockerApp(app) {
+		container := dockerContainerName(name, app)
+		running, err := stopDockerApp(alias, name, app, opts)
+		report.Patterns = []PatternReport{{Pattern: container, Matched: running, Stopped: running && err == nil, Err: err}}
+		if !running {
+			return report, fmt.Errorf("%w %s", ErrKillNoMatch, alias)
+		}
+		report.Patterns[0].Method = KillMethodGraceful
+		return report, err
+	}
+
+	// Store apps are stopped by package unless kill patterns are given
+	if aumid, ok := uwpAppID(app.GetLaunchPath()); ok && len(app.Kill) == 0 {
+		family := uwpPackageFamily(aumid)
+		running, err := killUWPApp(aumid)
+		report.Patterns = []PatternReport{{Pattern: family, Matched: running, Stopped: running && err == nil, Err: err}}
+		if running {
+			report.Patterns[0].Method = KillMethodForced
+		}
+		switch {
+		case err != nil:
+			return report, fmt.Errorf("failed to close %s: %w", alias, err)
+		case !running:
+			opts.progress("No running processes found for: %s", alias)
+			return report, fmt.Errorf("%w %s", ErrKillNoMatch, alias)
+		}
+		opts.progress("Killed all processes of package: %s", family)
+		return report, nil
+	}
+
+	killPatterns := appKillPatterns(app)
 	if len(killPatterns) == 0 {
-		return fmt.Errorf("no kill patterns available for %s", alias)
+		return nil, fmt.Errorf("no kill patterns available for %s", alias)
+	}
+	for _, pattern := range killPatterns {
+		if _, err := parseKillPattern(pattern); err != nil {
+			return nil, fmt.Errorf("%s: %w", alias, err)
+		}
+	}
+
+	if opts.Signal == "" {
+		opts.Signal = app.Signal
+	}
+	if opts.Signal, err = parseSignal(opts.Signal); err != nil {
+		return nil, fmt.Errorf("%s: %w", alias, err)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = app.KillTimeout
+	}
+	opts.exclude = append(append(append(opts.exclude, app.KillExclude...), config.Protected...), defaultProtectedProcesses...)
+	opts.saveDocuments = app.SaveBeforeKill && runtime.GOOS == "darwin"
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultKillTimeout
 	}
+	debug("closing", "alias", alias, "app", name, "patterns", killPatterns, "signal", opts.Signal, "timeout", opts.Timeout, "exclude", opts.exclude)
 
 	// Try each kill pattern and kill all matching processes
-	killed := false
+	var failed []error
+	var killed []string
 	for _, pattern := range killPatterns {
-		if err := killAllByPattern(pattern); err == nil {
-			fmt.Printf("Killed all processes matching: %s\n", pattern)
-			killed = true
+		if !opts.Yes && !confirmKill(alias, app, pattern, opts.exclude) {
+			opts.progress("Skipped processes matching: %s", pattern)
+			report.Patterns = append(report.Patterns, PatternReport{Pattern: pattern, Matched: true, Method: KillMethodSkipped})
+			continue
 		}
+		killed = append(killed, pattern)
+
+		result := stopPattern(pattern, opts)
+		report.Patterns = append(report.Patterns, result)
+		switch {
+		case result.Err != nil:
+			failed = append(failed, result.Err)
+		case result.Method == KillMethodSignal:
+			opts.progress("Sent HUP to all processes matching: %s", pattern)
+		case result.Matched:
+			opts.progress("Killed all processes matching: %s", pattern)
+		}
+	}
+
+	if len(failed) > 0 {
+		return report, fmt.Errorf("failed to close %s: %w", alias, errors.Join(failed...))
+	}
+	if !slices.ContainsFunc(report.Patterns, func(p PatternReport) bool { return p.Matched }) {
+		opts.progress("No running processes found for: %s", alias)
+		return report, fmt.Errorf("%w %s", ErrKillNoMatch, alias)
+	}
+
+	// Processes respawned by a supervisor or started meanwhile count too
+	if opts.Wait && report.Killed() && opts.Signal != signalHup {
+		if !waitUntilStopped(func() bool { return patternsRunning(killed, opts.exclude) }, killWaitTimeout) {
+			return report, fmt.Errorf("processes of %s %w after %s", alias, ErrStillRunning, killWaitTimeout)
+		}
+		opts.progress("No processes left for: %s", alias)
 	}
+	return report, nil
+}
 
-	if !killed {
-		fmt.Printf("No running processes found for: %s\n", alias)
+// patternsRunning reports whether any process matches one of the
+// patterns, looking at the whole process table rather than the processes
+// a kill already saw
+func patternsRunning(patterns, exclude []string) bool {
+	processes, err := listProcesses()
+	for _, pattern := range patterns {
+		if err != nil {
+			if isProcessRunning(pattern) {
+				return true
+			}
+			continue
+		}
+		if len(excludeProcesses(matchingProcesses(processes, pattern), exclusionsFor(pattern, exclude))) > 0 {
+			return true
+		}
 	}
+	return false
+}
 
+// appKillPatterns returns the app's kill patterns, deriving them for launch
+// path types that need a look at the system to resolve
+func appKillPatterns(app *App) []string {
+	if len(app.Kill) > 0 {
+		return app.Kill
+	}
+	if id, ok := desktopEntryID(app.GetLaunchPath()); ok {
+		return desktopEntryKillPatterns(id)
+	}
+	return app.GetKillPatterns()
+}
+
+// confirmKill asks before a pattern kills processes that don't look like
+// the app's own: processes outside the app's install path, or, when the
+// path is unknown, more than broadKillThreshold processes
+func confirmKill(alias string, app *App, pattern string, exclude []string) bool {
+	processes, err := listProcesses()
+	if err != nil {
+		return true
+	}
+
+	matched := excludeProcesses(matchingProcesses(processes, pattern), exclusionsFor(pattern, exclude))
+	suspects := broadKillSuspects(processes, matched, appExecutablePaths(app))
+	if len(suspects) == 0 {
+		return true
+	}
+
+	confirmMu.Lock()
+	defer confirmMu.Unlock()
+	fmt.Fprintf(os.Stderr, "Kill pattern %q for %s matches %d processes, %d of them not started from the app:\n", pattern, alias, len(matched), len(suspects))
+	for i, p := range suspects {
+		if i == broadKillThreshold {
+			fmt.Fprintf(os.Stderr, "  ... and %d more\n", len(suspects)-i)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  %-7d %s\n", p.PID, p.Command)
+	}
+	return confirm("Kill them?")
+}
+
+// broadKillSuspects returns the matched processes that need confirmation:
+// those that neither run from one of the app's paths nor descend from a
+// process that does. Without known paths, a long match list is suspect.
+func broadKillSuspects(processes, matched []processInfo, appPaths []string) []processInfo {
+	if len(appPaths) == 0 {
+		if len(matched) > broadKillThreshold {
+			return matched
+		}
+		return nil
+	}
+
+	byPID := map[int]processInfo{}
+	for _, p := range processes {
+		byPID[p.PID] = p
+	}
+
+	var suspects []processInfo
+	for _, p := range matched {
+		owned := false
+		for pid := range ancestors(processes, p.PID) {
+			command := strings.ToLower(byPID[pid].Command)
+			for _, path := range appPaths {
+				if strings.Contains(command, strings.ToLower(path)) {
+					owned = true
+				}
+			}
+		}
+		if !owned {
+			suspects = append(suspects, p)
+		}
+	}
+	return suspects
+}
+
+// appExecutablePaths returns the paths the app runs from: its launch path
+// and, for commands, where they resolve to on PATH
+func appExecutablePaths(app *App) []string {
+	launchPath := app.GetLaunchPath()
+	if !filepath.IsAbs(launchPath) {
+		resolved, err := exec.LookPath(launchPath)
+		if err != nil {
+			return nil
+		}
+		launchPath = resolved
+	}
+
+	paths := []string{launchPath}
+	if real, err := filepath.EvalSymlinks(launchPath); err == nil && real != launchPath {
+		paths = append(paths, real)
+	}
+	return paths
+}
+
+// stopPattern asks all processes matching the pattern to quit, waits up
+// to opts.Timeout for them and their children to exit, then force kills
+// whatever is left of the process trees. HUP and KILL are sent without
+// waiting. It reports what it found and did.
+func stopPattern(pattern string, opts KillOptions) PatternReport {
+	result := PatternReport{Pattern: pattern}
+	target := newKillTarget(pattern, opts.exclude)
+	if !target.running() {
+		return result
+	}
+
+	result.Matched, result.PIDs = true, target.pids
+	result.Method, result.Err = stopTarget(target, pattern, opts)
+	result.Stopped = result.Err == nil && result.Method != KillMethodSignal
+	return result
+}
+
+// stopTarget runs the graceful quit, wait and force kill sequence, or
+// sends HUP or KILL alone, against running processes. It returns how the
+// processes were stopped, and errors when they are still running.
+func stopTarget(target killTarget, label string, opts KillOptions) (string, error) {
+	if opts.Signal == signalHup {
+		if runtime.GOOS == "windows" {
+			return KillMethodSignal, fmt.Errorf("HUP is not supported on Windows")
+		}
+		return KillMethodSignal, target.signal(signalHup)
+	}
+	if opts.saveDocuments {
+		saveBeforeKill(label, opts)
+	}
+	if opts.Signal == signalKill {
+		return KillMethodForced, forceStop(target, label)
+	}
+
+	if err := target.signal(opts.Signal); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: graceful quit of %s failed: %v\n", label, err)
+	} else if target.waitGraceful(opts.Signal, opts.Timeout) {
+		return KillMethodGraceful, nil
+	} else {
+		opts.progress("Still running after %s, force killing: %s", opts.Timeout, label)
+	}
+
+	return KillMethodForced, forceStop(target, label)
+}
+
+// forceStop force kills the target and checks that it is gone
+func forceStop(target killTarget, label string) error {
+	if err := target.force(); err != nil {
+		return fmt.Errorf("failed to force kill %s: %w", label, err)
+	}
+	if !waitUntilStopped(target.running, forceKillWait) {
+		return fmt.Errorf("%s is %w after a force kill", label, ErrStillRunning)
+	}
 	return nil
 }
 
-// killAllByPattern kills all processes matching the given pattern
-func killAllByPattern(pattern string) error {
+// killTarget is how a kill reaches the processes of one pattern
+type killTarget struct {
+	pids    []int // matched processes, when known
+	running func() bool
+	signal  func(signal string) error // "" asks the platform's usual way
+	force   func() error
+
+	// rootsRunning and signalRest, set when the process tree is known,
+	// let helpers outliving the matched processes quit gracefully too
+	rootsRunning func() bool
+	signalRest   func(signal string) error
+}
+
+// newKillTarget targets the processes matching the pattern, minus the
+// excluded ones, and their process trees. Without a process table it falls
+// back to pkill and taskkill, which can't honor exclusions or trees.
+func newKillTarget(pattern string, exclude []string) killTarget {
+	tree, roots, err := matchingProcessTree(pattern, exclusionsFor(pattern, exclude))
+	if err != nil {
+		return killTarget{
+			running: func() bool { return isProcessRunning(pattern) },
+			signal:  func(signal string) error { return requestExit(pattern, signal) },
+			force:   func() error { return forceKill(pattern) },
+		}
+	}
+
+	rootTree := map[int]string{}
+	for _, pid := range roots {
+		rootTree[pid] = tree[pid]
+	}
+	return killTarget{
+		pids:         roots,
+		running:      func() bool { return len(aliveProcesses(tree)) > 0 },
+		signal:       func(signal string) error { return signalProcesses(pattern, roots, signal) },
+		force:        func() error { return killProcesses(tree) },
+		rootsRunning: func() bool { return len(aliveProcesses(rootTree)) > 0 },
+		signalRest: func(signal string) error {
+			if signal == "" {
+				signal = signalTerm
+			}
+			return signalProcesses(pattern, aliveProcesses(tree), signal)
+		},
+	}
+}
+
+// waitGraceful waits up to timeout for the target to quit after its
+// graceful signal. Once the matched processes are gone, what is left of
+// their trees gets the signal too, instead of waiting to be force killed.
+func (t killTarget) waitGraceful(signal string, timeout time.Duration) bool {
+	if t.signalRest == nil {
+		return waitUntilStopped(t.running, timeout)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if !waitUntilStopped(t.rootsRunning, timeout) {
+		return false
+	}
+	if !t.running() {
+		return true
+	}
+	if err := t.signalRest(signal); err != nil {
+		debug("signalling leftover processes failed", "error", err)
+	}
+	return waitUntilStopped(t.running, time.Until(deadline))
+}
+
+// matchingProcessTree returns the processes matching the pattern and all
+// their descendants, by pid, with the command line each pid had, and the
+// pids that matched. Children are collected up front: helpers orphaned by
+// a dying parent no longer show up as its descendants.
+func matchingProcessTree(pattern string, exclude []string) (map[int]string, []int, error) {
+	processes, err := listProcesses()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matched := excludeProcesses(matchingProcesses(processes, pattern), exclude)
+	roots := make([]int, 0, len(matched))
+	for _, p := range matched {
+		roots = append(roots, p.PID)
+	}
+	return processTree(processes, matched, exclude), roots, nil
+}
+
+// processTree returns the roots and all their descendants, minus excluded
+// processes and openx with its parents, by pid with their command lines
+func processTree(processes, roots []processInfo, exclude []string) map[int]string {
+	children := map[int][]processInfo{}
+	for _, p := range processes {
+		children[p.PPID] = append(children[p.PPID], p)
+	}
+	// openx may run inside the app it kills, e.g. its terminal
+	own := ancestors(processes, os.Getpid())
+
+	tree := map[int]string{}
+	queue := roots
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if _, seen := tree[p.PID]; seen || own[p.PID] {
+			continue
+		}
+		tree[p.PID] = p.Command
+		queue = append(queue, excludeProcesses(children[p.PID], exclude)...)
+	}
+	return tree
+}
+
+// aliveProcesses returns the pids of the tree still running the same
+// command, so reused pids are left alone
+func aliveProcesses(tree map[int]string) []int {
+	processes, err := listProcesses()
+	if err != nil {
+		return nil
+	}
+
+	var alive []int
+	for _, p := range processes {
+		if command, ok := tree[p.PID]; ok && command == p.Command {
+			alive = append(alive, p.PID)
+		}
+	}
+	return alive
+}
+
+// signalProcesses asks the given processes to quit: with the signal, or
+// through Apple Events on macOS (when a pattern names the app) and by
+// closing their windows, or else SIGTERM, on Linux. Windows has no signals,
+// so it always sends a close message.
+func signalProcesses(pattern string, pids []int, signal string) error {
+	args := make([]string, 0, 2*len(pids)+1)
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		// GUI apps quit through AppleScript, which lets them save their work
+		if name := killPatternName(pattern); runtime.GOOS == "darwin" && signal == "" && name != "" {
+			if err := quitMacOSApp(name); err == nil {
+				return nil
+			}
+		}
+		// Closing the windows lets Linux apps ask about unsaved work too
+		if runtime.GOOS == "linux" && signal == "" {
+			if err := closeLinuxWindows(pids); err == nil {
+				return nil
+			}
+		}
+		if signal == "" {
+			signal = signalTerm
+		}
+		args = append(args, "-"+signal)
+		for _, pid := range pids {
+			args = append(args, strconv.Itoa(pid))
+		}
+		return exec.Command("kill", args...).Run()
+	case "windows":
+		for _, pid := range pids {
+			args = append(args, strconv.Itoa(pid))
+		}
+		return exec.Command("taskkill", taskkillArgs(false, "/PID", args...)...).Run()
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// killProcesses force kills what is still running of the tree
+func killProcesses(tree map[int]string) error {
+	for _, pid := range aliveProcesses(tree) {
+		if process, err := os.FindProcess(pid); err == nil {
+			process.Kill()
+		}
+	}
+	return nil
+}
+
+// exclusionsFor returns the exclusions that apply to a kill pattern. A
+// pattern naming a protected process on purpose, like code-server, still
+// kills it.
+func exclusionsFor(pattern string, exclude []string) []string {
+	var applied []string
+	for _, e := range exclude {
+		if !strings.Contains(strings.ToLower(pattern), strings.ToLower(e)) {
+			applied = append(applied, e)
+		}
+	}
+	return applied
+}
+
+// excludeProcesses drops the processes whose command line or name
+// contains one of the patterns
+func excludeProcesses(processes []processInfo, patterns []string) []processInfo {
+	if len(patterns) == 0 {
+		return processes
+	}
+
+	var kept []processInfo
+	for _, p := range processes {
+		excluded := false
+		for _, pattern := range patterns {
+			pattern = strings.ToLower(pattern)
+			if pattern != "" && (strings.Contains(strings.ToLower(p.Command), pattern) || strings.Contains(strings.ToLower(p.Name), pattern)) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// waitUntilStopped polls running until it reports false, giving up after
+// timeout
+func waitUntilStopped(running func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for running() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		sleep(killPollInterval)
+	}
+	return true
+}
+
+// requestExit asks matching processes to quit: with the given signal, or
+// by default through Apple Events on macOS and SIGTERM on Linux. Windows
+// has no signals, so both send a close message.
+func requestExit(pattern, signal string) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return killAllMacOS(pattern)
+		// GUI apps quit through AppleScript, which lets them save their work
+		if name := killPatternName(pattern); signal == "" && name != "" {
+			if err := quitMacOSApp(name); err == nil {
+				return nil
+			}
+		}
+		if signal == "" {
+			signal = signalTerm
+		}
+		return exec.Command("pkill", append([]string{"-" + signal}, pkillArgs(pattern)...)...).Run()
 	case "linux":
-		return killAllLinux(pattern)
+		if signal == "" {
+			signal = signalTerm
+		}
+		return exec.Command("pkill", append([]string{"-" + signal}, pkillArgs(pattern)...)...).Run()
 	case "windows":
-		return killAllWindows(pattern)
+		name := killPatternName(pattern)
+		if err := exec.Command("taskkill", taskkillArgs(false, "/IM", name+".exe")...).Run(); err == nil {
+			return nil
+		}
+		return exec.Command("taskkill", taskkillArgs(false, "/IM", name)...).Run()
 	default:
 		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
 }
 
-// killAllMacOS kills all processes on macOS matching the pattern
-func killAllMacOS(pattern string) error {
-	// For macOS apps, try graceful quit first for GUI apps
-	if err := quitMacOSApp(pattern); err == nil {
-		// After graceful quit, check if any processes are still running
-		// and force kill them if needed
-		if isProcessRunning(pattern) {
-			return exec.Command("pkill", "-i", "-f", pattern).Run()
+// taskkillArgs builds a taskkill command line for pids (/PID) or image
+// names (/IM). Without /F taskkill posts WM_CLOSE to the app's windows, so
+// it can ask about unsaved documents; /F terminates it on the spot.
+func taskkillArgs(force bool, flag string, targets ...string) []string {
+	var args []string
+	if force {
+		args = append(args, "/F")
+	}
+	for _, target := range targets {
+		args = append(args, flag, target)
+	}
+	return args
+}
+
+// forceKill kills all processes matching the pattern outright
+func forceKill(pattern string) error {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		return exec.Command("pkill", append([]string{"-KILL"}, pkillArgs(pattern)...)...).Run()
+	case "windows":
+		// Try with .exe extension first
+		name := killPatternName(pattern)
+		if err := exec.Command("taskkill", taskkillArgs(true, "/IM", name+".exe")...).Run(); err == nil {
+			return nil
 		}
-		return nil
+		return exec.Command("taskkill", taskkillArgs(true, "/IM", name)...).Run()
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
+}
 
-	// If graceful quit failed, force kill all matching processes (case-insensitive)
-	return exec.Command("pkill", "-i", "-f", pattern).Run()
+// killPatternName returns the name a kill pattern selects, for tools that
+// match apps by name. Regular expressions have none.
+func killPatternName(pattern string) string {
+	m, err := parseKillPattern(pattern)
+	if err != nil || m.prefix == killRegexPrefix {
+		return ""
+	}
+	return m.value
 }
 
 // quitMacOSApp tries to quit an app gracefully via AppleScript
@@ -94,52 +760,152 @@ func quitMacOSApp(appName string) error {
 	return exec.Command("osascript", "-e", script).Run()
 }
 
-// killAllLinux kills all processes on Linux matching the pattern
-func killAllLinux(pattern string) error {
-	// Use -i flag for case-insensitive matching
-	return exec.Command("pkill", "-i", "-f", pattern).Run()
+// saveBeforeKill saves the open documents of the apps a kill pattern
+// names, so neither the quit dialog nor a force kill loses unsaved work
+func saveBeforeKill(pattern string, opts KillOptions) {
+	name := killPatternName(pattern)
+	if name == "" {
+		return
+	}
+	opts.progress("Saving open documents: %s", name)
+	if err := saveMacOSDocuments(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: saving documents of %s failed: %v\n", name, err)
+	}
 }
 
-// killAllWindows kills all processes on Windows matching the pattern
-func killAllWindows(pattern string) error {
-	// Try with .exe extension first - use /F to force kill all processes
-	if err := exec.Command("taskkill", "/F", "/IM", pattern+".exe").Run(); err == nil {
-		return nil
+// saveMacOSDocuments asks every instance of an app to save its documents.
+// Documents never saved would open a save panel, so each app gets a
+// bounded time to answer.
+func saveMacOSDocuments(appName string) error {
+	script := fmt.Sprintf(`
+		tell application "System Events"
+			set appList to (name of every application process whose name contains "%s")
+		end tell
+		repeat with appProcess in appList
+			try
+				with timeout of %d seconds
+					tell application (appProcess as text) to save every document
+				end timeout
+			end try
+		end repeat`, appName, int(saveDocumentsTimeout.Seconds()))
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+// closeLinuxWindows asks the window manager to close the windows of the
+// given processes, as the window's close button would. It errors when
+// neither wmctrl nor xdotool is installed or no process has a window, so
+// the caller can fall back to SIGTERM.
+func closeLinuxWindows(pids []int) error {
+	var windows []string
+	var closeArgs []string
+	if _, err := exec.LookPath("wmctrl"); err == nil {
+		output, err := exec.Command("wmctrl", "-lp").Output()
+		if err != nil {
+			return fmt.Errorf("failed to list windows: %w", err)
+		}
+		windows = windowsOwnedBy(string(output), pids)
+		closeArgs = []string{"wmctrl", "-i", "-c"}
+	} else if _, err := exec.LookPath("xdotool"); err == nil {
+		for _, pid := range pids {
+			output, _ := exec.Command("xdotool", "search", "--pid", strconv.Itoa(pid)).Output()
+			windows = append(windows, strings.Fields(string(output))...)
+		}
+		closeArgs = []string{"xdotool", "windowquit"}
+	} else {
+		return fmt.Errorf("closing windows needs wmctrl or xdotool installed")
+	}
+
+	if len(windows) == 0 {
+		return fmt.Errorf("no windows to close")
+	}
+	for _, window := range windows {
+		args := append(append([]string{}, closeArgs[1:]...), window)
+		if err := exec.Command(closeArgs[0], args...).Run(); err != nil {
+			return fmt.Errorf("failed to close window %s: %w", window, err)
+		}
+	}
+	return nil
+}
+
+// CloseApps closes several applications at once, so their graceful quit
+// timeouts run side by side, and returns per-app results in alias order
+func CloseApps(aliases []string, opts KillOptions) ([]CloseResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	results, err := closeMultipleApps(config, aliases, opts, defaultKillConcurrency)
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", result.Alias, result.Err)
+		}
 	}
+	return results, err
+}
 
-	// Try without .exe extension - use /F to force kill all processes
-	return exec.Command("taskkill", "/F", "/IM", pattern).Run()
+// closeRunning closes an application like closeApp, counting an app with
+// nothing running as closed rather than failed
+func closeRunning(config *Config, alias string, opts KillOptions) (*KillReport, error) {
+	report, err := closeApp(config, alias, opts)
+	if errors.Is(err, ErrKillNoMatch) {
+		return report, nil
+	}
+	return report, err
 }
 
-// closeMultipleApps closes multiple applications
-func closeMultipleApps(aliases []string) error {
+// closeMultipleApps closes the apps with up to concurrency of them at
+// once, and errors when any of them failed to close. The results come in
+// alias order, printing their errors is up to the caller.
+func closeMultipleApps(config *Config, aliases []string, opts KillOptions, concurrency int) ([]CloseResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]CloseResult, len(aliases))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i, alias := range aliases {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			appOpts := opts
+			if len(aliases) > 1 {
+				appOpts.prefix = alias + ": "
+			}
+			report, err := closeRunning(config, alias, appOpts)
+			results[i] = CloseResult{Alias: alias, Running: report.Killed(), Report: report, Err: err}
+		}()
+	}
+	wg.Wait()
+
 	errors := 0
-	for _, alias := range aliases {
-		if err := CloseApp(alias); err != nil {
-			fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", alias, err)
+	for _, result := range results {
+		if result.Err != nil {
 			errors++
 		}
 	}
 
 	if errors > 0 {
-		return fmt.Errorf("%d apps failed to close", errors)
+		return results, fmt.Errorf("%d apps failed to close", errors)
 	}
 
-	return nil
+	return results, nil
 }
 
 // isProcessRunning checks if a process matching the pattern is running
 func isProcessRunning(pattern string) bool {
 	switch runtime.GOOS {
 	case "darwin", "linux":
-		// Use -i flag for case-insensitive matching
-		cmd := exec.Command("pgrep", "-i", "-f", pattern)
+		cmd := exec.Command("pgrep", pkillArgs(pattern)...)
 		return cmd.Run() == nil
 	case "windows":
-		cmd := exec.Command("tasklist", "/FI", fmt.Sprintf("IMAGENAME eq %s*", pattern))
+		name := killPatternName(pattern)
+		cmd := exec.Command("tasklist", "/FI", fmt.Sprintf("IMAGENAME eq %s*", name))
 		output, err := cmd.Output()
 		// Windows is already case-insensitive by default
-		return err == nil && strings.Contains(string(output), pattern)
+		return err == nil && name != "" && strings.Contains(string(output), name)
 	default:
 		return false
 	}
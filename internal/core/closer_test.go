@@ -1,11 +1,30 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
+// startMarkerProcess starts a shell with marker as its $0, so that only
+// it matches marker, and stops it when the test ends. The script takes
+// its sleep down with it on SIGTERM.
+func startMarkerProcess(t *testing.T, marker string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", "trap 'kill $!; exit' TERM; sleep 30 & wait", marker)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go cmd.Wait()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return cmd
+}
+
 func TestCloseApp(t *testing.T) {
 	// Create a test config
 	testContent := `
@@ -55,7 +74,7 @@ aliases:
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CloseApp(tt.alias)
+			_, err := CloseApp(tt.alias)
 
 			if tt.wantErr {
 				if err == nil {
@@ -101,6 +120,11 @@ aliases:
 	cleanup := setTempConfigPath(t, configPath)
 	defer cleanup()
 
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
 	tests := []struct {
 		name    string
 		aliases []string
@@ -134,7 +158,22 @@ aliases:
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := closeMultipleApps(tt.aliases)
+			results, err := closeMultipleApps(config, tt.aliases, KillOptions{Yes: true}, 2)
+			if len(results) != len(tt.aliases) {
+				t.Fatalf("closeMultipleApps() = %d results, want one per alias", len(results))
+			}
+			errs := 0
+			for i, result := range results {
+				if result.Alias != tt.aliases[i] {
+					t.Errorf("closeMultipleApps()[%d] = %s, want results in alias order", i, result.Alias)
+				}
+				if result.Err != nil {
+					errs++
+				}
+			}
+			if errs < tt.minErrs {
+				t.Errorf("closeMultipleApps() = %d failed apps, want at least %d", errs, tt.minErrs)
+			}
 
 			if tt.wantErr {
 				if err == nil {
@@ -150,29 +189,10 @@ aliases:
 	}
 }
 
-func TestKillAllByPattern(t *testing.T) {
-	tests := []struct {
-		name    string
-		pattern string
-		wantErr bool
-	}{
-		{
-			name:    "nonexistent process",
-			pattern: "nonexistent-app-12345",
-			wantErr: false, // killAllByPattern should not error if process doesn't exist
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := killAllByPattern(tt.pattern)
-			if tt.wantErr && err == nil {
-				t.Errorf("killAllByPattern(%s) expected error but got none", tt.pattern)
-			}
-			if !tt.wantErr && err != nil {
-				t.Errorf("killAllByPattern(%s) unexpected error: %v", tt.pattern, err)
-			}
-		})
+func TestStopPattern_NoMatch(t *testing.T) {
+	result := stopPattern("nonexistent-app-12345", KillOptions{Timeout: time.Second})
+	if result.Matched || result.Err != nil || result.Method != "" {
+		t.Errorf("stopPattern() = %+v, want nothing matched and no error", result)
 	}
 }
 
@@ -221,7 +241,7 @@ func TestCloseApp_ConfigError(t *testing.T) {
 		}
 	}()
 
-	err := CloseApp("testapp")
+	_, err := CloseApp("testapp")
 	if err == nil {
 		t.Error("CloseApp() expected error when config file doesn't exist")
 	}
@@ -422,3 +442,365 @@ func getKillPatternsForApp(alias string) ([]string, error) {
 	killPatterns := app.GetKillPatterns()
 	return killPatterns, nil
 }
+
+func TestKillAllByPattern_Escalation(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses pkill signals on Linux")
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		signal string
+		method string
+	}{
+		// The shell's sleep outlives it and gets the signal in turn
+		{"exits on SIGTERM", "sleep 30", "", KillMethodGraceful},
+		{"ignores SIGTERM and is force killed", `trap "" TERM; while :; do sleep 0.1; done`, "", KillMethodForced},
+		// A shell waiting on a foreground job defers SIGINT, so it traps it
+		{"exits on SIGINT", "trap 'kill $!; exit' INT; sleep 30 & wait", signalInt, KillMethodGraceful},
+		{"KILL skips the grace period", `trap "" TERM; while :; do sleep 0.1; done`, signalKill, KillMethodForced},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The marker becomes $0, so only this process matches
+			marker := fmt.Sprintf("openx-kill-test-%d-%d", os.Getpid(), i)
+			cmd := exec.Command("sh", "-c", tt.script, marker)
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+			exited := make(chan struct{})
+			go func() {
+				cmd.Wait()
+				close(exited)
+			}()
+
+			result := stopPattern(marker, KillOptions{Timeout: 500 * time.Millisecond, Signal: tt.signal})
+			if !result.Matched || result.Err != nil {
+				t.Fatalf("stopPattern() = %+v, want the process stopped", result)
+			}
+			if result.Method != tt.method {
+				t.Errorf("stopPattern() method = %s, want %s", result.Method, tt.method)
+			}
+			select {
+			case <-exited:
+			case <-time.After(5 * time.Second):
+				cmd.Process.Kill()
+				t.Fatal("process still running after stopPattern()")
+			}
+		})
+	}
+}
+
+func TestCloseApp_Report(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-kill-report-%d", os.Getpid())
+	testContent := `
+apps:
+  server:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `", "` + marker + `-idle"]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		signal string
+		method string
+	}{
+		{"graceful quit", "", KillMethodGraceful},
+		{"KILL", signalKill, KillMethodForced},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := startMarkerProcess(t, marker)
+
+			report, err := CloseAppWithOptions("server", KillOptions{Timeout: time.Second, Signal: tt.signal, Yes: true})
+			if err != nil {
+				t.Fatalf("CloseAppWithOptions() unexpected error: %v", err)
+			}
+			if !report.Killed() || len(report.Patterns) != 2 {
+				t.Fatalf("CloseAppWithOptions() report = %+v, want both patterns with a kill", report)
+			}
+
+			got := report.Patterns[0]
+			if !got.Matched || !got.Stopped || got.Method != tt.method || fmt.Sprint(got.PIDs) != fmt.Sprint([]int{cmd.Process.Pid}) {
+				t.Errorf("report for %s = %+v, want pid %d stopped by %s", marker, got, cmd.Process.Pid, tt.method)
+			}
+			if idle := report.Patterns[1]; idle.Matched || idle.Method != "" {
+				t.Errorf("report for the idle pattern = %+v, want nothing matched", idle)
+			}
+		})
+	}
+
+	// Nothing running errors with ErrKillNoMatch, and nothing is killed
+	report, err := CloseAppWithOptions("server", KillOptions{Yes: true})
+	if !errors.Is(err, ErrKillNoMatch) || report.Killed() {
+		t.Errorf("CloseAppWithOptions() = %+v, %v, want an empty report and ErrKillNoMatch", report, err)
+	}
+}
+
+func TestCloseApp_Protected(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-kill-protected-%d", os.Getpid())
+	startMarkerProcess(t, marker)
+
+	testContent := `
+apps:
+  vault:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `"]
+    protected: true
+
+groups:
+  work: [vault]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	opts := KillOptions{Timeout: time.Second, Yes: true}
+	report, err := CloseAppWithOptions("vault", opts)
+	if err == nil || !report.Protected || report.Killed() {
+		t.Fatalf("CloseAppWithOptions() = %+v, %v, want a refusal", report, err)
+	}
+
+	// Group kills skip protected members without failing
+	results, err := CloseGroup("work", opts)
+	if err != nil {
+		t.Fatalf("CloseGroup() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Report == nil || !results[0].Report.Protected {
+		t.Errorf("CloseGroup() = %+v, want vault left alone", results)
+	}
+	if !isProcessRunning(marker) {
+		t.Fatal("protected app should still be running")
+	}
+
+	opts.Force = true
+	report, err = CloseAppWithOptions("vault", opts)
+	if err != nil || !report.Killed() {
+		t.Errorf("CloseAppWithOptions() with Force = %+v, %v, want the app closed", report, err)
+	}
+}
+
+func TestPatternsRunning(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-patterns-running-%d", os.Getpid())
+	startMarkerProcess(t, marker)
+
+	if !patternsRunning([]string{"openx-none-" + marker, marker}, nil) {
+		t.Error("patternsRunning() = false, want true while the process runs")
+	}
+	if patternsRunning([]string{marker}, []string{"sleep 30"}) {
+		t.Error("patternsRunning() = true, want excluded processes ignored")
+	}
+
+	testContent := `
+apps:
+  server:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `"]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if _, err := CloseAppWithOptions("server", KillOptions{Timeout: time.Second, Yes: true, Wait: true}); err != nil {
+		t.Fatalf("CloseAppWithOptions(Wait) unexpected error: %v", err)
+	}
+	if patternsRunning([]string{marker}, nil) {
+		t.Error("CloseAppWithOptions(Wait) returned while matching processes remain")
+	}
+}
+
+func TestKillAllByPattern_HUP(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses pkill signals on Linux")
+	}
+
+	// A daemon that reloads on HUP keeps running
+	marker := fmt.Sprintf("openx-hup-test-%d", os.Getpid())
+	cmd := exec.Command("sh", "-c", `trap "" HUP; while :; do sleep 0.1; done`, marker)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	if result := stopPattern(marker, KillOptions{Signal: signalHup}); !result.Matched || result.Err != nil {
+		t.Fatalf("stopPattern(HUP) = %+v, want the signal sent", result)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if !isProcessRunning(marker) {
+		t.Error("HUP should not stop or force kill the process")
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"TERM", signalTerm, false},
+		{"sigint", signalInt, false},
+		{"SIGHUP", signalHup, false},
+		{"kill", signalKill, false},
+		{"USR1", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSignal(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseSignal(%q) = %q, %v, want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTaskkillArgs(t *testing.T) {
+	tests := []struct {
+		force   bool
+		flag    string
+		targets []string
+		want    string
+	}{
+		{false, "/PID", []string{"812", "830"}, "/PID 812 /PID 830"},
+		{false, "/IM", []string{"notepad.exe"}, "/IM notepad.exe"},
+		{true, "/IM", []string{"notepad.exe"}, "/F /IM notepad.exe"},
+	}
+
+	for _, tt := range tests {
+		got := strings.Join(taskkillArgs(tt.force, tt.flag, tt.targets...), " ")
+		if got != tt.want {
+			t.Errorf("taskkillArgs(%v, %s, %v) = %q, want %q", tt.force, tt.flag, tt.targets, got, tt.want)
+		}
+	}
+}
+
+func TestBroadKillSuspects(t *testing.T) {
+	processes := []processInfo{
+		{PID: 1, PPID: 0, Command: "/sbin/init"},
+		{PID: 100, PPID: 1, Command: "/opt/editor/bin/node /opt/editor/main.js"},
+		{PID: 101, PPID: 100, Command: "node --type=extension-host"},
+		{PID: 200, PPID: 1, Command: "node build-server.js"},
+	}
+	matched := processes[1:]
+
+	suspects := broadKillSuspects(processes, matched, []string{"/opt/editor/bin/node"})
+	if len(suspects) != 1 || suspects[0].PID != 200 {
+		t.Errorf("broadKillSuspects() = %+v, want only the unrelated build server", suspects)
+	}
+
+	// Without the app's path only long match lists are suspect
+	if suspects := broadKillSuspects(processes, matched, nil); len(suspects) != 0 {
+		t.Errorf("broadKillSuspects() without paths = %+v, want none for %d matches", suspects, len(matched))
+	}
+	many := make([]processInfo, broadKillThreshold+1)
+	if suspects := broadKillSuspects(processes, many, nil); len(suspects) != len(many) {
+		t.Errorf("broadKillSuspects() without paths = %d suspects, want all %d", len(suspects), len(many))
+	}
+}
+
+func TestKillAllByPattern_ProcessTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses pkill signals on Linux")
+	}
+
+	// The parent exits on SIGTERM and orphans its child, as app helpers do
+	marker := fmt.Sprintf("openx-tree-test-%d", os.Getpid())
+	cmd := exec.Command("sh", "-c", `sleep 300 & echo $!; wait`, marker)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go cmd.Wait()
+
+	var child int
+	if _, err := fmt.Fscan(stdout, &child); err != nil {
+		t.Fatalf("failed to read child pid: %v", err)
+	}
+	childProcess, _ := os.FindProcess(child)
+	defer childProcess.Kill()
+
+	if result := stopPattern(marker, KillOptions{Timeout: 500 * time.Millisecond}); !result.Matched || result.Err != nil {
+		t.Fatalf("stopPattern() = %+v, want the process stopped", result)
+	}
+
+	processes, err := listProcesses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range processes {
+		if p.PID == child && strings.Contains(p.Command, "sleep 300") {
+			t.Errorf("child %d of the killed process is still running", child)
+		}
+	}
+}
+
+func TestExcludeProcesses(t *testing.T) {
+	processes := []processInfo{
+		{PID: 1, Name: "code", Command: "/usr/share/code/code --unity-launch"},
+		{PID: 2, Name: "node", Command: "/home/dev/.vscode-server/bin/node server-main.js"},
+		{PID: 3, Name: "code-server", Command: "/usr/lib/code-server/lib/node /usr/lib/code-server"},
+	}
+
+	kept := excludeProcesses(processes, exclusionsFor("code", defaultProtectedProcesses))
+	if len(kept) != 1 || kept[0].PID != 1 {
+		t.Errorf("excludeProcesses() = %+v, want only the desktop editor", kept)
+	}
+
+	// Naming a protected process on purpose still reaches it
+	kept = excludeProcesses(processes, exclusionsFor("code-server", defaultProtectedProcesses))
+	if len(kept) != 2 || kept[1].PID != 3 {
+		t.Errorf("excludeProcesses(code-server) = %+v, want code-server kept", kept)
+	}
+}
+
+func TestKillAllByPattern_Exclude(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-exclude-test-%d", os.Getpid())
+	app := exec.Command("sh", "-c", "sleep 30", marker+"-app")
+	daemon := exec.Command("sh", "-c", "sleep 30", marker+"-daemon")
+	for _, cmd := range []*exec.Cmd{app, daemon} {
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		go cmd.Wait()
+		defer cmd.Process.Kill()
+	}
+
+	opts := KillOptions{Timeout: 500 * time.Millisecond, exclude: []string{"-daemon"}}
+	if result := stopPattern(marker, opts); !result.Matched || result.Err != nil {
+		t.Fatalf("stopPattern() = %+v, want the process stopped", result)
+	}
+
+	if isProcessRunning(marker + "-app") {
+		t.Error("matching process should be killed")
+	}
+	if !isProcessRunning(marker + "-daemon") {
+		t.Error("excluded process should keep running")
+	}
+}
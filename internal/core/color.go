@@ -0,0 +1,69 @@
+package core
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI color codes, empty when colors are off: when NO_COLOR is set, when
+// stdout isn't a terminal, as when piped into a file or CI log, and after
+// SetColor(false). Reports written to a writer drop them unless it is a
+// terminal, see plainWriter.
+var (
+	ColorReset  = colorCode(ansiReset)
+	ColorRed    = colorCode(ansiRed)
+	ColorGreen  = colorCode(ansiGreen)
+	ColorYellow = colorCode(ansiYellow)
+	ColorGray   = colorCode(ansiGray)
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiGray   = "\033[90m"
+)
+
+// SetColor turns the colors of openx's output on or off, whatever NO_COLOR
+// and the terminal say
+func SetColor(enabled bool) {
+	if !enabled {
+		ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray = "", "", "", "", ""
+		return
+	}
+	ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray = ansiReset, ansiRed, ansiGreen, ansiYellow, ansiGray
+}
+
+// colorCode returns code when output starts out colored, "" otherwise.
+// Any non-empty NO_COLOR turns colors off, see https://no-color.org.
+func colorCode(code string) string {
+	if os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout) {
+		return ""
+	}
+	return code
+}
+
+// ansiCodes removes the color codes openx writes
+var ansiCodes = strings.NewReplacer(ansiReset, "", ansiRed, "", ansiGreen, "", ansiYellow, "", ansiGray, "")
+
+// plainWriter returns w itself when it is a terminal, and otherwise a
+// writer dropping the color codes, so reports written to files and
+// buffers stay plain whatever stdout is
+func plainWriter(w io.Writer) io.Writer {
+	if f, ok := w.(*os.File); ok && isTerminal(f) {
+		return w
+	}
+	return colorStripper{w}
+}
+
+// colorStripper writes to w without the color codes
+type colorStripper struct{ w io.Writer }
+
+func (s colorStripper) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(s.w, ansiCodes.Replace(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
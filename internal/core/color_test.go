@@ -0,0 +1,81 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetColor(t *testing.T) {
+	saved := []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray}
+	defer func() {
+		ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray = saved[0], saved[1], saved[2], saved[3], saved[4]
+	}()
+
+	report := DoctorReport{
+		Platform: "test",
+		Apps: []AppStatus{
+			{Name: "chrome", LaunchPath: "/opt/chrome", Status: "available", Running: true},
+			{Name: "slack", Status: "missing", LaunchIssue: "not executable"},
+		},
+		Summary: Summary{Total: 2, Available: 1, Missing: 1, Running: 1},
+	}
+
+	tests := []struct {
+		enabled bool
+		want    bool // escape codes in the report
+	}{
+		{true, true},
+		{false, false},
+	}
+	for _, tt := range tests {
+		SetColor(tt.enabled)
+		var out bytes.Buffer
+		if err := outputHuman(&out, report); err != nil {
+			t.Fatalf("outputHuman() error: %v", err)
+		}
+		if got := strings.Contains(out.String(), "\033["); got != tt.want {
+			t.Errorf("SetColor(%v): escape codes in the report = %v, want %v", tt.enabled, got, tt.want)
+		}
+		if !strings.Contains(out.String(), "chrome") {
+			t.Errorf("SetColor(%v): report lost its content:\n%s", tt.enabled, out.String())
+		}
+	}
+}
+
+func TestColorCode_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if got := colorCode(ansiRed); got != "" {
+		t.Errorf("colorCode() with NO_COLOR = %q, want none", got)
+	}
+}
+
+func TestWriteDoctor_PlainWriter(t *testing.T) {
+	saved := []string{ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray}
+	defer func() {
+		ColorReset, ColorRed, ColorGreen, ColorYellow, ColorGray = saved[0], saved[1], saved[2], saved[3], saved[4]
+	}()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	configPath := setupTestConfig(t, `
+apps:
+  ghost:
+    linux: /nonexistent/ghost
+    darwin: /nonexistent/ghost
+    windows: C:\nonexistent\ghost.exe`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	// Colors on, as when stdout is a terminal, but the writer is a buffer
+	SetColor(true)
+	var out bytes.Buffer
+	if err := WriteDoctor(&out, DoctorFormatHuman); err != nil {
+		t.Fatalf("WriteDoctor() error: %v", err)
+	}
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("WriteDoctor() to a buffer wrote escape codes:\n%q", out.String())
+	}
+	if !strings.Contains(out.String(), "ghost") {
+		t.Errorf("WriteDoctor() lost the report's content:\n%s", out.String())
+	}
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Completions are the names shell completion offers, read from the config
+// each time so new apps complete without reloading the shell
+type Completions struct {
+	Names    []string // apps, aliases, groups and the synonyms of configured apps
+	Apps     []string
+	Aliases  []string // the config's aliases
+	Groups   []string
+	Projects []string
+	Tags     []string
+}
+
+// LoadCompletions reads the names shell completion offers from the config
+func LoadCompletions() (*Completions, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return completionsOf(config), nil
+}
+
+// completionsOf collects the names in cfg, each list sorted without
+// duplicates
+func completionsOf(cfg *Config) *Completions {
+	names := map[string]bool{}
+	tags := map[string]bool{}
+	for name, app := range cfg.Apps {
+		names[name] = true
+		for _, tag := range app.Tags {
+			tags[tag] = true
+		}
+	}
+	for alias := range cfg.Aliases {
+		names[alias] = true
+	}
+	for group := range cfg.Groups {
+		names[group] = true
+	}
+	// Synonyms of apps missing from the config would only fail to launch
+	for synonym, target := range newAliasResolver(cfg).synonyms {
+		if _, ok := cfg.Apps[target]; ok {
+			names[synonym] = true
+		}
+	}
+
+	return &Completions{
+		Names:    slices.Sorted(maps.Keys(names)),
+		Apps:     slices.Sorted(maps.Keys(cfg.Apps)),
+		Aliases:  slices.Sorted(maps.Keys(cfg.Aliases)),
+		Groups:   slices.Sorted(maps.Keys(cfg.Groups)),
+		Projects: slices.Sorted(maps.Keys(cfg.Projects)),
+		Tags:     slices.Sorted(maps.Keys(tags)),
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletionsOf(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"postman": {Tags: []string{"api"}},
+			"chrome":  {Tags: []string{"browsers", "api"}},
+		},
+		Aliases:  map[string]string{"web": "chrome"},
+		Groups:   map[string][]GroupMember{"work": {{App: "chrome"}}},
+		Projects: map[string]Project{"api": {Editor: "vscode", Path: "~/src/api"}},
+	}
+
+	got := completionsOf(cfg)
+	want := &Completions{
+		// gc and pm are synonyms of configured apps; code's vscode isn't configured
+		Names:    []string{"chrome", "gc", "pm", "postman", "web", "work"},
+		Apps:     []string{"chrome", "postman"},
+		Aliases:  []string{"web"},
+		Groups:   []string{"work"},
+		Projects: []string{"api"},
+		Tags:     []string{"api", "browsers"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("completionsOf() = %+v, want %+v", got, want)
+	}
+}
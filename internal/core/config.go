@@ -4,11 +4,32 @@ import (
 	"openx/shared/config"
 )
 
+const (
+	uwpPrefix      = config.UWPPrefix
+	desktopPrefix  = config.DesktopPrefix
+	snapPrefix     = config.SnapPrefix
+	appImagePrefix = config.AppImagePrefix
+	appTypeDocker  = config.AppTypeDocker
+
+	killExactPrefix = config.KillExactPrefix
+	killWordPrefix  = config.KillWordPrefix
+	killRegexPrefix = config.KillRegexPrefix
+)
+
 // Re-export types and functions from shared config for backward compatibility
 type Config = config.Config
 type App = config.App
+type GroupMember = config.GroupMember
+type Limits = config.Limits
+type Project = config.Project
+type ParseError = config.ParseError
+type Problem = config.Problem
+
+var ErrConfigNotFound = config.ErrConfigNotFound
 
 var loadConfig = config.LoadConfig
 var saveConfig = config.SaveConfig
+var entryLine = config.EntryLine
 var GetVersion = config.GetVersion
 var processNameExceptions = config.ProcessNameExceptions
+var groupMembers = config.GroupMembers
@@ -37,6 +37,9 @@ func setTempConfigPath(t *testing.T, path string) func() {
 	tempDir := filepath.Dir(filepath.Dir(path))
 	os.Setenv("XDG_CONFIG_HOME", tempDir)
 
+	// Keep logs and session state of launches out of the real state dir
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
 	return func() {
 		if oldXDG != "" {
 			os.Setenv("XDG_CONFIG_HOME", oldXDG)
@@ -508,3 +511,29 @@ func TestDeriveKillPatterns_E2E_Success(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapKillPatterns(t *testing.T) {
+	tests := []struct {
+		launchPath string
+		expected   string
+	}{
+		{"snap:postman", "/snap/postman/"},
+		{"/snap/bin/postman", "/snap/postman/"},
+		{"/snap/bin/code.url-handler", "/snap/code/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.launchPath, func(t *testing.T) {
+			app := &App{Paths: map[string]string{
+				"darwin":  tt.launchPath,
+				"linux":   tt.launchPath,
+				"windows": tt.launchPath,
+			}}
+
+			patterns := app.DeriveKillPatterns()
+			if len(patterns) != 1 || patterns[0] != tt.expected {
+				t.Errorf("DeriveKillPatterns() = %v, want [%s]", patterns, tt.expected)
+			}
+		})
+	}
+}
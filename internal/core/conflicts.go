@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"openx/shared/config"
+)
+
+// Kinds of Conflict
+const (
+	ConflictDuplicate = "duplicate" // apps launching the same executable
+	ConflictShadowed  = "shadowed"  // alias hidden by an app of the same name
+	ConflictSynonym   = "synonym"   // alias overriding a built-in synonym
+)
+
+// Conflict is a config entry that silently hides or repeats another, with
+// a suggested resolution
+type Conflict struct {
+	Kind   string   `json:"kind" yaml:"kind"`
+	Names  []string `json:"names" yaml:"names"`
+	Detail string   `json:"detail" yaml:"detail"`
+	Fix    string   `json:"fix" yaml:"fix"`
+}
+
+// checkConflicts finds apps that resolve to the same executable, aliases
+// hidden by an app name, and aliases that override a built-in synonym.
+// Duplicates come first, then the alias conflicts by alias.
+func checkConflicts(cfg *config.Config) []Conflict {
+	var conflicts []Conflict
+
+	byExecutable := map[string][]string{}
+	for name, app := range cfg.Apps {
+		if isDockerApp(app) {
+			continue
+		}
+		if key := executableKey(app.GetLaunchPath()); key != "" {
+			byExecutable[key] = append(byExecutable[key], name)
+		}
+	}
+	for _, names := range byExecutable {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		conflicts = append(conflicts, Conflict{
+			Kind:   ConflictDuplicate,
+			Names:  names,
+			Detail: fmt.Sprintf("%s launch the same executable, %s", strings.Join(names, " and "), cfg.Apps[names[0]].GetLaunchPath()),
+			Fix:    fmt.Sprintf("keep %s and replace the others with aliases to it", names[0]),
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Names[0] < conflicts[j].Names[0] })
+
+	aliases := make([]string, 0, len(cfg.Aliases))
+	for alias := range cfg.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	synonyms := newAliasResolver(cfg).synonyms
+	for _, alias := range aliases {
+		target := cfg.Aliases[alias]
+		if _, shadowed := cfg.Apps[alias]; shadowed {
+			conflicts = append(conflicts, Conflict{
+				Kind:   ConflictShadowed,
+				Names:  []string{alias},
+				Detail: fmt.Sprintf("alias %s → %s is hidden by the app named %s", alias, target, alias),
+				Fix:    fmt.Sprintf("rename the alias, or open %s by its app name", target),
+			})
+		}
+		if builtin, ok := synonyms[alias]; ok && builtin != target {
+			conflicts = append(conflicts, Conflict{
+				Kind:   ConflictSynonym,
+				Names:  []string{alias},
+				Detail: fmt.Sprintf("alias %s → %s overrides the built-in synonym %s → %s", alias, target, alias, builtin),
+				Fix:    fmt.Sprintf("remove the alias to get %s, or keep it if %s is intended", builtin, target),
+			})
+		}
+	}
+	return conflicts
+}
+
+// executableKey identifies the executable a launch path starts, resolving
+// commands on PATH and symlinks; it returns "" for URLs, Store apps and
+// commands not on PATH, which can't be compared
+func executableKey(path string) string {
+	if path == "" || isURL(path) {
+		return ""
+	}
+	if _, ok := uwpAppID(path); ok {
+		return ""
+	}
+	if !strings.ContainsAny(path, `/\`) && !strings.Contains(path, ":") {
+		resolved, err := exec.LookPath(path)
+		if err != nil {
+			return ""
+		}
+		path = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	path = filepath.Clean(path)
+	if runtime.GOOS != "linux" {
+		// macOS and Windows file systems are case-insensitive by default
+		path = strings.ToLower(path)
+	}
+	return path
+}
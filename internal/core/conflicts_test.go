@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"openx/shared/config"
+)
+
+func TestCheckConflicts(t *testing.T) {
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "editor-link")
+	if err := os.Symlink(editor, link); err != nil {
+		t.Skip("symlinks unavailable")
+	}
+
+	cfg := &config.Config{
+		Apps: map[string]*config.App{
+			"vscode":   {Paths: map[string]string{runtime.GOOS: editor}},
+			"code-oss": {Paths: map[string]string{runtime.GOOS: link}},
+			"cursor":   {Paths: map[string]string{runtime.GOOS: filepath.Join(dir, "cursor")}},
+			"pg":       {Type: "docker", Image: "postgres:16"},
+			"db":       {Type: "docker", Image: "postgres:16"},
+		},
+		Aliases: map[string]string{
+			"code":   "cursor",
+			"cursor": "vscode",
+			"gc":     "chrome",
+		},
+	}
+
+	want := []Conflict{
+		{
+			Kind:   ConflictDuplicate,
+			Names:  []string{"code-oss", "vscode"},
+			Detail: "code-oss and vscode launch the same executable, " + link,
+			Fix:    "keep code-oss and replace the others with aliases to it",
+		},
+		{
+			Kind:   ConflictSynonym,
+			Names:  []string{"code"},
+			Detail: "alias code → cursor overrides the built-in synonym code → vscode",
+			Fix:    "remove the alias to get vscode, or keep it if cursor is intended",
+		},
+		{
+			Kind:   ConflictShadowed,
+			Names:  []string{"cursor"},
+			Detail: "alias cursor → vscode is hidden by the app named cursor",
+			Fix:    "rename the alias, or open vscode by its app name",
+		},
+	}
+	if got := checkConflicts(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkConflicts() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// desktopEntryID returns the desktop file id of a desktop:<id> launch
+// path, always with its .desktop suffix
+func desktopEntryID(launchPath string) (string, bool) {
+	if !strings.HasPrefix(launchPath, desktopPrefix) {
+		return "", false
+	}
+	id := strings.TrimPrefix(launchPath, desktopPrefix)
+	if id == "" {
+		return "", false
+	}
+	if !strings.HasSuffix(id, ".desktop") {
+		id += ".desktop"
+	}
+	return id, true
+}
+
+// desktopEntryDirs returns the directories holding application .desktop
+// files, most specific first, following the XDG base directory spec
+func desktopEntryDirs() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(getHomeDir(), ".local", "share")
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+
+	dirs := []string{filepath.Join(dataHome, "applications")}
+	for _, dir := range filepath.SplitList(dataDirs) {
+		if dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "applications"))
+		}
+	}
+
+	// Flatpak and snap exports, in case XDG_DATA_DIRS doesn't list them
+	for _, dir := range []string{"/var/lib/flatpak/exports/share/applications", "/var/lib/snapd/desktop/applications"} {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// findDesktopEntry returns the path of the .desktop file with the given id
+func findDesktopEntry(id string, dirs []string) (string, bool) {
+	for _, dir := range dirs {
+		file := filepath.Join(dir, id)
+		if exists(file) {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// desktopEntryCommand launches a .desktop entry through gtk-launch, or
+// gio launch when gtk-launch isn't installed, so the entry's Exec line,
+// wrappers and D-Bus activation are honoured
+func desktopEntryCommand(id string, args []string) *exec.Cmd {
+	if _, err := exec.LookPath("gtk-launch"); err == nil {
+		return exec.Command("gtk-launch", append([]string{id}, args...)...)
+	}
+
+	// gio wants the full path of the entry
+	file, ok := findDesktopEntry(id, desktopEntryDirs())
+	if !ok {
+		file = id
+	}
+	return exec.Command("gio", append([]string{"launch", file}, args...)...)
+}
+
+// desktopEntryKillPatterns derives kill patterns from the entry's Exec line
+func desktopEntryKillPatterns(id string) []string {
+	file, ok := findDesktopEntry(id, desktopEntryDirs())
+	if !ok {
+		return []string{strings.TrimSuffix(id, ".desktop")}
+	}
+
+	entry, err := readDesktopEntry(file)
+	if err != nil || entry["Exec"] == "" {
+		return []string{strings.TrimSuffix(id, ".desktop")}
+	}
+	return []string{execProgramName(entry["Exec"])}
+}
+
+// execProgramName returns the program an Exec line runs, skipping env
+// wrappers. Flatpak apps are matched by their application id.
+func execProgramName(execLine string) string {
+	fields := splitExecLine(execLine)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if field == "env" || strings.Contains(field, "=") {
+			continue
+		}
+
+		program := filepath.Base(field)
+		if program == "flatpak" {
+			// flatpak run [--options] <app-id> [args]
+			for _, arg := range fields[i+1:] {
+				if arg != "run" && !strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "@@") {
+					return arg
+				}
+			}
+		}
+		return program
+	}
+	return ""
+}
+
+// splitExecLine splits an Exec line into arguments, honouring double quotes
+func splitExecLine(execLine string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes, inField := false, false
+
+	for i := 0; i < len(execLine); i++ {
+		c := execLine[i]
+		switch {
+		case c == '\\' && inQuotes && i+1 < len(execLine):
+			i++
+			current.WriteByte(execLine[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteByte(c)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
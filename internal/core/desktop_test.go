@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDesktopEntryID(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"desktop:firefox.desktop", "firefox.desktop", true},
+		{"desktop:org.gnome.Nautilus", "org.gnome.Nautilus.desktop", true},
+		{"desktop:", "", false},
+		{"/usr/bin/firefox", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			id, ok := desktopEntryID(tt.path)
+			if id != tt.expected || ok != tt.ok {
+				t.Errorf("desktopEntryID(%q) = %q, %v, want %q, %v", tt.path, id, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestExecProgramName(t *testing.T) {
+	tests := []struct {
+		execLine string
+		expected string
+	}{
+		{"/usr/lib/firefox/firefox %u", "firefox"},
+		{`"/opt/My App/app" --flag`, "app"},
+		{"env GDK_BACKEND=x11 code --unity-launch %F", "code"},
+		{"/usr/bin/flatpak run --branch=stable --command=obsidian md.obsidian.Obsidian @@u %U @@", "md.obsidian.Obsidian"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.execLine, func(t *testing.T) {
+			if got := execProgramName(tt.execLine); got != tt.expected {
+				t.Errorf("execProgramName(%q) = %q, want %q", tt.execLine, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDesktopEntryKillPatterns(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	appsDir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(appsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	entry := "[Desktop Entry]\nType=Application\nName=Firefox\nExec=/usr/lib/firefox/firefox %u\n"
+	if err := os.WriteFile(filepath.Join(appsDir, "firefox.desktop"), []byte(entry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if file, ok := findDesktopEntry("firefox.desktop", desktopEntryDirs()); !ok || filepath.Dir(file) != appsDir {
+		t.Errorf("findDesktopEntry() = %q, %v, want entry in %s", file, ok, appsDir)
+	}
+
+	if patterns := desktopEntryKillPatterns("firefox.desktop"); len(patterns) != 1 || patterns[0] != "firefox" {
+		t.Errorf("desktopEntryKillPatterns() = %v, want [firefox]", patterns)
+	}
+
+	// Unknown entries fall back to the entry's id
+	if patterns := desktopEntryKillPatterns("missing.desktop"); len(patterns) != 1 || patterns[0] != "missing" {
+		t.Errorf("desktopEntryKillPatterns() = %v, want [missing]", patterns)
+	}
+}
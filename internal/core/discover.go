@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// appPathsKeys are the registry keys Windows resolves bare executable
+// names through, machine-wide and per user
+var appPathsKeys = []string{
+	`HKLM\Software\Microsoft\Windows\CurrentVersion\App Paths`,
+	`HKCU\Software\Microsoft\Windows\CurrentVersion\App Paths`,
+}
+
+// windowsEnvVar matches %VAR% references in REG_EXPAND_SZ values
+var windowsEnvVar = regexp.MustCompile(`%[^%]+%`)
+
+// DiscoverApps finds the applications installed on this machine: the
+// Applications folders and the Spotlight index on macOS, Program Files,
+// the App Paths registry key and the Start Menu on Windows, and the
+// .desktop database on Linux. Each app is reported once, from the first
+// source that has it, sorted by name.
+func DiscoverApps() ([]ScannedApp, error) {
+	home := getHomeDir()
+
+	var sources [][]ScannedApp
+	switch runtime.GOOS {
+	case "darwin":
+		sources = append(sources, scanMacApplications([]string{
+			"/Applications",
+			filepath.Join(home, "Applications"),
+		}))
+		// Spotlight also knows apps kept outside the Applications folders
+		if output, err := exec.Command("mdfind", "kMDItemContentType == 'com.apple.application-bundle'").Output(); err == nil {
+			sources = append(sources, parseSpotlightApps(string(output)))
+		}
+	case "linux":
+		sources = append(sources, scanDesktopEntries(desktopEntryDirs()))
+	case "windows":
+		sources = append(sources, scanWindowsPrograms(
+			[]string{os.Getenv("ProgramFiles"), os.Getenv("ProgramFiles(x86)"), filepath.Join(os.Getenv("LOCALAPPDATA"), "Programs")},
+			nil,
+		))
+		for _, key := range appPathsKeys {
+			if output, err := exec.Command("reg", "query", key, "/s").Output(); err == nil {
+				sources = append(sources, parseAppPaths(string(output)))
+			}
+		}
+		sources = append(sources, scanWindowsPrograms(nil, []string{
+			filepath.Join(os.Getenv("ProgramData"), "Microsoft", "Windows", "Start Menu", "Programs"),
+			filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs"),
+		}))
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+
+	return mergeDiscovered(sources...), nil
+}
+
+// RunDiscover prints the applications installed on this machine
+func RunDiscover(jsonOutput bool) error {
+	apps, err := DiscoverApps()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(apps)
+	}
+
+	fmt.Printf("%-32s %-11s %s\n", "NAME", "SOURCE", "PATH")
+	for _, app := range apps {
+		fmt.Printf("%-32s %-11s %s\n", app.Name, app.Source, app.Path)
+	}
+	fmt.Printf("\n%d applications found\n", len(apps))
+	return nil
+}
+
+// mergeDiscovered joins the apps of several sources, keeping the first app
+// of each normalized name, and sorts them by name
+func mergeDiscovered(sources ...[]ScannedApp) []ScannedApp {
+	apps := []ScannedApp{}
+	seen := map[string]bool{}
+	for _, source := range sources {
+		for _, app := range source {
+			key := normalizeAppName(app.Name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			apps = append(apps, app)
+		}
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+	return apps
+}
+
+// parseSpotlightApps reads mdfind's list of application bundles, leaving
+// out system components and apps nested inside other bundles
+func parseSpotlightApps(output string) []ScannedApp {
+	var apps []ScannedApp
+	for _, path := range strings.Split(output, "\n") {
+		path = strings.TrimSpace(path)
+		if !strings.HasSuffix(path, ".app") || strings.Contains(strings.TrimSuffix(path, ".app"), ".app/") {
+			continue
+		}
+		if strings.HasPrefix(path, "/System/Library/") || strings.HasPrefix(path, "/Library/") {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".app")
+		apps = append(apps, ScannedApp{Name: name, Path: path, Source: SourceSpotlight})
+	}
+	return apps
+}
+
+// parseAppPaths reads `reg query <App Paths> /s` output: a key per
+// executable whose default value is the executable's full path
+func parseAppPaths(output string) []ScannedApp {
+	var apps []ScannedApp
+	name := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "HKEY_") {
+			name = line[strings.LastIndex(line, `\`)+1:]
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+			continue
+		}
+
+		fields := strings.SplitN(strings.TrimSpace(line), "    ", 3)
+		if name == "" || len(fields) != 3 || fields[0] != "(Default)" {
+			continue
+		}
+		path := strings.Trim(strings.TrimSpace(fields[2]), `"`)
+		if path == "" || strings.Contains(strings.ToLower(name), "unins") {
+			continue
+		}
+		path = windowsEnvVar.ReplaceAllStringFunc(path, func(v string) string {
+			if value, ok := os.LookupEnv(strings.Trim(v, "%")); ok {
+				return value
+			}
+			return v
+		})
+		apps = append(apps, ScannedApp{Name: name, Path: path, Source: SourceAppPaths})
+		name = ""
+	}
+	return apps
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSpotlightApps(t *testing.T) {
+	output := `/Applications/Slack.app
+/Users/dev/Tools/Insomnia.app
+/Applications/Xcode.app/Contents/Applications/Instruments.app
+/System/Library/CoreServices/Finder.app
+/Library/Application Support/Helper.app
+/Users/dev/notes.txt
+`
+
+	want := []ScannedApp{
+		{Name: "Slack", Path: "/Applications/Slack.app", Source: SourceSpotlight},
+		{Name: "Insomnia", Path: "/Users/dev/Tools/Insomnia.app", Source: SourceSpotlight},
+	}
+	if got := parseSpotlightApps(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSpotlightApps() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAppPaths(t *testing.T) {
+	t.Setenv("OPENX_TEST_PROGRAMS", `C:\Program Files`)
+	output := "\r\n" +
+		`HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\App Paths\chrome.exe` + "\r\n" +
+		`    (Default)    REG_SZ    C:\Program Files\Google\Chrome\Application\chrome.exe` + "\r\n" +
+		`    Path    REG_SZ    C:\Program Files\Google\Chrome\Application` + "\r\n" +
+		"\r\n" +
+		`HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\App Paths\notepad++.exe` + "\r\n" +
+		`    (Default)    REG_EXPAND_SZ    "%OPENX_TEST_PROGRAMS%\Notepad++\notepad++.exe"` + "\r\n" +
+		"\r\n" +
+		`HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\App Paths\empty.exe` + "\r\n" +
+		`    Path    REG_SZ    C:\Empty` + "\r\n"
+
+	want := []ScannedApp{
+		{Name: "chrome", Path: `C:\Program Files\Google\Chrome\Application\chrome.exe`, Source: SourceAppPaths},
+		{Name: "notepad++", Path: `C:\Program Files\Notepad++\notepad++.exe`, Source: SourceAppPaths},
+	}
+	if got := parseAppPaths(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAppPaths() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeDiscovered(t *testing.T) {
+	folders := []ScannedApp{
+		{Name: "Slack", Path: "/Applications/Slack.app", Source: SourceFolder},
+		{Name: "brave", Path: "/Applications/brave.app", Source: SourceFolder},
+	}
+	spotlight := []ScannedApp{
+		{Name: "slack", Path: "/Users/dev/Slack.app", Source: SourceSpotlight},
+		{Name: "Arc", Path: "/Users/dev/Arc.app", Source: SourceSpotlight},
+		{Name: "---", Path: "/Users/dev/---.app", Source: SourceSpotlight},
+	}
+
+	want := []ScannedApp{
+		{Name: "Arc", Path: "/Users/dev/Arc.app", Source: SourceSpotlight},
+		{Name: "brave", Path: "/Applications/brave.app", Source: SourceFolder},
+		{Name: "Slack", Path: "/Applications/Slack.app", Source: SourceFolder},
+	}
+	if got := mergeDiscovered(folders, spotlight); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeDiscovered() = %+v, want %+v", got, want)
+	}
+}
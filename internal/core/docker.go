@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// isDockerApp reports whether the app runs as a Docker container
+func isDockerApp(app *App) bool {
+	return app.Type == appTypeDocker
+}
+
+// dockerContainerName returns the container an app manages
+func dockerContainerName(name string, app *App) string {
+	if app.Container != "" {
+		return app.Container
+	}
+	return name
+}
+
+// dockerContainerState returns the container's state (running, exited,
+// created, ...) or "" when it doesn't exist
+func dockerContainerState(container string) string {
+	output, err := exec.Command("docker", "inspect", "--format", "{{.State.Status}}", container).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// dockerRunArgs builds the docker run command line for a new container
+func dockerRunArgs(container string, app *App, args []string) []string {
+	runArgs := []string{"run", "--detach", "--name", container}
+	runArgs = append(runArgs, app.DockerArgs...)
+	runArgs = append(runArgs, app.Image)
+	return append(runArgs, args...)
+}
+
+// dockerUnsupportedOptions names the launch options a container can't
+// honour; openx only starts and stops it through docker
+func dockerUnsupportedOptions(opts LaunchOptions) []string {
+	var names []string
+	add := func(set bool, name string) {
+		if set {
+			names = append(names, name)
+		}
+	}
+	add(opts.Log, "--log")
+	add(opts.Follow, "--follow")
+	add(opts.StartupCheck > 0, "--check")
+	add(opts.NewInstance, "--new-instance")
+	add(opts.Elevated, "--admin")
+	add(opts.Terminal, "--terminal")
+	add(opts.Mode == LaunchModeAttached, "--attach")
+	add(opts.Profile != "", "a profile")
+	add(opts.User != "", "another user")
+	add(opts.Priority != "", "a priority")
+	add(opts.Limits != nil, "limits")
+	return names
+}
+
+// launchDockerApp starts the app's container, creating it from its image
+// the first time. With opts.Wait it blocks until the container exits.
+func launchDockerApp(alias, name string, app *App, args []string, opts LaunchOptions) error {
+	if unsupported := dockerUnsupportedOptions(opts); len(unsupported) > 0 {
+		return fmt.Errorf("%s runs in a Docker container and can't be launched with %s", alias, strings.Join(unsupported, ", "))
+	}
+	container := dockerContainerName(name, app)
+
+	var dockerArgs []string
+	switch dockerContainerState(container) {
+	case "running":
+		info("Already running: %s", container)
+		if opts.Wait {
+			return waitForContainer(alias, container)
+		}
+		return nil
+	case "":
+		if app.Image == "" {
+			return fmt.Errorf("container %s does not exist and %s has no image to run", container, alias)
+		}
+		dockerArgs = dockerRunArgs(container, app, args)
+	default:
+		dockerArgs = []string{"start", container}
+	}
+
+	if output, err := exec.Command("docker", dockerArgs...).CombinedOutput(); err != nil {
+		return fmt.Errorf("docker %s failed: %w: %s", dockerArgs[0], err, strings.TrimSpace(string(output)))
+	}
+
+	info("Launched: %s (container %s)", alias, container)
+	recordLaunch(alias, args, nil)
+	if opts.Wait {
+		return waitForContainer(alias, container)
+	}
+	return nil
+}
+
+// waitForContainer blocks until the container stops and returns an
+// AppExitError when it exited with an error
+func waitForContainer(alias, container string) error {
+	output, err := exec.Command("docker", "wait", container).Output()
+	if err != nil {
+		return fmt.Errorf("failed waiting for %s: %w", alias, err)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return fmt.Errorf("failed waiting for %s: unexpected docker wait output %q", alias, strings.TrimSpace(string(output)))
+	}
+	if code != 0 {
+		return &AppExitError{Alias: alias, Code: code}
+	}
+	return nil
+}
+
+// stopDockerApp stops the app's container and reports whether it was running
+func stopDockerApp(alias, name string, app *App, opts KillOptions) (bool, error) {
+	container := dockerContainerName(name, app)
+	if dockerContainerState(container) != "running" {
+		opts.progress("No running container found for: %s", alias)
+		return false, nil
+	}
+
+	if output, err := exec.Command("docker", "stop", container).CombinedOutput(); err != nil {
+		return true, fmt.Errorf("docker stop failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	opts.progress("Stopped container: %s", container)
+	return true, nil
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDockerRunArgs(t *testing.T) {
+	app := &App{
+		Type:       "docker",
+		Image:      "postgres:16",
+		DockerArgs: []string{"-p", "5432:5432", "-e", "POSTGRES_PASSWORD=dev"},
+	}
+
+	if got := dockerContainerName("postgres", app); got != "postgres" {
+		t.Errorf("dockerContainerName() = %q, want app name", got)
+	}
+	app.Container = "dev-db"
+	if got := dockerContainerName("postgres", app); got != "dev-db" {
+		t.Errorf("dockerContainerName() = %q, want configured container", got)
+	}
+
+	got := strings.Join(dockerRunArgs("dev-db", app, []string{"-c", "fsync=off"}), " ")
+	want := "run --detach --name dev-db -p 5432:5432 -e POSTGRES_PASSWORD=dev postgres:16 -c fsync=off"
+	if got != want {
+		t.Errorf("dockerRunArgs() = %q, want %q", got, want)
+	}
+}
+
+// fakeDocker puts a docker script on PATH that reports the container state
+// stored in a file and records every other command; docker wait prints
+// $FAKE_DOCKER_EXIT, 0 by default
+func fakeDocker(t *testing.T, state string) (calls func() []string) {
+	t.Helper()
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state")
+	callsFile := filepath.Join(dir, "calls")
+	if err := os.WriteFile(stateFile, []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	script := `#!/bin/sh
+if [ "$1" = inspect ]; then
+  state=$(cat "` + stateFile + `")
+  [ -n "$state" ] || exit 1
+  echo "$state"
+  exit 0
+fi
+echo "$*" >> "` + callsFile + `"
+[ "$1" = wait ] && echo "${FAKE_DOCKER_EXIT:-0}"
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return func() []string {
+		data, _ := os.ReadFile(callsFile)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+func TestDockerApp_LaunchAndKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script tests on Windows")
+	}
+
+	testContent := `
+apps:
+  postgres:
+    type: docker
+    image: postgres:16
+    docker_args: ["-p", "5432:5432"]
+aliases:
+  pg: postgres`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		state  string
+		action func() error
+		want   string
+	}{
+		{"run missing container", "", func() error { return LaunchApp("pg", nil) }, "run --detach --name postgres -p 5432:5432 postgres:16"},
+		{"start stopped container", "exited", func() error { return LaunchApp("pg", nil) }, "start postgres"},
+		{"stop running container", "running", func() error { _, err := CloseApp("pg"); return err }, "stop postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := fakeDocker(t, tt.state)
+			if err := tt.action(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := calls(); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("docker calls = %q, want [%q]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockerApp_LaunchOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script tests on Windows")
+	}
+
+	testContent := `
+apps:
+  postgres:
+    type: docker
+    image: postgres:16
+aliases:
+  pg: postgres`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	t.Run("wait reports the container's exit code", func(t *testing.T) {
+		calls := fakeDocker(t, "exited")
+		t.Setenv("FAKE_DOCKER_EXIT", "3")
+
+		report, err := LaunchAppReport("pg", nil, LaunchOptions{Wait: true})
+		var exitErr *AppExitError
+		if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+			t.Fatalf("LaunchAppReport() error = %v, want exit code 3", err)
+		}
+		if report.ExitCode == nil || *report.ExitCode != 3 {
+			t.Errorf("report.ExitCode = %v, want 3", report.ExitCode)
+		}
+		if got := strings.Join(calls(), "|"); got != "start postgres|wait postgres" {
+			t.Errorf("docker calls = %q, want start then wait", got)
+		}
+	})
+
+	t.Run("wait on a running container", func(t *testing.T) {
+		calls := fakeDocker(t, "running")
+		if err := LaunchAppWithOptions("pg", nil, LaunchOptions{Wait: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Join(calls(), "|"); got != "wait postgres" {
+			t.Errorf("docker calls = %q, want wait only", got)
+		}
+	})
+
+	t.Run("unsupported options", func(t *testing.T) {
+		calls := fakeDocker(t, "exited")
+		err := LaunchAppWithOptions("pg", nil, LaunchOptions{Log: true, Terminal: true})
+		if err == nil || !strings.Contains(err.Error(), "--log, --terminal") {
+			t.Fatalf("LaunchAppWithOptions() error = %v, want the unsupported options", err)
+		}
+		if got := calls(); len(got) != 1 || got[0] != "" {
+			t.Errorf("docker calls = %q, want none", got)
+		}
+	})
+}
@@ -1,59 +1,193 @@
 package core
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openx/shared/config"
 )
 
-// ANSI color codes
+// Doctor report formats accepted by RunDoctorFormat
 const (
-	ColorReset  = "\033[0m"
-	ColorRed    = "\033[31m"
-	ColorGreen  = "\033[32m"
-	ColorYellow = "\033[33m"
-	ColorGray   = "\033[90m"
+	DoctorFormatHuman    = "human" // colored terminal view
+	DoctorFormatJSON     = "json"
+	DoctorFormatYAML     = "yaml"
+	DoctorFormatCSV      = "csv"      // one row per app, for spreadsheets
+	DoctorFormatMarkdown = "markdown" // tables to paste into a wiki
+
+	DoctorFormatPrometheus = "prometheus" // OpenMetrics text for scrapers
 )
 
 // DoctorReport represents the status of all configured applications
 type DoctorReport struct {
-	Platform   string            `json:"platform"`
-	ConfigPath string            `json:"configPath"`
-	Apps       []AppStatus       `json:"apps"`
-	Aliases    map[string]string `json:"aliases"`
-	Summary    Summary           `json:"summary"`
+	Platform   string            `json:"platform" yaml:"platform"`
+	ConfigPath string            `json:"configPath" yaml:"configPath"`
+	Apps       []AppStatus       `json:"apps" yaml:"apps"`
+	Aliases    map[string]string `json:"aliases" yaml:"aliases"`
+	Summary    Summary           `json:"summary" yaml:"summary"`
+
+	// DanglingAliases are the aliases and synonyms that lead nowhere here
+	DanglingAliases []AliasIssue `json:"danglingAliases,omitempty" yaml:"danglingAliases,omitempty"`
+
+	// Conflicts are duplicate apps and aliases hiding other names
+	Conflicts []Conflict `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
 }
 
 // AppStatus represents the status of a single application
 type AppStatus struct {
-	Name        string `json:"name"`
-	LaunchPath  string `json:"launchPath"`
-	Status      string `json:"status"` // "available", "missing", "no-path"
-	KillPattern string `json:"killPattern"`
-	Running     bool   `json:"running"`
+	Name        string `json:"name" yaml:"name"`
+	LaunchPath  string `json:"launchPath" yaml:"launchPath"`
+	Status      string `json:"status" yaml:"status"` // "available", "missing", "no-path"
+	KillPattern string `json:"killPattern" yaml:"killPattern"`
+	Running     bool   `json:"running" yaml:"running"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"` // "" when it can't be detected
+
+	// OtherUsers own some of a running app's processes, which --kill
+	// can't stop
+	OtherUsers []string `json:"otherUsers,omitempty" yaml:"otherUsers,omitempty"`
+
+	// InstallHint is the command that installs a missing app
+	InstallHint string `json:"installHint,omitempty" yaml:"installHint,omitempty"`
+
+	// LaunchIssue is what keeps an available app from launching, such as
+	// a missing execute bit or Gatekeeper's quarantine
+	LaunchIssue string `json:"launchIssue,omitempty" yaml:"launchIssue,omitempty"`
+
+	// ArchMismatch says why the app's binary won't run natively here
+	ArchMismatch string `json:"archMismatch,omitempty" yaml:"archMismatch,omitempty"`
+
+	// Usage is what a running app's processes use, in the JSON and YAML
+	// reports
+	Usage *ResourceUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
 }
 
 // Summary provides aggregate statistics
 type Summary struct {
-	Total     int `json:"total"`
-	Available int `json:"available"`
-	Missing   int `json:"missing"`
-	Running   int `json:"running"`
+	Total     int `json:"total" yaml:"total"`
+	Available int `json:"available" yaml:"available"`
+	Missing   int `json:"missing" yaml:"missing"`
+	Running   int `json:"running" yaml:"running"`
+	Dangling  int `json:"dangling" yaml:"dangling"` // config aliases only, see DanglingAliases
+	Conflicts int `json:"conflicts" yaml:"conflicts"`
+	Blocked   int `json:"blocked" yaml:"blocked"` // available apps with a LaunchIssue
 }
 
 // RunDoctor performs a health check of all configured applications
 func RunDoctor(jsonOutput bool) error {
+	if jsonOutput {
+		return RunDoctorFormat(DoctorFormatJSON)
+	}
+	return RunDoctorFormat(DoctorFormatHuman)
+}
+
+// RunDoctorFormat performs a health check and prints the report in one of
+// the DoctorFormat formats; an empty format is the human view
+func RunDoctorFormat(format string) error {
+	return WriteDoctor(os.Stdout, format)
+}
+
+// RunDoctorProblems performs a health check and prints only what needs
+// attention, see WriteDoctorProblems
+func RunDoctorProblems(format string) error {
+	return WriteDoctorProblems(os.Stdout, format)
+}
+
+// WriteDoctor performs a health check and writes the report to w in one
+// of the DoctorFormat formats, colored only when w is a terminal
+func WriteDoctor(w io.Writer, format string) error {
+	return writeDoctor(w, format, false)
+}
+
+// WriteDoctorProblems performs a health check and writes only the apps
+// and aliases that need attention to w, keeping the full summary
+func WriteDoctorProblems(w io.Writer, format string) error {
+	return writeDoctor(w, format, true)
+}
+
+func writeDoctor(w io.Writer, format string, problemsOnly bool) error {
+	w = plainWriter(w)
+	output, err := doctorOutput(format)
+	if err != nil {
+		return err
+	}
+
+	// Only the structured reports carry resource usage
+	usage := false
+	switch strings.ToLower(format) {
+	case DoctorFormatJSON, DoctorFormatYAML, DoctorFormatPrometheus:
+		usage = true
+	}
+
+	report, err := BuildDoctorReport(usage)
+	if err != nil {
+		return err
+	}
+	if _, err := recordDoctorRun(newDoctorRun(report, time.Now())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: doctor run not recorded: %v\n", err)
+	}
+	if !problemsOnly {
+		return output(w, *report)
+	}
+
+	problems := doctorProblems(*report)
+	human := format == "" || strings.EqualFold(format, DoctorFormatHuman)
+	if human && len(problems.Apps) == 0 && len(problems.DanglingAliases) == 0 && len(problems.Conflicts) == 0 {
+		fmt.Fprintf(w, "%s✓ No problems in %d apps%s\n", ColorGreen, report.Summary.Total, ColorReset)
+		return nil
+	}
+	return output(w, problems)
+}
+
+// doctorProblems trims a report to what needs attention: apps that are
+// missing, have no path here, can't launch or run emulated, the dangling
+// config aliases and the conflicts. Built-in synonyms with no app aren't
+// problems of the config, and the summary still counts everything.
+func doctorProblems(report DoctorReport) DoctorReport {
+	apps := []AppStatus{}
+	for _, app := range report.Apps {
+		if app.Status != "available" || app.LaunchIssue != "" || app.ArchMismatch != "" {
+			apps = append(apps, app)
+		}
+	}
+	report.Apps = apps
+
+	aliases := map[string]string{}
+	var dangling []AliasIssue
+	for _, issue := range report.DanglingAliases {
+		if !issue.Synonym {
+			aliases[issue.Alias] = report.Aliases[issue.Alias]
+			dangling = append(dangling, issue)
+		}
+	}
+	report.Aliases = aliases
+	report.DanglingAliases = dangling
+	return report
+}
+
+// BuildDoctorReport performs a health check of all configured
+// applications and returns the report, with the resource usage of running
+// apps when usage is set
+func BuildDoctorReport(usage bool) (*DoctorReport, error) {
 	config, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	configPath := getConfigPath()
-	report := DoctorReport{
+	report := &DoctorReport{
 		Platform:   runtime.GOOS,
 		ConfigPath: configPath,
 		Apps:       []AppStatus{},
@@ -84,20 +218,129 @@ func RunDoctor(jsonOutput bool) error {
 		if status.Running {
 			report.Summary.Running++
 		}
+		if status.LaunchIssue != "" {
+			report.Summary.Blocked++
+		}
 	}
 
-	if jsonOutput {
-		return outputJSON(report)
+	report.DanglingAliases = checkAliases(config)
+	for _, issue := range report.DanglingAliases {
+		if !issue.Synonym {
+			report.Summary.Dangling++
+		}
+	}
+
+	report.Conflicts = checkConflicts(config)
+	report.Summary.Conflicts = len(report.Conflicts)
+
+	addDoctorVersions(config, report.Apps)
+	if report.Summary.Running > 0 {
+		addDoctorProcesses(report.Apps, usage)
+	}
+	return report, nil
+}
+
+// doctorOutput returns the function writing a report in format
+func doctorOutput(format string) (func(io.Writer, DoctorReport) error, error) {
+	output, ok := map[string]func(io.Writer, DoctorReport) error{
+		"":                   outputHuman,
+		DoctorFormatHuman:    outputHuman,
+		DoctorFormatJSON:     outputJSON,
+		DoctorFormatYAML:     outputYAML,
+		DoctorFormatCSV:      outputCSV,
+		DoctorFormatMarkdown: outputMarkdown,
+
+		DoctorFormatPrometheus: outputPrometheus,
+	}[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (use human, json, yaml, csv, markdown or prometheus)", format)
+	}
+	return output, nil
+}
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// WatchDoctor re-runs the health check every interval and whenever the
+// config file changes, redrawing the report in place until interrupted
+func WatchDoctor(format string, interval time.Duration) error {
+	return watchDoctor(format, interval, false)
+}
+
+// WatchDoctorProblems is WatchDoctor showing only what needs attention
+func WatchDoctorProblems(format string, interval time.Duration) error {
+	return watchDoctor(format, interval, true)
+}
+
+func watchDoctor(format string, interval time.Duration, problemsOnly bool) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+	if _, err := doctorOutput(format); err != nil {
+		return err
 	}
 
-	return outputHuman(report)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	changes, err := config.Watch(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, checking every %s only\n", err, interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Print(clearScreen)
+		if err := writeDoctor(os.Stdout, format, problemsOnly); err != nil {
+			// A half-edited config fails to load; the next save retries
+			fmt.Fprintf(os.Stderr, "Doctor check failed: %v\n", err)
+		}
+		fmt.Printf("\n%sChecked at %s, again every %s and on config changes (Ctrl-C to stop)%s\n",
+			ColorGray, time.Now().Format("15:04:05"), interval, ColorReset)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+			}
+		}
+	}
+}
+
+// addDoctorProcesses adds who owns the running apps' processes, and with
+// usage what they use
+func addDoctorProcesses(apps []AppStatus, usage bool) {
+	statuses, err := ProcessStatuses(usage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: process details unavailable: %v\n", err)
+		return
+	}
+
+	byName := map[string]ProcessStatus{}
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	for i := range apps {
+		if apps[i].Running {
+			apps[i].Usage = byName[apps[i].Name].Usage
+			apps[i].OtherUsers = byName[apps[i].Name].OtherUsers
+		}
+	}
 }
 
 // checkAppStatus checks the status of a single application
 func checkAppStatus(name string, app *App) AppStatus {
 	status := AppStatus{
 		Name:        name,
-		KillPattern: strings.Join(app.GetKillPatterns(), ", "),
+		KillPattern: strings.Join(appKillPatterns(app), ", "),
+	}
+
+	if isDockerApp(app) {
+		return checkDockerAppStatus(name, app, status)
 	}
 
 	// Check if we have a launch path for this platform
@@ -113,24 +356,69 @@ func checkAppStatus(name string, app *App) AppStatus {
 	// Check if the application exists
 	if appExists(launchPath) {
 		status.Status = "available"
+		status.LaunchIssue = appLaunchIssue(launchPath)
+		status.ArchMismatch = appArchMismatch(launchPath)
 	} else {
 		status.Status = "missing"
+		status.InstallHint = installHint(name)
 	}
 
 	// Check if the application is running
-	killPatterns := app.GetKillPatterns()
-	for _, pattern := range killPatterns {
-		if isProcessRunning(pattern) {
-			status.Running = true
-			break
-		}
+	status.Running = isAppRunning(name, app)
+
+	return status
+}
+
+// checkDockerAppStatus checks a Docker app: docker must be installed and
+// the container must exist or be creatable from an image
+func checkDockerAppStatus(name string, app *App, status AppStatus) AppStatus {
+	container := dockerContainerName(name, app)
+	status.LaunchPath = fmt.Sprintf("docker: %s", container)
+	if app.Image != "" {
+		status.LaunchPath += fmt.Sprintf(" (%s)", app.Image)
 	}
 
+	_, err := exec.LookPath("docker")
+	dockerInstalled := err == nil
+
+	state := ""
+	if dockerInstalled {
+		state = dockerContainerState(container)
+	}
+	if dockerInstalled && (state != "" || app.Image != "") {
+		status.Status = "available"
+	} else {
+		status.Status = "missing"
+	}
+	if !dockerInstalled {
+		status.InstallHint = installHint("docker")
+	}
+	status.Running = state == "running"
 	return status
 }
 
 // appExists checks if an application exists at the given path
 func appExists(path string) bool {
+	if aumid, ok := uwpAppID(path); ok {
+		return uwpAppInstalled(aumid)
+	}
+	if isURL(path) {
+		// Scheme handlers can't be checked portably, trust the config
+		return true
+	}
+	if strings.HasPrefix(path, snapPrefix) {
+		// Every installed snap app gets a command in /snap/bin
+		return exists("/snap/bin/" + strings.TrimPrefix(path, snapPrefix))
+	}
+	if strings.HasPrefix(path, appImagePrefix) {
+		_, found := findAppImage(strings.TrimPrefix(path, appImagePrefix), appImageDirs())
+		return found
+	}
+	if id, ok := desktopEntryID(path); ok {
+		_, found := findDesktopEntry(id, desktopEntryDirs())
+		return found
+	}
+
 	if strings.ContainsAny(path, `/\`) {
 		// Absolute or relative path
 		return exists(path)
@@ -142,19 +430,164 @@ func appExists(path string) bool {
 }
 
 // outputJSON outputs the doctor report in JSON format
-func outputJSON(report DoctorReport) error {
-	encoder := json.NewEncoder(os.Stdout)
+func outputJSON(w io.Writer, report DoctorReport) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(report)
 }
 
+// outputYAML outputs the doctor report in YAML format
+func outputYAML(w io.Writer, report DoctorReport) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+	return encoder.Encode(report)
+}
+
+// outputCSV outputs one row per application with a header row
+func outputCSV(w io.Writer, report DoctorReport) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"name", "status", "version", "launch_path", "kill_pattern", "running"})
+	for _, app := range report.Apps {
+		writer.Write([]string{app.Name, app.Status, app.Version, app.LaunchPath, app.KillPattern, strconv.FormatBool(app.Running)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// outputMarkdown outputs the doctor report as Markdown tables
+func outputMarkdown(w io.Writer, report DoctorReport) error {
+	cell := func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	}
+
+	fmt.Fprintf(w, "# openx doctor (%s)\n\n", report.Platform)
+	fmt.Fprintf(w, "Config: `%s`\n\n", report.ConfigPath)
+
+	fmt.Fprintln(w, "| App | Status | Version | Launch path | Kill pattern | Running |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- |")
+	for _, app := range report.Apps {
+		running := ""
+		if app.Running {
+			running = "yes"
+		}
+		fmt.Fprintf(w, "| %s | %s %s | %s | %s | %s | %s |\n", cell(app.Name), getStatusIcon(app.Status), app.Status, cell(app.Version), cell(app.LaunchPath), cell(app.KillPattern), running)
+	}
+
+	if len(report.Aliases) > 0 {
+		fmt.Fprintln(w, "\n| Alias | App |")
+		fmt.Fprintln(w, "| --- | --- |")
+		aliasNames := make([]string, 0, len(report.Aliases))
+		for alias := range report.Aliases {
+			aliasNames = append(aliasNames, alias)
+		}
+		sort.Strings(aliasNames)
+		for _, alias := range aliasNames {
+			fmt.Fprintf(w, "| %s | %s |\n", cell(alias), cell(report.Aliases[alias]))
+		}
+	}
+
+	if len(report.DanglingAliases) > 0 {
+		fmt.Fprintln(w, "\n| Dangling alias | Kind | Problem |")
+		fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, issue := range report.DanglingAliases {
+			kind := "alias"
+			if issue.Synonym {
+				kind = "built-in synonym"
+			}
+			fmt.Fprintf(w, "| %s | %s | %s |\n", cell(issue.Alias), kind, cell(issue.Problem))
+		}
+	}
+
+	if len(report.Conflicts) > 0 {
+		fmt.Fprintln(w, "\n| Conflict | Detail | Suggested fix |")
+		fmt.Fprintln(w, "| --- | --- | --- |")
+		for _, conflict := range report.Conflicts {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", conflict.Kind, cell(conflict.Detail), cell(conflict.Fix))
+		}
+	}
+
+	fmt.Fprintf(w, "\n**Total:** %d apps, **available:** %d, **missing:** %d, **running:** %d, **dangling aliases:** %d, **conflicts:** %d\n",
+		report.Summary.Total, report.Summary.Available, report.Summary.Missing, report.Summary.Running, report.Summary.Dangling, report.Summary.Conflicts)
+	return nil
+}
+
+// outputPrometheus outputs the doctor report as OpenMetrics gauges, one
+// series per app, for node exporter's textfile collector or a scraper
+func outputPrometheus(w io.Writer, report DoctorReport) error {
+	label := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	bit := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	gauge := func(name, help string, value func(AppStatus) (float64, bool)) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, app := range report.Apps {
+			if v, ok := value(app); ok {
+				fmt.Fprintf(w, "%s{app=\"%s\"} %s\n", name, label.Replace(app.Name), strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+	}
+
+	gauge("openx_app_available", "Whether the app is installed on this machine.", func(app AppStatus) (float64, bool) {
+		return float64(bit(app.Status == "available")), true
+	})
+	gauge("openx_app_running", "Whether the app has running processes.", func(app AppStatus) (float64, bool) {
+		return float64(bit(app.Running)), true
+	})
+	gauge("openx_app_blocked", "Whether an installed app is kept from launching.", func(app AppStatus) (float64, bool) {
+		return float64(bit(app.LaunchIssue != "")), app.Status == "available"
+	})
+	gauge("openx_app_cpu_percent", "CPU used by the app's processes, in percent of one core.", func(app AppStatus) (float64, bool) {
+		if app.Usage == nil {
+			return 0, false
+		}
+		return app.Usage.CPU, true
+	})
+	gauge("openx_app_memory_bytes", "Resident memory of the app's processes.", func(app AppStatus) (float64, bool) {
+		if app.Usage == nil {
+			return 0, false
+		}
+		return float64(app.Usage.RSS), true
+	})
+
+	fmt.Fprintf(w, "# HELP openx_app_info Installed version of the app.\n# TYPE openx_app_info gauge\n")
+	for _, app := range report.Apps {
+		if app.Version != "" {
+			fmt.Fprintf(w, "openx_app_info{app=\"%s\",version=\"%s\"} 1\n", label.Replace(app.Name), label.Replace(app.Version))
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP openx_apps Configured apps by state.\n# TYPE openx_apps gauge\n")
+	for _, state := range []struct {
+		name  string
+		count int
+	}{
+		{"total", report.Summary.Total},
+		{"available", report.Summary.Available},
+		{"missing", report.Summary.Missing},
+		{"running", report.Summary.Running},
+		{"blocked", report.Summary.Blocked},
+	} {
+		fmt.Fprintf(w, "openx_apps{state=\"%s\"} %d\n", state.name, state.count)
+	}
+	fmt.Fprintf(w, "# HELP openx_dangling_aliases Config aliases that lead to no app.\n# TYPE openx_dangling_aliases gauge\nopenx_dangling_aliases %d\n", report.Summary.Dangling)
+	fmt.Fprintf(w, "# HELP openx_conflicts Duplicate apps and conflicting aliases.\n# TYPE openx_conflicts gauge\nopenx_conflicts %d\n", report.Summary.Conflicts)
+	fmt.Fprintln(w, "# EOF")
+	return nil
+}
+
 // outputHuman outputs the doctor report in human-readable format
-func outputHuman(report DoctorReport) error {
-	fmt.Printf("openx doctor (%s)\n", report.Platform)
-	fmt.Printf("Config: %s\n\n", report.ConfigPath)
+func outputHuman(w io.Writer, report DoctorReport) error {
+	fmt.Fprintf(w, "openx doctor (%s)\n", report.Platform)
+	fmt.Fprintf(w, "Config: %s\n\n", report.ConfigPath)
 
-	// Applications status
-	fmt.Println("Applications:")
+	// Applications status; a problems-only report may have none
+	if len(report.Apps) > 0 {
+		fmt.Fprintln(w, "Applications:")
+	}
 	for _, app := range report.Apps {
 		status := getStatusIcon(app.Status)
 		statusColor := getStatusColor(app.Status)
@@ -163,15 +596,41 @@ func outputHuman(report DoctorReport) error {
 			running = ColorGreen + " (running)" + ColorReset
 		}
 
-		fmt.Printf("  %s%s%s %-15s %s%s\n", statusColor, status, ColorReset, app.Name, app.LaunchPath, running)
+		version := ""
+		if app.Version != "" {
+			version = " " + app.Version
+		}
+
+		fmt.Fprintf(w, "  %s%s%s %-15s %s%s%s\n", statusColor, status, ColorReset, app.Name, app.LaunchPath, version, running)
 		if app.KillPattern != "" {
-			fmt.Printf("    %s└─ kill: %s%s\n", ColorGray, app.KillPattern, ColorReset)
+			fmt.Fprintf(w, "    %s└─ kill: %s%s\n", ColorGray, app.KillPattern, ColorReset)
+		}
+		if len(app.OtherUsers) > 0 {
+			fmt.Fprintf(w, "    %s└─ owned by %s, --kill can't stop it%s\n", ColorYellow, strings.Join(app.OtherUsers, ", "), ColorReset)
+		}
+		if app.LaunchIssue != "" {
+			fmt.Fprintf(w, "    %s└─ launch: %s%s\n", ColorRed, app.LaunchIssue, ColorReset)
+		}
+		if app.ArchMismatch != "" {
+			fmt.Fprintf(w, "    %s└─ arch: %s%s\n", ColorYellow, app.ArchMismatch, ColorReset)
+		}
+		if app.InstallHint != "" {
+			fmt.Fprintf(w, "    %s└─ install: %s%s\n", ColorYellow, app.InstallHint, ColorReset)
 		}
 	}
 
-	// Aliases
-	if len(report.Aliases) > 0 {
-		fmt.Println("\nAliases:")
+	// Aliases, with the problem of each dangling one
+	problems := map[string]string{}
+	var synonyms []string
+	for _, issue := range report.DanglingAliases {
+		if issue.Synonym {
+			synonyms = append(synonyms, issue.Alias)
+		} else {
+			problems[issue.Alias] = issue.Problem
+		}
+	}
+	if len(report.Aliases) > 0 || len(synonyms) > 0 {
+		fmt.Fprintln(w, "\nAliases:")
 		aliasNames := make([]string, 0, len(report.Aliases))
 		for alias := range report.Aliases {
 			aliasNames = append(aliasNames, alias)
@@ -180,27 +639,53 @@ func outputHuman(report DoctorReport) error {
 
 		for _, alias := range aliasNames {
 			target := report.Aliases[alias]
-			fmt.Printf("  %-10s → %s\n", alias, target)
+			if problem, dangling := problems[alias]; dangling {
+				fmt.Fprintf(w, "  %s✗ %-8s → %s (%s)%s\n", ColorRed, alias, target, problem, ColorReset)
+				continue
+			}
+			fmt.Fprintf(w, "  %-10s → %s\n", alias, target)
+		}
+		if len(synonyms) > 0 {
+			fmt.Fprintf(w, "  %sBuilt-in synonyms with no app here: %s%s\n", ColorGray, strings.Join(synonyms, ", "), ColorReset)
+		}
+	}
+
+	// Conflicts
+	if len(report.Conflicts) > 0 {
+		fmt.Fprintln(w, "\nConflicts:")
+		for _, conflict := range report.Conflicts {
+			fmt.Fprintf(w, "  %s⚠ %s%s\n", ColorYellow, conflict.Detail, ColorReset)
+			fmt.Fprintf(w, "    %s└─ fix: %s%s\n", ColorGray, conflict.Fix, ColorReset)
 		}
 	}
 
 	// Summary
-	fmt.Printf("\nSummary:\n")
-	fmt.Printf("  Total: %d apps\n", report.Summary.Total)
-	fmt.Printf("  %sAvailable: %d%s\n", ColorGreen, report.Summary.Available, ColorReset)
+	fmt.Fprintf(w, "\nSummary:\n")
+	fmt.Fprintf(w, "  Total: %d apps\n", report.Summary.Total)
+	fmt.Fprintf(w, "  %sAvailable: %d%s\n", ColorGreen, report.Summary.Available, ColorReset)
 	if report.Summary.Missing > 0 {
-		fmt.Printf("  %sMissing: %d%s\n", ColorRed, report.Summary.Missing, ColorReset)
+		fmt.Fprintf(w, "  %sMissing: %d%s\n", ColorRed, report.Summary.Missing, ColorReset)
 	} else {
-		fmt.Printf("  Missing: %d\n", report.Summary.Missing)
+		fmt.Fprintf(w, "  Missing: %d\n", report.Summary.Missing)
 	}
 	if report.Summary.Running > 0 {
-		fmt.Printf("  %sRunning: %d%s\n", ColorGreen, report.Summary.Running, ColorReset)
+		fmt.Fprintf(w, "  %sRunning: %d%s\n", ColorGreen, report.Summary.Running, ColorReset)
 	} else {
-		fmt.Printf("  Running: %d\n", report.Summary.Running)
+		fmt.Fprintf(w, "  Running: %d\n", report.Summary.Running)
+	}
+
+	if report.Summary.Dangling > 0 {
+		fmt.Fprintf(w, "  %sDangling aliases: %d%s\n", ColorRed, report.Summary.Dangling, ColorReset)
+	}
+	if report.Summary.Conflicts > 0 {
+		fmt.Fprintf(w, "  %sConflicts: %d%s\n", ColorYellow, report.Summary.Conflicts, ColorReset)
+	}
+	if report.Summary.Blocked > 0 {
+		fmt.Fprintf(w, "  %sBlocked from launching: %d%s\n", ColorRed, report.Summary.Blocked, ColorReset)
 	}
 
 	if report.Summary.Missing > 0 {
-		fmt.Printf("\n%sNote: Missing apps may need to be installed or paths updated in config.%s\n", ColorYellow, ColorReset)
+		fmt.Fprintf(w, "\n%sNote: Missing apps may need to be installed or paths updated in config.%s\n", ColorYellow, ColorReset)
 	}
 
 	return nil
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRunDoctor(t *testing.T) {
@@ -50,31 +52,24 @@ aliases:
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Capture stdout
-			oldStdout := os.Stdout
-			r, w, _ := os.Pipe()
-			os.Stdout = w
-
-			err := RunDoctor(tt.jsonOutput)
-
-			// Restore stdout
-			w.Close()
-			os.Stdout = oldStdout
+			format := DoctorFormatHuman
+			if tt.jsonOutput {
+				format = DoctorFormatJSON
+			}
 
-			// Read the output
 			var buf bytes.Buffer
-			io.Copy(&buf, r)
+			err := WriteDoctor(&buf, format)
 			output := buf.String()
 
 			if tt.wantErr {
 				if err == nil {
-					t.Errorf("RunDoctor() expected error but got none")
+					t.Errorf("WriteDoctor() expected error but got none")
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("RunDoctor() unexpected error: %v", err)
+				t.Errorf("WriteDoctor() unexpected error: %v", err)
 				return
 			}
 
@@ -83,12 +78,12 @@ aliases:
 				// Should be valid JSON
 				var report DoctorReport
 				if err := json.Unmarshal([]byte(output), &report); err != nil {
-					t.Errorf("RunDoctor() JSON output is invalid: %v\nOutput: %s", err, output)
+					t.Errorf("WriteDoctor() JSON output is invalid: %v\nOutput: %s", err, output)
 				}
 			} else {
 				// Human readable should contain certain keywords
 				if len(output) == 0 {
-					t.Error("RunDoctor() human output is empty")
+					t.Error("WriteDoctor() human output is empty")
 				}
 			}
 		})
@@ -312,20 +307,8 @@ func TestOutputJSON(t *testing.T) {
 		},
 	}
 
-	// Capture stdout
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	err := outputJSON(report)
-
-	// Restore stdout
-	w.Close()
-	os.Stdout = oldStdout
-
-	// Read the output
 	var buf bytes.Buffer
-	io.Copy(&buf, r)
+	err := outputJSON(&buf, report)
 	output := buf.String()
 
 	if err != nil {
@@ -366,3 +349,201 @@ func TestRunDoctor_ConfigError(t *testing.T) {
 		t.Errorf("RunDoctor() error = %v, want error containing %v", err, expectedSubstring)
 	}
 }
+
+func TestOutputCSVAndMarkdown(t *testing.T) {
+	report := DoctorReport{
+		Platform:   "linux",
+		ConfigPath: "/home/dev/.config/openx/config.yaml",
+		Apps: []AppStatus{
+			{Name: "code", LaunchPath: "/usr/bin/code", Status: "available", KillPattern: "code", Running: true, Version: "1.95.3"},
+			{Name: "pipe", LaunchPath: "/opt/a|b", Status: "missing", KillPattern: "a|b"},
+		},
+		Aliases: map[string]string{"vs": "code"},
+		Summary: Summary{Total: 2, Available: 1, Missing: 1, Running: 1},
+	}
+
+	capture := func(output func(io.Writer, DoctorReport) error) string {
+		var buf bytes.Buffer
+		if err := output(&buf, report); err != nil {
+			t.Fatalf("output unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	wantCSV := "name,status,version,launch_path,kill_pattern,running\n" +
+		"code,available,1.95.3,/usr/bin/code,code,true\n" +
+		"pipe,missing,,/opt/a|b,a|b,false\n"
+	if got := capture(outputCSV); got != wantCSV {
+		t.Errorf("outputCSV() = %q, want %q", got, wantCSV)
+	}
+
+	markdown := capture(outputMarkdown)
+	for _, want := range []string{
+		"| App | Status | Version | Launch path | Kill pattern | Running |",
+		"| code | ✓ available | 1.95.3 | /usr/bin/code | code | yes |",
+		`| pipe | ✗ missing |  | /opt/a\|b | a\|b |  |`,
+		"| vs | code |",
+		"**Total:** 2 apps",
+		"**conflicts:** 0",
+	} {
+		if !contains(markdown, want) {
+			t.Errorf("outputMarkdown() missing %q in:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestRunDoctorFormat_Unknown(t *testing.T) {
+	if err := RunDoctorFormat("xml"); err == nil {
+		t.Error("RunDoctorFormat(xml) expected error")
+	}
+}
+
+func TestWatchDoctor_InvalidOptions(t *testing.T) {
+	if err := WatchDoctor("", 0); err == nil {
+		t.Error("WatchDoctor() expected error for a zero interval")
+	}
+	if err := WatchDoctor("xml", time.Second); err == nil {
+		t.Error("WatchDoctor() expected error for an unknown format")
+	}
+}
+
+func TestBuildDoctorReport(t *testing.T) {
+	testContent := `
+apps:
+  existingcommand:
+    darwin: "/bin/ls"
+    linux: "/bin/ls"
+    windows: "cmd.exe"
+  missingapp:
+    darwin: "/definitely/does/not/exist"
+    linux: "/definitely/does/not/exist"
+    windows: "C:\\definitely\\does\\not\\exist.exe"
+
+aliases:
+  ls: existingcommand
+  gone: atom`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	report, err := BuildDoctorReport(false)
+	if err != nil {
+		t.Fatalf("BuildDoctorReport() unexpected error: %v", err)
+	}
+
+	if report.ConfigPath != configPath || len(report.Apps) != 2 {
+		t.Fatalf("BuildDoctorReport() = %+v, want both apps of %s", report, configPath)
+	}
+	if report.Apps[0].Name != "existingcommand" || report.Apps[1].Status != "missing" {
+		t.Errorf("BuildDoctorReport() apps = %+v, want existingcommand, then missingapp missing", report.Apps)
+	}
+	// Running depends on what else runs on the machine
+	want := Summary{Total: 2, Available: 1, Missing: 1, Running: report.Summary.Running, Dangling: 1}
+	if report.Summary != want {
+		t.Errorf("BuildDoctorReport() summary = %+v, want %+v", report.Summary, want)
+	}
+}
+
+func TestOutputPrometheus(t *testing.T) {
+	report := DoctorReport{
+		Apps: []AppStatus{
+			{Name: "chrome", Status: "available", Running: true, Version: "130.0", Usage: &ResourceUsage{Processes: 3, CPU: 12.5, RSS: 1048576}},
+			{Name: `odd"name`, Status: "missing"},
+		},
+		Summary: Summary{Total: 2, Available: 1, Missing: 1, Running: 1, Conflicts: 2},
+	}
+
+	var buf bytes.Buffer
+	if err := outputPrometheus(&buf, report); err != nil {
+		t.Fatalf("outputPrometheus() unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"# TYPE openx_app_available gauge\n",
+		`openx_app_available{app="chrome"} 1` + "\n",
+		`openx_app_available{app="odd\"name"} 0` + "\n",
+		`openx_app_running{app="chrome"} 1` + "\n",
+		`openx_app_cpu_percent{app="chrome"} 12.5` + "\n",
+		`openx_app_memory_bytes{app="chrome"} 1048576` + "\n",
+		`openx_app_info{app="chrome",version="130.0"} 1` + "\n",
+		`openx_apps{state="missing"} 1` + "\n",
+		"openx_conflicts 2\n",
+		"# EOF\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("outputPrometheus() missing %q in:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, `openx_app_blocked{app="odd`) || strings.Contains(output, `openx_app_cpu_percent{app="odd`) {
+		t.Errorf("outputPrometheus() has series that don't apply to a missing app:\n%s", output)
+	}
+}
+
+func TestWriteDoctorProblems(t *testing.T) {
+	configPath := setupTestConfig(t, `
+apps:
+  testapp:
+    darwin: "/Applications/NoSuchTestApp.app"
+    linux: "no-such-testapp"
+    windows: "no-such-testapp.exe"
+  existingcommand:
+    darwin: "/bin/ls"
+    linux: "/bin/ls"
+    windows: "cmd.exe"
+
+aliases:
+  ls: existingcommand
+  gone: nothere`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	if err := WriteDoctorProblems(&buf, DoctorFormatHuman); err != nil {
+		t.Fatalf("WriteDoctorProblems() error: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{"testapp", "gone", "Total: 2 apps"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("problems output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "existingcommand") {
+		t.Errorf("problems output lists a healthy app:\n%s", output)
+	}
+
+	buf.Reset()
+	if err := WriteDoctorProblems(&buf, DoctorFormatJSON); err != nil {
+		t.Fatalf("WriteDoctorProblems(json) error: %v", err)
+	}
+	var report DoctorReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(report.Apps) != 1 || report.Apps[0].Name != "testapp" {
+		t.Errorf("problems report apps = %+v, want only testapp", report.Apps)
+	}
+	if _, ok := report.Aliases["ls"]; ok {
+		t.Errorf("problems report kept a working alias: %v", report.Aliases)
+	}
+}
+
+func TestWriteDoctorProblems_Healthy(t *testing.T) {
+	configPath := setupTestConfig(t, `
+apps:
+  existingcommand:
+    darwin: "/bin/ls"
+    linux: "/bin/ls"
+    windows: "cmd.exe"`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	if err := WriteDoctorProblems(&buf, ""); err != nil {
+		t.Fatalf("WriteDoctorProblems() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No problems in 1 apps") {
+		t.Errorf("healthy problems output = %q", buf.String())
+	}
+}
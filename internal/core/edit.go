@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigEntryLine returns the line of the config file defining the app,
+// group or project name stands for. Aliases and built-in synonyms lead to
+// their app.
+func ConfigEntryLine(name string) (int, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name = strings.ToLower(name)
+	var section, key string
+	if app, _, err := lookupApp(config, name); err == nil {
+		section, key = "apps", app
+	} else if target, ok := SynonymTarget(name); ok && config.Apps[target] != nil {
+		section, key = "apps", target
+	} else if _, ok := config.Groups[name]; ok {
+		section, key = "groups", name
+	} else if _, ok := config.Projects[name]; ok {
+		section, key = "projects", name
+	} else {
+		return 0, UnknownName(config, ListKindApp, name)
+	}
+
+	line, err := entryLine(section, key)
+	if err != nil {
+		return 0, err
+	}
+	if line == 0 {
+		return 0, fmt.Errorf("%s not found in %s", key, getConfigPath())
+	}
+	debug("config entry", "name", name, "section", section, "key", key, "line", line)
+	return line, nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigEntryLine(t *testing.T) {
+	configPath := setupTestConfig(t, `apps:
+  chrome:
+    linux: /usr/bin/google-chrome
+  vscode:
+    linux: /usr/bin/code
+aliases:
+  gc: chrome
+groups:
+  web:
+    - chrome
+projects:
+  site:
+    editor: vscode
+    path: /work/site
+`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		want    int
+		wantErr string
+	}{
+		{name: "chrome", want: 2},
+		{name: "GC", want: 2},
+		{name: "code", want: 4}, // built-in synonym of vscode
+		{name: "web", want: 9},
+		{name: "site", want: 12},
+		{name: "chrom", wantErr: "did you mean: chrome"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConfigEntryLine(tt.name)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ConfigEntryLine(%q) error = %v, want %q", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ConfigEntryLine(%q) = %d, %v, want %d", tt.name, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// elevateCommand rewraps cmd so it runs with administrator privileges.
+// On Linux sudo credentials are validated up front on the terminal, since
+// the launched app runs detached from it.
+func elevateCommand(cmd *exec.Cmd, wait bool) (*exec.Cmd, error) {
+	if runtime.GOOS == "linux" {
+		if err := authenticateSudo(); err != nil {
+			return nil, err
+		}
+	}
+
+	elevated := elevatedCommand(runtime.GOOS, cmd.Path, cmd.Args[1:], wait)
+	if elevated == nil {
+		return nil, fmt.Errorf("elevated launch is not supported on %s", runtime.GOOS)
+	}
+	return elevated, nil
+}
+
+// elevatedCommand builds the platform command that runs path with
+// administrator privileges
+func elevatedCommand(goos, path string, args []string, wait bool) *exec.Cmd {
+	switch goos {
+	case "linux":
+		// -n: credentials were cached by authenticateSudo, never prompt
+		return exec.Command("sudo", append([]string{"-n", "--", path}, args...)...)
+	case "darwin":
+		shellCmd := shellQuote(append([]string{path}, args...))
+		if !wait {
+			shellCmd += " > /dev/null 2>&1 &"
+		}
+		script := fmt.Sprintf("do shell script %s with administrator privileges", appleScriptQuote(shellCmd))
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		return exec.Command("powershell", "-NoProfile", "-Command", startProcessScript(path, args, wait, "-Verb RunAs"))
+	default:
+		return nil
+	}
+}
+
+// userCommand rewraps cmd so it runs as another user. sudo credentials are
+// validated up front; Windows runas asks for the user's password itself.
+func userCommand(cmd *exec.Cmd, user string) (*exec.Cmd, error) {
+	if runtime.GOOS != "windows" {
+		if err := authenticateSudo(); err != nil {
+			return nil, err
+		}
+	}
+
+	asUser := runAsUserCommand(runtime.GOOS, user, cmd.Path, cmd.Args[1:])
+	if asUser == nil {
+		return nil, fmt.Errorf("launching as another user is not supported on %s", runtime.GOOS)
+	}
+	if runtime.GOOS == "windows" {
+		asUser.Stdin, asUser.Stdout, asUser.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+	return asUser, nil
+}
+
+// runAsUserCommand builds the platform command that runs path as user
+func runAsUserCommand(goos, user, path string, args []string) *exec.Cmd {
+	switch goos {
+	case "linux", "darwin":
+		return exec.Command("sudo", append([]string{"-n", "-u", user, "--", path}, args...)...)
+	case "windows":
+		return exec.Command("runas", "/user:"+user, windowsCommandLine(append([]string{path}, args...)))
+	default:
+		return nil
+	}
+}
+
+// authenticateSudo prompts for the sudo password on the terminal if needed
+func authenticateSudo() error {
+	cmd := exec.Command("sudo", "-v")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sudo authentication failed: %w", err)
+	}
+	return nil
+}
+
+// startProcessScript builds a PowerShell Start-Process invocation
+func startProcessScript(path string, args []string, wait bool, extra string) string {
+	script := "Start-Process -FilePath " + powerShellQuote(path)
+	if len(args) > 0 {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = powerShellQuote(arg)
+		}
+		script += " -ArgumentList " + strings.Join(quoted, ",")
+	}
+	if extra != "" {
+		script += " " + extra
+	}
+	if wait {
+		script += " -Wait"
+	}
+	return script
+}
+
+// windowsCommandLine joins args into a Windows command line, quoting
+// arguments with spaces or quotes
+func windowsCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote joins args into a POSIX shell command line
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// appleScriptQuote quotes s as an AppleScript string literal
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellQuote quotes s as a single-quoted PowerShell string
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
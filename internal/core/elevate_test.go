@@ -0,0 +1,85 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestElevatedCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		wait bool
+		want string
+	}{
+		{
+			name: "linux sudo",
+			goos: "linux",
+			want: "sudo -n -- /usr/bin/wireshark -k it's",
+		},
+		{
+			name: "macOS administrator privileges",
+			goos: "darwin",
+			want: `osascript -e do shell script "'/usr/bin/wireshark' '-k' 'it'\\''s' > /dev/null 2>&1 &" with administrator privileges`,
+		},
+		{
+			name: "macOS waits in the foreground",
+			goos: "darwin",
+			wait: true,
+			want: `osascript -e do shell script "'/usr/bin/wireshark' '-k' 'it'\\''s'" with administrator privileges`,
+		},
+		{
+			name: "windows RunAs",
+			goos: "windows",
+			wait: true,
+			want: "powershell -NoProfile -Command Start-Process -FilePath '/usr/bin/wireshark' -ArgumentList '-k','it''s' -Verb RunAs -Wait",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := elevatedCommand(tt.goos, "/usr/bin/wireshark", []string{"-k", "it's"}, tt.wait)
+			if cmd == nil {
+				t.Fatalf("elevatedCommand(%s) returned nil", tt.goos)
+			}
+			if got := strings.Join(cmd.Args, " "); got != tt.want {
+				t.Errorf("elevatedCommand(%s) = %s\nwant %s", tt.goos, got, tt.want)
+			}
+		})
+	}
+
+	if elevatedCommand("plan9", "/bin/app", nil, false) != nil {
+		t.Error("elevatedCommand() should not support unknown platforms")
+	}
+}
+
+func TestRunAsUserCommand(t *testing.T) {
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{"linux", []string{"sudo", "-n", "-u", "kiosk", "--", "/usr/bin/firefox", "--kiosk", "my page"}},
+		{"darwin", []string{"sudo", "-n", "-u", "kiosk", "--", "/usr/bin/firefox", "--kiosk", "my page"}},
+		{"windows", []string{"runas", "/user:kiosk", `/usr/bin/firefox --kiosk "my page"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmd := runAsUserCommand(tt.goos, "kiosk", "/usr/bin/firefox", []string{"--kiosk", "my page"})
+			if cmd == nil {
+				t.Fatalf("runAsUserCommand(%s) returned nil", tt.goos)
+			}
+			if got := strings.Join(cmd.Args, "|"); got != strings.Join(tt.want, "|") {
+				t.Errorf("runAsUserCommand(%s) = %q, want %q", tt.goos, cmd.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	got := windowsCommandLine([]string{`C:\Program Files\App\app.exe`, "-x", `say "hi"`, ""})
+	want := `"C:\Program Files\App\app.exe" -x "say \"hi\"" ""`
+	if got != want {
+		t.Errorf("windowsCommandLine() = %s, want %s", got, want)
+	}
+}
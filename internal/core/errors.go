@@ -0,0 +1,36 @@
+package core
+
+import "errors"
+
+// Errors openx wraps, for callers to tell failures apart with errors.Is
+// rather than by their messages. ErrConfigNotFound and ErrNotTerminal
+// are defined with the code returning them.
+var (
+	// ErrUnknownApp, ErrUnknownAlias, ErrUnknownGroup and ErrUnknownProject
+	// match the UnknownNameError of that kind; names looked up as an alias
+	// or group match both ErrUnknownApp and ErrUnknownGroup
+	ErrUnknownApp     = errors.New("unknown app")
+	ErrUnknownAlias   = errors.New("unknown alias")
+	ErrUnknownGroup   = errors.New("unknown group")
+	ErrUnknownProject = errors.New("unknown project")
+
+	// ErrUnknownTag is returned when no app carries a tag
+	ErrUnknownTag = errors.New("no apps tagged")
+
+	// ErrNoPathForPlatform is returned for an app without a launch path
+	// for this OS
+	ErrNoPathForPlatform = errors.New("no launch path configured")
+
+	// ErrAppNotFound is returned when an app's executable isn't there
+	ErrAppNotFound = errors.New("application not found")
+
+	// ErrKillNoMatch is returned when closing an app finds none of its
+	// processes running
+	ErrKillNoMatch = errors.New("no running processes match")
+
+	// ErrStillRunning is returned when an app's processes outlive a kill
+	ErrStillRunning = errors.New("still running")
+
+	// ErrNothingLaunched is returned when relaunching before any launch
+	ErrNothingLaunched = errors.New("nothing launched yet")
+)
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	testContent := `
+apps:
+  elsewhere:
+    plan9: /bin/elsewhere
+  idle:
+    linux: /bin/true
+    darwin: /usr/bin/true
+    windows: true.exe
+    kill: ["` + fmt.Sprintf("openx-sentinel-idle-%d", os.Getpid()) + `"]
+aliases:
+  broken: nothing
+groups:
+  work: [elsewhere]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	_, tagErr := CloseTaggedApps("games", KillOptions{})
+	_, groupErr := CloseGroup("play", KillOptions{})
+	_, lineErr := ConfigEntryLine("slack")
+	_, whichErr := Which("slack")
+	_, killErr := CloseAppWithOptions("idle", KillOptions{Yes: true})
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unknown app", LaunchApp("slack", nil), ErrUnknownApp},
+		{"alias to an unknown app", LaunchApp("broken", nil), ErrUnknownApp},
+		{"unknown alias or group", whichErr, ErrUnknownGroup},
+		{"unknown group", groupErr, ErrUnknownGroup},
+		{"unknown app entry", lineErr, ErrUnknownApp},
+		{"unknown project", LaunchProject("site"), ErrUnknownProject},
+		{"unknown tag", tagErr, ErrUnknownTag},
+		{"no path here", LaunchApp("elsewhere", nil), ErrNoPathForPlatform},
+		{"missing direct path", LaunchApp("/nonexistent/openx-app", nil), ErrAppNotFound},
+		{"nothing running", killErr, ErrKillNoMatch},
+		{"nothing to relaunch", RelaunchLast(LaunchOptions{}), ErrNothingLaunched},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: error %v doesn't match %v", tt.name, tt.err, tt.want)
+		}
+	}
+
+	// Closing several apps counts the ones not running as closed
+	if _, err := CloseApps([]string{"idle"}, KillOptions{Yes: true}); err != nil {
+		t.Errorf("CloseApps() of an app not running = %v, want no error", err)
+	}
+
+	if err := UnknownName(&Config{}, ListKindGroup, "play"); errors.Is(err, ErrUnknownApp) {
+		t.Errorf("unknown group %v matches ErrUnknownApp", err)
+	}
+	if err := UnknownName(&Config{}, ListKindProject, "site"); errors.Is(err, ErrUnknownApp) {
+		t.Errorf("unknown project %v matches ErrUnknownApp", err)
+	}
+}
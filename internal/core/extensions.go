@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// appForFile returns the app the extensions section maps a file to
+func appForFile(config *Config, path string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" || isURL(path) {
+		return "", false
+	}
+
+	for key, app := range config.Extensions {
+		if "."+strings.TrimPrefix(strings.ToLower(key), ".") == ext {
+			return app, true
+		}
+	}
+	return "", false
+}
+
+// OpenFile opens a file with the app its extension is mapped to. It
+// reports false when no mapping applies, leaving the file to the system
+// default application.
+func OpenFile(path string) (bool, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	app, ok := appForFile(config, path)
+	if !ok {
+		return false, nil
+	}
+	return true, LaunchApp(app, []string{path})
+}
+
+// OpenTarget opens a file or URL: with the app named by with when given,
+// otherwise with the app its extension is mapped to, otherwise with the
+// system default application
+func OpenTarget(target, with string) error {
+	if with != "" {
+		return LaunchApp(with, []string{target})
+	}
+
+	if handled, err := OpenFile(target); handled {
+		return err
+	}
+
+	// The URL opener hands files to their default application as well
+	if err := urlOpenerCommand(target).Run(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	return nil
+}
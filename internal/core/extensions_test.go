@@ -0,0 +1,86 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestAppForFile(t *testing.T) {
+	config := &Config{Extensions: map[string]string{
+		".psd": "photoshop",
+		"sql":  "tableplus",
+		".MD":  "obsidian",
+	}}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"design/logo.psd", "photoshop"},
+		{"~/queries/report.SQL", "tableplus"},
+		{"README.md", "obsidian"},
+		{"notes.txt", ""},
+		{"Makefile", ""},
+		{"https://example.com/page.md", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			app, ok := appForFile(config, tt.path)
+			if app != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("appForFile(%q) = %q, %v, want %q", tt.path, app, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	testContent := `
+apps:
+  viewer:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+extensions:
+  .log: viewer`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	handled, err := OpenFile("server.log")
+	if !handled || err != nil {
+		t.Errorf("OpenFile(server.log) = %v, %v, want handled", handled, err)
+	}
+
+	handled, err = OpenFile("photo.jpg")
+	if handled || err != nil {
+		t.Errorf("OpenFile(photo.jpg) = %v, %v, want unhandled", handled, err)
+	}
+}
+
+func TestOpenTarget_With(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	testContent := `
+apps:
+  viewer:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := OpenTarget("https://example.com", "viewer"); err != nil {
+		t.Errorf("OpenTarget(--with viewer) unexpected error: %v", err)
+	}
+	if err := OpenTarget("https://example.com", "nonexistent"); err == nil {
+		t.Error("OpenTarget(--with nonexistent) expected error but got none")
+	}
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LaunchGroup launches every member of a workspace group
+func LaunchGroup(name string) error {
+	_, err := LaunchGroupWithConcurrency(name, defaultLaunchConcurrency)
+	return err
+}
+
+// LaunchGroupWithConcurrency launches a workspace group with at most
+// concurrency members starting at once and returns per-app results
+func LaunchGroupWithConcurrency(name string, concurrency int) ([]LaunchResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	members, exists := lookupGroup(config, name)
+	if !exists {
+		return nil, UnknownName(config, ListKindGroup, name)
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group %s has no members", name)
+	}
+
+	info("Launching group: %s", name)
+	plan, err := buildLaunchPlan(config, members)
+	if err != nil {
+		return nil, err
+	}
+	return executeLaunchPlan(config, plan, concurrency)
+}
+
+// CloseResult is the outcome of closing a single group member
+type CloseResult struct {
+	Alias   string      `json:"alias"`
+	Running bool        `json:"running"`          // whether the app had anything to close
+	Report  *KillReport `json:"report,omitempty"` // nil when the app couldn't be looked up
+	Err     error       `json:"-"`
+}
+
+// CloseGroup closes every member of a workspace group, last launched
+// first, and returns per-app results. Dependencies pulled in by needs are
+// left running, other apps may rely on them.
+func CloseGroup(name string, opts KillOptions) ([]CloseResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	members, exists := lookupGroup(config, name)
+	if !exists {
+		return nil, UnknownName(config, ListKindGroup, name)
+	}
+
+	info("Closing group: %s", name)
+	results := closeMembers(config, shutdownOrder(config, members), opts)
+	return results, reportClosed("apps in "+name, results)
+}
+
+// CloseAllApps closes every configured app that is running, apps before
+// the apps they need. Protected apps are left alone unless opts.Force is set.
+func CloseAllApps(opts KillOptions) ([]CloseResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	statuses, err := ProcessStatuses(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var running []GroupMember
+	for _, status := range statuses {
+		if status.Running {
+			running = append(running, GroupMember{App: status.Name})
+		}
+	}
+	if len(running) == 0 {
+		info("No configured apps are running")
+		return nil, nil
+	}
+
+	results := closeMembers(config, shutdownOrder(config, running), opts)
+	return results, reportClosed("running apps", results)
+}
+
+// CloseTaggedApps closes every app carrying the tag, apps before the apps
+// they need, and errors when any of them failed to close
+func CloseTaggedApps(tag string, opts KillOptions) ([]CloseResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := taggedApps(config, tag)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrUnknownTag, tag)
+	}
+
+	members := make([]GroupMember, len(names))
+	for i, name := range names {
+		members[i] = GroupMember{App: name}
+	}
+	info("Closing apps tagged: %s", tag)
+	results := closeMembers(config, shutdownOrder(config, members), opts)
+	return results, reportClosed("apps tagged "+tag, results)
+}
+
+// taggedApps returns the sorted names of the apps carrying a tag, compared
+// case-insensitively
+func taggedApps(config *Config, tag string) []string {
+	var names []string
+	for name, app := range config.Apps {
+		for _, t := range app.Tags {
+			if strings.EqualFold(t, tag) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shutdownOrder returns the aliases of a group's members in the stages
+// they close in: the launch plan's stages reversed, so apps stop before the
+// apps they need while the apps of one stage close side by side
+func shutdownOrder(config *Config, members []GroupMember) [][]string {
+	plan, err := buildLaunchPlan(config, members)
+	if err != nil {
+		// A broken plan can't launch, but its members can still be closed
+		aliases := make([]string, 0, len(members))
+		for i := len(members) - 1; i >= 0; i-- {
+			aliases = append(aliases, members[i].App)
+		}
+		return [][]string{aliases}
+	}
+
+	var stages [][]string
+	launchStages := planStages(plan)
+	for i := len(launchStages) - 1; i >= 0; i-- {
+		var aliases []string
+		for j := len(launchStages[i]) - 1; j >= 0; j-- {
+			if step := plan[launchStages[i][j]]; !step.Implicit {
+				aliases = append(aliases, step.Alias)
+			}
+		}
+		if len(aliases) > 0 {
+			stages = append(stages, aliases)
+		}
+	}
+	return stages
+}
+
+// closeMembers closes the apps one stage after another, up to
+// defaultKillConcurrency of a stage at once, and returns their results in
+// closing order
+func closeMembers(config *Config, stages [][]string, opts KillOptions) []CloseResult {
+	var results []CloseResult
+	for _, stage := range stages {
+		// Failures are reported with the results, by reportClosed
+		stageResults, _ := closeMultipleApps(config, stage, opts, defaultKillConcurrency)
+		results = append(results, stageResults...)
+	}
+	return results
+}
+
+// reportClosed prints which apps were closed and which weren't running,
+// describing them as what, and errors when any of them failed to close
+func reportClosed(what string, results []CloseResult) error {
+	var closed, idle, protected []string
+	errors := 0
+	for _, result := range results {
+		switch {
+		case result.Report != nil && result.Report.Protected:
+			protected = append(protected, result.Alias)
+		case result.Err != nil:
+			fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", result.Alias, result.Err)
+			errors++
+		case result.Running:
+			closed = append(closed, result.Alias)
+		default:
+			idle = append(idle, result.Alias)
+		}
+	}
+
+	summary := fmt.Sprintf("Closed %d of %d %s", len(closed), len(results), what)
+	if len(closed) > 0 {
+		summary += ": " + strings.Join(closed, ", ")
+	}
+	info("%s", summary)
+	if len(idle) > 0 {
+		info("Not running: %s", strings.Join(idle, ", "))
+	}
+	if len(protected) > 0 {
+		info("Protected, left alone without --force: %s", strings.Join(protected, ", "))
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("%d apps failed to close", errors)
+	}
+	return nil
+}
+
+// IsGroup checks if the given name is a configured workspace group
+func IsGroup(name string) bool {
+	config, err := loadConfig()
+	if err != nil {
+		return false
+	}
+
+	_, exists := lookupGroup(config, name)
+	return exists
+}
+
+// lookupGroup returns the members of a group, matching name as written or
+// lower case, the way groups are saved
+func lookupGroup(cfg *Config, name string) ([]GroupMember, bool) {
+	if members, exists := cfg.Groups[name]; exists {
+		return members, true
+	}
+	members, exists := cfg.Groups[strings.ToLower(name)]
+	return members, exists
+}
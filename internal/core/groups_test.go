@@ -0,0 +1,453 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLaunchGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	testContent := `
+apps:
+  echo:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  noop:
+    darwin: "/usr/bin/true"
+    linux: "/bin/true"
+
+aliases:
+  e: echo
+
+groups:
+  work: [echo, e, noop]
+  broken: [echo, missing]
+  empty: []`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		group   string
+		wantErr bool
+	}{
+		{
+			name:    "all members valid",
+			group:   "work",
+			wantErr: false,
+		},
+		{
+			name:    "name in another case",
+			group:   "Work",
+			wantErr: false,
+		},
+		{
+			name:    "unknown member",
+			group:   "broken",
+			wantErr: true,
+		},
+		{
+			name:    "empty group",
+			group:   "empty",
+			wantErr: true,
+		},
+		{
+			name:    "unknown group",
+			group:   "nonexistent",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LaunchGroup(tt.group)
+			if tt.wantErr && err == nil {
+				t.Errorf("LaunchGroup(%s) expected error but got none", tt.group)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("LaunchGroup(%s) unexpected error: %v", tt.group, err)
+			}
+		})
+	}
+}
+
+func TestIsGroup(t *testing.T) {
+	testContent := `
+apps:
+  echo:
+    linux: "/bin/echo"
+
+groups:
+  work: [echo]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if !IsGroup("work") {
+		t.Error("IsGroup(work) = false, want true")
+	}
+	if !IsGroup("WORK") {
+		t.Error("IsGroup(WORK) = false, want true")
+	}
+	if IsGroup("echo") {
+		t.Error("IsGroup(echo) = true, want false")
+	}
+}
+
+func TestGroupMember_YAML(t *testing.T) {
+	testContent := `
+apps:
+  docker:
+    linux: "/bin/true"
+  dbclient:
+    linux: "/bin/true"
+
+groups:
+  stack:
+    - docker
+    - app: dbclient
+      delay: 2s`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	members := config.Groups["stack"]
+	if len(members) != 2 {
+		t.Fatalf("stack has %d members, want 2", len(members))
+	}
+	if members[0].App != "docker" || members[0].Delay != 0 {
+		t.Errorf("members[0] = %+v, want docker without delay", members[0])
+	}
+	if members[1].App != "dbclient" || members[1].Delay != 2*time.Second {
+		t.Errorf("members[1] = %+v, want dbclient with 2s delay", members[1])
+	}
+
+	// Round trip keeps the short form for members without options
+	if err := saveConfig(config); err != nil {
+		t.Fatalf("saveConfig() failed: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "- docker\n") || !strings.Contains(string(data), "delay: 2s") {
+		t.Errorf("saved groups not in expected form:\n%s", data)
+	}
+}
+
+func TestCloseGroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-group-close-%d", os.Getpid())
+	startMarkerProcess(t, marker)
+
+	testContent := `
+apps:
+  server:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `"]
+  idle:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-idle"]
+
+groups:
+  work: [server, idle]
+  broken: [server, missing]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	opts := KillOptions{Timeout: 500 * time.Millisecond, Yes: true}
+	results, err := CloseGroup("work", opts)
+	if err != nil {
+		t.Fatalf("CloseGroup() unexpected error: %v", err)
+	}
+
+	// Members close last launched first
+	want := []CloseResult{{Alias: "idle", Running: false}, {Alias: "server", Running: true}}
+	if len(results) != len(want) {
+		t.Fatalf("CloseGroup() = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i].Alias != want[i].Alias || results[i].Running != want[i].Running || results[i].Err != nil {
+			t.Errorf("CloseGroup()[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+	if report := results[1].Report; report == nil || len(report.Patterns) != 1 || report.Patterns[0].Method != KillMethodGraceful {
+		t.Errorf("CloseGroup() report for server = %+v, want a graceful close", report)
+	}
+	if isProcessRunning(marker) {
+		t.Error("running group member should be closed")
+	}
+
+	if _, err := CloseGroup("broken", opts); err == nil {
+		t.Error("CloseGroup() expected error for an unknown member")
+	}
+	if _, err := CloseGroup("nonexistent", opts); err == nil {
+		t.Error("CloseGroup() expected error for an unknown group")
+	}
+}
+
+func TestCloseGroup_Concurrent(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-group-concurrent-%d", os.Getpid())
+	for _, name := range []string{marker + "-a", marker + "-b"} {
+		// Ignoring TERM, each app sits out the whole graceful timeout
+		cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 30", name)
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		go cmd.Wait()
+		defer cmd.Process.Kill()
+	}
+
+	testContent := `
+apps:
+  a:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-a"]
+  b:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-b"]
+
+groups:
+  slow: [a, b]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	timeout := time.Second
+	start := time.Now()
+	var results []CloseResult
+	var err error
+	stdout := captureStdout(t, func() {
+		results, err = CloseGroup("slow", KillOptions{Timeout: timeout, Yes: true})
+	})
+	if err != nil {
+		t.Fatalf("CloseGroup() unexpected error: %v", err)
+	}
+
+	// One after the other, the two timeouts would add up
+	if elapsed := time.Since(start); elapsed >= 2*timeout {
+		t.Errorf("CloseGroup() took %s, want the members' timeouts to overlap", elapsed)
+	}
+	for _, result := range results {
+		if !result.Running || result.Report.Patterns[0].Method != KillMethodForced {
+			t.Errorf("CloseGroup() result for %s = %+v, want a forced close", result.Alias, result.Report)
+		}
+		// Progress lines of apps closing side by side say which app they are about
+		if want := result.Alias + ": Still running after 1s, force killing"; !strings.Contains(stdout, want) {
+			t.Errorf("CloseGroup() printed %q, want a line starting %q", stdout, want)
+		}
+	}
+}
+
+func TestCloseAllApps(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-close-all-%d", os.Getpid())
+	for _, name := range []string{marker + "-web", marker + "-db", marker + "-vault"} {
+		startMarkerProcess(t, name)
+	}
+
+	testContent := `
+apps:
+  web:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-web"]
+    needs: [db]
+  db:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-db"]
+  vault:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-vault"]
+    protected: true
+  stopped:
+    linux: "/bin/sleep"
+    kill: ["` + marker + `-stopped"]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	results, err := CloseAllApps(KillOptions{Timeout: time.Second, Yes: true})
+	if err != nil {
+		t.Fatalf("CloseAllApps() unexpected error: %v", err)
+	}
+
+	// Apps close before the apps they need, stopped apps aren't touched
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Alias)
+	}
+	if want := []string{"web", "vault", "db"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("CloseAllApps() closed %v, want %v", got, want)
+	}
+	if isProcessRunning(marker+"-web") || isProcessRunning(marker+"-db") {
+		t.Error("running apps should be closed")
+	}
+	if !isProcessRunning(marker + "-vault") {
+		t.Error("protected app should still be running")
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	testContent := `
+apps:
+  web:
+    linux: "/bin/echo"
+    needs: [api]
+  api:
+    linux: "/bin/echo"
+    needs: [db]
+  db:
+    linux: "/bin/echo"
+  docs:
+    linux: "/bin/echo"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		members []GroupMember
+		want    string
+	}{
+		// web launches after api, so it closes first; db isn't a member
+		{"dependents first", []GroupMember{{App: "web"}, {App: "api"}, {App: "docs"}}, "docs,web|api"},
+		{"reverse member order", []GroupMember{{App: "db"}, {App: "docs"}}, "docs,db"},
+		{"unknown member kept", []GroupMember{{App: "docs"}, {App: "missing"}}, "missing,docs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinStages(shutdownOrder(config, tt.members)); got != tt.want {
+				t.Errorf("shutdownOrder() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// joinStages writes closing stages as a,b|c
+func joinStages(stages [][]string) string {
+	parts := make([]string, len(stages))
+	for i, stage := range stages {
+		parts[i] = strings.Join(stage, ",")
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestTaggedApps(t *testing.T) {
+	testContent := `
+apps:
+  chrome:
+    linux: "/bin/echo"
+    tags: [browsers, work]
+  firefox:
+    linux: "/bin/echo"
+    tags: [Browsers]
+  slack:
+    linux: "/bin/echo"
+    tags: [work]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"browsers", "chrome,firefox"},
+		{"WORK", "chrome,slack"},
+		{"games", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(taggedApps(config, tt.tag), ","); got != tt.want {
+			t.Errorf("taggedApps(%q) = %s, want %s", tt.tag, got, tt.want)
+		}
+	}
+
+	if _, err := CloseTaggedApps("games", KillOptions{}); err == nil {
+		t.Error("CloseTaggedApps() expected error for a tag no app has")
+	}
+}
+
+func TestRestartGroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	marker := fmt.Sprintf("openx-group-restart-%d", os.Getpid())
+	cmd := exec.Command("sh", "-c", "sleep 30", marker)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go cmd.Wait()
+	defer cmd.Process.Kill()
+
+	testContent := `
+apps:
+  server:
+    linux: "/bin/echo"
+    kill: ["` + marker + `"]
+
+groups:
+  work: [server]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	opts := KillOptions{Timeout: 500 * time.Millisecond, Yes: true}
+	results, err := RestartGroup("work", opts, 1)
+	if err != nil {
+		t.Fatalf("RestartGroup() unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Alias != "server" || results[0].Err != nil {
+		t.Errorf("RestartGroup() = %+v, want server launched again", results)
+	}
+	if isProcessRunning(marker) {
+		t.Error("RestartGroup() should close the running member first")
+	}
+
+	if _, err := RestartGroup("nonexistent", opts, 1); err == nil {
+		t.Error("RestartGroup() expected error for an unknown group")
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthCheckTimeout bounds a single readiness probe
+const healthCheckTimeout = 2 * time.Second
+
+// checkHealth probes a readiness target. http(s):// URLs must answer with a
+// non-error status, tcp://host:port targets must accept a connection.
+func checkHealth(target string) bool {
+	switch {
+	case strings.HasPrefix(target, "tcp://"):
+		conn, err := net.DialTimeout("tcp", strings.TrimPrefix(target, "tcp://"), healthCheckTimeout)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		client := http.Client{Timeout: healthCheckTimeout}
+		resp, err := client.Get(target)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode < 400
+	default:
+		return false
+	}
+}
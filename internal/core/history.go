@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// maxDoctorRuns is how many doctor runs the state file keeps
+const maxDoctorRuns = 50
+
+// doctorRun is what a doctor run found, kept to show drift over time
+type doctorRun struct {
+	Time    time.Time               `json:"time"`
+	Summary Summary                 `json:"summary"`
+	Apps    map[string]doctorRunApp `json:"apps"`
+}
+
+// doctorRunApp is the state of one app in a doctor run
+type doctorRunApp struct {
+	Status  string `json:"status"`
+	Running bool   `json:"running,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// newDoctorRun condenses a report into a history entry
+func newDoctorRun(report *DoctorReport, at time.Time) doctorRun {
+	run := doctorRun{Time: at, Summary: report.Summary, Apps: map[string]doctorRunApp{}}
+	for _, app := range report.Apps {
+		run.Apps[app.Name] = doctorRunApp{Status: app.Status, Running: app.Running, Version: app.Version}
+	}
+	return run
+}
+
+// recordDoctorRun appends a run to the history in the state file and
+// returns the run before it, nil on the first run. A run finding the same
+// as the last one only moves the last one's time.
+func recordDoctorRun(run doctorRun) (*doctorRun, error) {
+	var previous *doctorRun
+	err := updateState(func(state *launchState) error {
+		if n := len(state.DoctorRuns); n > 0 {
+			last := state.DoctorRuns[n-1]
+			previous = &last
+			if reflect.DeepEqual(last.Apps, run.Apps) {
+				state.DoctorRuns[n-1].Time = run.Time
+				return nil
+			}
+		}
+		state.DoctorRuns = append(state.DoctorRuns, run)
+		if extra := len(state.DoctorRuns) - maxDoctorRuns; extra > 0 {
+			state.DoctorRuns = state.DoctorRuns[extra:]
+		}
+		return nil
+	})
+	return previous, err
+}
+
+// RunDoctorDiff performs a health check and prints what changed since the
+// last doctor run: apps gone missing or installed, started or stopped,
+// updated, or added to and removed from the config
+func RunDoctorDiff(w io.Writer) error {
+	report, err := BuildDoctorReport(false)
+	if err != nil {
+		return err
+	}
+	run := newDoctorRun(report, time.Now())
+	previous, err := recordDoctorRun(run)
+	if err != nil {
+		return err
+	}
+
+	if previous == nil {
+		fmt.Fprintln(w, "No earlier doctor run to compare with; this one is recorded for next time")
+		return nil
+	}
+	changes := diffDoctorRuns(*previous, run)
+	since := previous.Time.Local().Format("2006-01-02 15:04")
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No changes since the last doctor run (%s)\n", since)
+		return nil
+	}
+	fmt.Fprintf(w, "Changes since the last doctor run (%s):\n", since)
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\n", change)
+	}
+	return nil
+}
+
+// diffDoctorRuns describes how each app changed between two runs, by app
+// name
+func diffDoctorRuns(before, after doctorRun) []string {
+	names := map[string]bool{}
+	for name := range before.Apps {
+		names[name] = true
+	}
+	for name := range after.Apps {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, name := range sorted {
+		was, existed := before.Apps[name]
+		now, exists := after.Apps[name]
+		switch {
+		case !existed:
+			changes = append(changes, fmt.Sprintf("+ %s added to the config (%s)", name, now.Status))
+			continue
+		case !exists:
+			changes = append(changes, fmt.Sprintf("- %s removed from the config", name))
+			continue
+		}
+
+		if was.Status != now.Status {
+			changes = append(changes, fmt.Sprintf("%s %s is now %s (was %s)", getStatusIcon(now.Status), name, now.Status, was.Status))
+		}
+		if was.Version != now.Version && was.Version != "" && now.Version != "" {
+			changes = append(changes, fmt.Sprintf("~ %s version %s → %s", name, was.Version, now.Version))
+		}
+		if was.Running != now.Running {
+			if now.Running {
+				changes = append(changes, fmt.Sprintf("▶ %s started running", name))
+			} else {
+				changes = append(changes, fmt.Sprintf("■ %s stopped running", name))
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffDoctorRuns(t *testing.T) {
+	before := doctorRun{Apps: map[string]doctorRunApp{
+		"chrome":  {Status: "available", Running: true, Version: "129.0"},
+		"postman": {Status: "available"},
+		"slack":   {Status: "missing"},
+		"old":     {Status: "available"},
+		"code":    {Status: "available", Version: "1.95.3"},
+	}}
+	after := doctorRun{Apps: map[string]doctorRunApp{
+		"chrome":  {Status: "available", Version: "130.0"},
+		"postman": {Status: "missing"},
+		"slack":   {Status: "available", Running: true},
+		"zed":     {Status: "available"},
+		"code":    {Status: "available", Version: "1.95.3"},
+	}}
+
+	want := []string{
+		"~ chrome version 129.0 → 130.0",
+		"■ chrome stopped running",
+		"- old removed from the config",
+		"✗ postman is now missing (was available)",
+		"✓ slack is now available (was missing)",
+		"▶ slack started running",
+		"+ zed added to the config (available)",
+	}
+	if got := diffDoctorRuns(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffDoctorRuns() = %q, want %q", got, want)
+	}
+	if got := diffDoctorRuns(after, after); len(got) != 0 {
+		t.Errorf("diffDoctorRuns() of the same run = %q, want none", got)
+	}
+}
+
+func TestRecordDoctorRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	available := map[string]doctorRunApp{"code": {Status: "available"}}
+	missing := map[string]doctorRunApp{"code": {Status: "missing"}}
+
+	if previous, err := recordDoctorRun(doctorRun{Time: first, Apps: available}); err != nil || previous != nil {
+		t.Fatalf("recordDoctorRun() first = %v, %v, want no previous run", previous, err)
+	}
+
+	// The same findings only move the last run's time
+	previous, err := recordDoctorRun(doctorRun{Time: first.Add(time.Hour), Apps: available})
+	if err != nil || previous == nil || !previous.Time.Equal(first) {
+		t.Fatalf("recordDoctorRun() second = %v, %v, want the first run", previous, err)
+	}
+	if _, err := recordDoctorRun(doctorRun{Time: first.Add(2 * time.Hour), Apps: missing}); err != nil {
+		t.Fatalf("recordDoctorRun() unexpected error: %v", err)
+	}
+
+	state, err := loadState()
+	if err != nil {
+		t.Fatalf("loadState() unexpected error: %v", err)
+	}
+	if len(state.DoctorRuns) != 2 || !state.DoctorRuns[0].Time.Equal(first.Add(time.Hour)) {
+		t.Errorf("DoctorRuns = %+v, want the merged run, then the changed one", state.DoctorRuns)
+	}
+
+	for i := 0; i < maxDoctorRuns; i++ {
+		apps := available
+		if i%2 == 0 {
+			apps = missing
+		}
+		recordDoctorRun(doctorRun{Time: first.Add(time.Duration(i+3) * time.Hour), Apps: apps})
+	}
+	if state, _ := loadState(); len(state.DoctorRuns) != maxDoctorRuns {
+		t.Errorf("len(DoctorRuns) = %d, want %d", len(state.DoctorRuns), maxDoctorRuns)
+	}
+}
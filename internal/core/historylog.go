@@ -0,0 +1,228 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions the history log records
+const (
+	HistoryLaunch = "launch"
+	HistoryKill   = "kill"
+)
+
+// HistoryEntry is a launch or kill in the history log
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // HistoryLaunch or HistoryKill
+	Alias  string    `json:"alias"`  // as typed, or the path of a direct launch
+	App    string    `json:"app,omitempty"`
+	Args   []string  `json:"args,omitempty"`
+}
+
+// HistoryFilter narrows the history log; zero fields match everything
+type HistoryFilter struct {
+	App   string // an app name or alias
+	Since time.Time
+	Until time.Time
+}
+
+// historyMu serializes history log writes from concurrent group launches
+var historyMu sync.Mutex
+
+// getHistoryPath returns the file logging every launch and kill, one JSON
+// object per line
+func getHistoryPath() string {
+	return filepath.Join(getStateDir(), "history.jsonl")
+}
+
+// recordHistory appends a launch or kill to the history log, rotated
+// like the app logs
+func recordHistory(action, alias, app string, args []string) {
+	entry := HistoryEntry{Time: time.Now(), Action: action, Alias: alias, App: app, Args: resolveTargets(args)}
+	if err := appendHistory(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s of %s not added to the history: %v\n", action, alias, err)
+	}
+}
+
+// appendHistory writes entry to the end of the history log
+func appendHistory(entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	path := getHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxLogSize {
+		rotateLog(path)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// History returns the launches and kills matching filter, oldest first,
+// including those in the rotated logs
+func History(filter HistoryFilter) ([]HistoryEntry, error) {
+	// The app filter takes aliases as launch and kill do
+	names := map[string]bool{}
+	if filter.App != "" {
+		app := strings.ToLower(filter.App)
+		names[app] = true
+		if config, err := loadConfig(); err == nil {
+			if name, _, err := lookupApp(config, app); err == nil {
+				names[name] = true
+			}
+		}
+	}
+
+	path := getHistoryPath()
+	files := []string{}
+	for i := maxLogBackups; i >= 1; i-- {
+		files = append(files, fmt.Sprintf("%s.%d", path, i))
+	}
+	files = append(files, path)
+
+	var entries []HistoryEntry
+	for _, file := range files {
+		read, err := readHistory(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range read {
+			if filter.App != "" && !names[strings.ToLower(entry.Alias)] && !names[entry.App] {
+				continue
+			}
+			if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+				continue
+			}
+			if !filter.Until.IsZero() && !entry.Time.Before(filter.Until) {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// readHistory reads one history log. A missing log has no entries, and a
+// line cut short by a crash is skipped.
+func readHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// RunHistory prints the launches and kills matching filter, oldest first
+func RunHistory(filter HistoryFilter, jsonOutput bool) error {
+	entries, err := History(filter)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if entries == nil {
+			entries = []HistoryEntry{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No launches or kills recorded")
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Alias
+		if entry.App != "" && entry.App != entry.Alias {
+			name += " (" + entry.App + ")"
+		}
+		fmt.Printf("%s  %-6s %-20s %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Action, name, strings.Join(entry.Args, " "))
+	}
+	return nil
+}
+
+// ParseHistoryTime reads a --since or --until time relative to now: a
+// duration ago such as 2h or 3d, today or yesterday, optionally followed by
+// a time of day, a date with an optional time, or an RFC 3339 timestamp
+func ParseHistoryTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day, clock, _ := strings.Cut(value, " ")
+	switch strings.ToLower(day) {
+	case "today":
+		return atClock(midnight, clock, value)
+	case "yesterday":
+		return atClock(midnight.AddDate(0, 0, -1), clock, value)
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use e.g. 2h, 3d, yesterday 09:00 or 2006-01-02 15:04)", value)
+}
+
+// atClock returns day at the HH:MM time of clock, or day itself when
+// clock is empty
+func atClock(day time.Time, clock, value string) (time.Time, error) {
+	if clock == "" {
+		return day, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use e.g. 2h, 3d, yesterday 09:00 or 2006-01-02 15:04)", value)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
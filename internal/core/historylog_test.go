@@ -0,0 +1,98 @@
+package core
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "duration", value: "2h", want: now.Add(-2 * time.Hour)},
+		{name: "days", value: "3d", want: time.Date(2026, 10, 13, 14, 30, 0, 0, time.Local)},
+		{name: "today", value: "today", want: time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)},
+		{name: "yesterday morning", value: "yesterday 09:00", want: time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)},
+		{name: "date", value: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)},
+		{name: "date and time", value: "2026-10-01 18:45", want: time.Date(2026, 10, 1, 18, 45, 0, 0, time.Local)},
+		{name: "RFC 3339", value: "2026-10-01T18:45:00Z", want: time.Date(2026, 10, 1, 18, 45, 0, 0, time.UTC)},
+		{name: "bad time of day", value: "today noon", wantErr: true},
+		{name: "garbage", value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHistoryTime(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHistoryTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseHistoryTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	configPath := setupTestConfig(t, `
+apps:
+  chrome:
+    linux: /usr/bin/google-chrome
+  code:
+    linux: /usr/bin/code
+aliases:
+  gc: chrome
+`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	morning := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	entries := []HistoryEntry{
+		{Time: morning, Action: HistoryLaunch, Alias: "gc", App: "chrome", Args: []string{"https://example.com"}},
+		{Time: morning.Add(time.Hour), Action: HistoryLaunch, Alias: "code", App: "code", Args: []string{"/work/app"}},
+		{Time: morning.Add(8 * time.Hour), Action: HistoryKill, Alias: "chrome", App: "chrome"},
+	}
+	// The oldest entry went to a rotated log
+	if err := appendHistory(entries[0]); err != nil {
+		t.Fatalf("appendHistory() unexpected error: %v", err)
+	}
+	if err := os.Rename(getHistoryPath(), getHistoryPath()+".1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries[1:] {
+		if err := appendHistory(entry); err != nil {
+			t.Fatalf("appendHistory() unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter HistoryFilter
+		want   []HistoryEntry
+	}{
+		{name: "everything", want: entries},
+		{name: "by app name", filter: HistoryFilter{App: "chrome"}, want: []HistoryEntry{entries[0], entries[2]}},
+		{name: "by alias", filter: HistoryFilter{App: "GC"}, want: []HistoryEntry{entries[0], entries[2]}},
+		{name: "morning", filter: HistoryFilter{Since: morning, Until: morning.Add(3 * time.Hour)}, want: entries[:2]},
+		{name: "nothing", filter: HistoryFilter{App: "slack"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := History(tt.filter)
+			if err != nil {
+				t.Fatalf("History() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("History() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
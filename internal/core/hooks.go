@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Names the logout hook is registered under
+const (
+	logoutUnitName  = "openx-logout.service"
+	logoutAgentName = "com.openx.logout"
+	logoutTaskName  = "openx logout"
+)
+
+// logoutKillSeconds bounds how long the logout hook may take to close the
+// group before the session ends anyway
+const logoutKillSeconds = 60
+
+// InstallLogoutHook registers a hook that closes a workspace group when the
+// user logs out: a systemd user unit on Linux, a launch agent on macOS and
+// a scheduled task on Windows. Installing again replaces the group.
+func InstallLogoutHook(group string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if _, exists := lookupGroup(config, group); !exists {
+		return UnknownName(config, ListKindGroup, group)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the openx executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		path := logoutUnitPath()
+		if err := writeHookFile(path, logoutUnit(exe, group)); err != nil {
+			return err
+		}
+		if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
+			return fmt.Errorf("failed to reload systemd: %w", err)
+		}
+		// The unit is started now and stopped, closing the group, when the
+		// user's systemd instance shuts down at logout
+		if err := exec.Command("systemctl", "--user", "enable", "--now", logoutUnitName).Run(); err != nil {
+			return fmt.Errorf("failed to enable %s: %w", logoutUnitName, err)
+		}
+		fmt.Printf("Installed logout hook for %s: %s\n", group, path)
+	case "darwin":
+		path := logoutAgentPath()
+		exec.Command("launchctl", "unload", path).Run() // a previous hook may be loaded
+		if err := writeHookFile(path, logoutAgentPlist(exe, group)); err != nil {
+			return err
+		}
+		if err := exec.Command("launchctl", "load", "-w", path).Run(); err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		fmt.Printf("Installed logout hook for %s: %s\n", group, path)
+	case "windows":
+		if output, err := exec.Command("schtasks", logoutTaskArgs(exe, group)...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create task: %w: %s", err, strings.TrimSpace(string(output)))
+		}
+		fmt.Printf("Installed logout hook for %s: task %q\n", group, logoutTaskName)
+		fmt.Println("It runs on logoff event 4647, which needs logon/logoff auditing enabled")
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// UninstallLogoutHook removes the logout hook
+func UninstallLogoutHook() error {
+	switch runtime.GOOS {
+	case "linux":
+		path := logoutUnitPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Println("No logout hook installed")
+			return nil
+		}
+		// disable without --now: stopping the unit would close the group
+		exec.Command("systemctl", "--user", "disable", logoutUnitName).Run()
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		exec.Command("systemctl", "--user", "daemon-reload").Run()
+		fmt.Printf("Removed logout hook: %s\n", path)
+	case "darwin":
+		path := logoutAgentPath()
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Println("No logout hook installed")
+			return nil
+		}
+		exec.Command("launchctl", "unload", "-w", path).Run()
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		fmt.Printf("Removed logout hook: %s\n", path)
+	case "windows":
+		if err := exec.Command("schtasks", "/Delete", "/TN", logoutTaskName, "/F").Run(); err != nil {
+			fmt.Println("No logout hook installed")
+			return nil
+		}
+		fmt.Printf("Removed logout hook: task %q\n", logoutTaskName)
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// writeHookFile writes a hook definition, creating its directory
+func writeHookFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// logoutUnitPath returns where the systemd user unit is installed
+func logoutUnitPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(getHomeDir(), ".config")
+	}
+	return filepath.Join(configHome, "systemd", "user", logoutUnitName)
+}
+
+// logoutAgentPath returns where the macOS launch agent is installed
+func logoutAgentPath() string {
+	return filepath.Join(getHomeDir(), "Library", "LaunchAgents", logoutAgentName+".plist")
+}
+
+// logoutUnit builds a systemd user unit that does nothing on start and
+// closes the group on stop, which systemd does at logout
+func logoutUnit(exe, group string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Close the openx group %[2]s at logout
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/true
+ExecStop=%[1]s --kill --wait %[2]s
+TimeoutStopSec=%[3]d
+
+[Install]
+WantedBy=default.target
+`, systemdQuote(exe), systemdQuote(group), logoutKillSeconds)
+}
+
+// systemdQuote quotes a unit file command argument when it needs it
+func systemdQuote(arg string) string {
+	if !strings.ContainsAny(arg, " \t\"'\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// logoutAgentPlist builds a launch agent that starts at login and waits;
+// launchd sends it SIGTERM at logout, and the trap closes the group
+func logoutAgentPlist(exe, group string) string {
+	script := logoutAgentScript(exe, group) + "; while :; do sleep 86400 & wait $!; done"
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>/bin/sh</string>
+		<string>-c</string>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>ExitTimeOut</key>
+	<integer>%d</integer>
+</dict>
+</plist>
+`, logoutAgentName, html.EscapeString(script), logoutKillSeconds)
+}
+
+// logoutAgentScript sets the trap that closes the group on SIGTERM
+func logoutAgentScript(exe, group string) string {
+	stop := shellQuote([]string{exe, "--kill", "--wait", group}) + "; exit 0"
+	return "trap " + shellQuote([]string{stop}) + " TERM"
+}
+
+// logoutTaskArgs builds the schtasks arguments for a task that closes the
+// group when Windows logs the user off (Security event 4647)
+func logoutTaskArgs(exe, group string) []string {
+	return []string{
+		"/Create", "/TN", logoutTaskName, "/F",
+		"/SC", "ONEVENT", "/EC", "Security", "/MO", "*[System[EventID=4647]]",
+		"/TR", windowsCommandLine([]string{exe, "--kill", group}),
+	}
+}
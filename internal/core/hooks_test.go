@@ -0,0 +1,60 @@
+package core
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLogoutUnit(t *testing.T) {
+	unit := logoutUnit("/home/dev/My Tools/openx", "work")
+	for _, want := range []string{
+		`ExecStop="/home/dev/My Tools/openx" --kill --wait work`,
+		"ExecStart=/bin/true",
+		"RemainAfterExit=yes",
+		"TimeoutStopSec=60",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("logoutUnit() missing %q:\n%s", want, unit)
+		}
+	}
+}
+
+func TestLogoutAgentPlist(t *testing.T) {
+	plist := logoutAgentPlist("/usr/local/bin/openx", "dev & ops")
+	for _, want := range []string{
+		"<string>com.openx.logout</string>",
+		"<key>RunAtLoad</key>",
+		"dev &amp; ops",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("logoutAgentPlist() missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestLogoutAgentScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Needs sh")
+	}
+
+	// The trap must run openx with the group intact
+	script := logoutAgentScript("echo", "it's work") + "; kill -TERM $$"
+	output, err := exec.Command("sh", "-c", script).Output()
+	if err != nil {
+		t.Fatalf("trap script failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(output)); got != "--kill --wait it's work" {
+		t.Errorf("trap ran with %q", got)
+	}
+}
+
+func TestLogoutTaskArgs(t *testing.T) {
+	got := strings.Join(logoutTaskArgs(`C:\Program Files\openx\openx.exe`, "work"), " ")
+	want := `/Create /TN openx logout /F /SC ONEVENT /EC Security /MO *[System[EventID=4647]] /TR "C:\Program Files\openx\openx.exe" --kill work`
+	if got != want {
+		t.Errorf("logoutTaskArgs() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// idleCPUShare is the share of one core below which an app counts as idle
+const idleCPUShare = 0.005
+
+// cpuSampleRetention is how long CPU samples are kept in the state file
+const cpuSampleRetention = 24 * time.Hour
+
+// cpuSample is the CPU time an app's processes had used at one moment
+type cpuSample struct {
+	PIDs []int     `json:"pids"` // top-level processes; a restart makes older samples meaningless
+	CPU  float64   `json:"cpu"`  // seconds of CPU used by the processes and their children
+	At   time.Time `json:"at"`
+}
+
+// CloseIdleApps closes the running apps that used next to no CPU over the
+// last threshold. openx isn't running in between, so every call records
+// the apps' CPU time, and an app is judged against the newest sample that
+// is at least threshold old: run it regularly, e.g. from cron.
+func CloseIdleApps(threshold time.Duration, opts KillOptions) ([]CloseResult, error) {
+	if threshold <= 0 {
+		return nil, fmt.Errorf("idle threshold must be positive, got %s", threshold)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	statuses, err := ProcessStatuses(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, status := range statuses {
+		pids = append(pids, status.tree...)
+	}
+	cpu, err := processCPUTimes(pids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var idle []string
+	sampled := 0
+	err = updateState(func(state *launchState) error {
+		samples := state.CPUSamples
+		state.CPUSamples = map[string][]cpuSample{}
+		for _, status := range statuses {
+			if len(status.tree) == 0 {
+				continue
+			}
+
+			current := cpuSample{PIDs: status.PIDs, At: now}
+			for _, pid := range status.tree {
+				current.CPU += cpu[pid]
+			}
+			isIdle, known := idleSince(samples[status.Name], current, threshold)
+			if isIdle {
+				idle = append(idle, status.Name)
+			}
+			if !known {
+				sampled++
+			}
+			state.CPUSamples[status.Name] = addCPUSample(samples[status.Name], current)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record CPU samples: %w", err)
+	}
+
+	if sampled > 0 {
+		info("Recorded the CPU time of %d apps, run again after %s to close the idle ones", sampled, threshold)
+	}
+	if len(idle) == 0 {
+		info("No apps idle for %s", threshold)
+		return nil, nil
+	}
+
+	info("Idle for %s: %s", threshold, strings.Join(idle, ", "))
+	results := closeMembers(config, [][]string{idle}, opts)
+	return results, reportClosed("idle apps", results)
+}
+
+// idleSince compares the current sample with the newest earlier sample of
+// the same processes taken at least threshold ago. known is false when
+// there is no such sample yet.
+func idleSince(samples []cpuSample, current cpuSample, threshold time.Duration) (idle, known bool) {
+	for i := len(samples) - 1; i >= 0; i-- {
+		base := samples[i]
+		elapsed := current.At.Sub(base.At)
+		if elapsed < threshold || !slices.Equal(base.PIDs, current.PIDs) {
+			continue
+		}
+		return (current.CPU-base.CPU)/elapsed.Seconds() < idleCPUShare, true
+	}
+	return false, false
+}
+
+// addCPUSample appends a sample, dropping samples of earlier processes and
+// samples past cpuSampleRetention
+func addCPUSample(samples []cpuSample, current cpuSample) []cpuSample {
+	var kept []cpuSample
+	for _, sample := range samples {
+		if slices.Equal(sample.PIDs, current.PIDs) && current.At.Sub(sample.At) <= cpuSampleRetention {
+			kept = append(kept, sample)
+		}
+	}
+	return append(kept, current)
+}
+
+// processCPUTimes returns the CPU seconds each process has used
+func processCPUTimes(pids []int) (map[int]float64, error) {
+	times := map[int]float64{}
+	if len(pids) == 0 {
+		return times, nil
+	}
+
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(pid)
+	}
+
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		// ps exits non-zero when some pids are gone, the rest still print
+		output, _ := exec.Command("ps", "-o", "pid=,time=", "-p", strings.Join(ids, ",")).Output()
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			pid, err := strconv.Atoi(fields[0])
+			seconds, ok := parseCPUTime(fields[1])
+			if err == nil && ok {
+				times[pid] = seconds
+			}
+		}
+		return times, nil
+	case "windows":
+		script := fmt.Sprintf(`Get-Process -Id %s -ErrorAction SilentlyContinue | ForEach-Object { "$($_.Id)`+"`t"+`$($_.CPU)" }`, strings.Join(ids, ","))
+		output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CPU times: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			pid, seconds, found := strings.Cut(strings.TrimSpace(line), "\t")
+			if !found {
+				continue
+			}
+			id, err1 := strconv.Atoi(pid)
+			value, err2 := strconv.ParseFloat(seconds, 64)
+			if err1 == nil && err2 == nil {
+				times[id] = value
+			}
+		}
+		return times, nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// parseCPUTime reads ps's cumulative CPU time: [DD-]HH:MM:SS on Linux,
+// MM:SS.ss on macOS
+func parseCPUTime(value string) (float64, bool) {
+	days := 0.0
+	if d, rest, found := strings.Cut(value, "-"); found {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, false
+		}
+		days, value = float64(n), rest
+	}
+
+	seconds := 0.0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return days*24*3600 + seconds, true
+}
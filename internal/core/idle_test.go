@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCPUTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{"00:01:05", 65, true},
+		{"1-00:00:01", 24*3600 + 1, true},
+		{"0:01.50", 1.5, true},
+		{"12:30", 750, true},
+		{"x-00:00:01", 0, false},
+		{"n/a", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCPUTime(tt.value)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseCPUTime(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIdleSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	current := cpuSample{PIDs: []int{812}, CPU: 100, At: now}
+
+	tests := []struct {
+		name      string
+		samples   []cpuSample
+		wantIdle  bool
+		wantKnown bool
+	}{
+		{
+			name:    "no samples",
+			samples: nil,
+		},
+		{
+			name:    "only recent samples",
+			samples: []cpuSample{{PIDs: []int{812}, CPU: 100, At: now.Add(-10 * time.Minute)}},
+		},
+		{
+			name:      "no CPU used",
+			samples:   []cpuSample{{PIDs: []int{812}, CPU: 99.5, At: now.Add(-time.Hour)}},
+			wantIdle:  true,
+			wantKnown: true,
+		},
+		{
+			name:      "busy",
+			samples:   []cpuSample{{PIDs: []int{812}, CPU: 40, At: now.Add(-time.Hour)}},
+			wantKnown: true,
+		},
+		{
+			name: "newest old enough sample wins",
+			samples: []cpuSample{
+				{PIDs: []int{812}, CPU: 10, At: now.Add(-2 * time.Hour)},
+				{PIDs: []int{812}, CPU: 100, At: now.Add(-40 * time.Minute)},
+				{PIDs: []int{812}, CPU: 100, At: now.Add(-5 * time.Minute)},
+			},
+			wantIdle:  true,
+			wantKnown: true,
+		},
+		{
+			name:    "app restarted since",
+			samples: []cpuSample{{PIDs: []int{700}, CPU: 100, At: now.Add(-time.Hour)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idle, known := idleSince(tt.samples, current, 30*time.Minute)
+			if idle != tt.wantIdle || known != tt.wantKnown {
+				t.Errorf("idleSince() = %v, %v, want %v, %v", idle, known, tt.wantIdle, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestAddCPUSample(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	samples := []cpuSample{
+		{PIDs: []int{812}, At: now.Add(-48 * time.Hour)},
+		{PIDs: []int{700}, At: now.Add(-time.Hour)},
+		{PIDs: []int{812}, At: now.Add(-time.Hour)},
+	}
+	current := cpuSample{PIDs: []int{812}, At: now}
+
+	got := addCPUSample(samples, current)
+	if len(got) != 2 || !got[0].At.Equal(now.Add(-time.Hour)) || !got[1].At.Equal(now) {
+		t.Errorf("addCPUSample() = %+v, want the hour old sample and the new one", got)
+	}
+}
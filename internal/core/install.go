@@ -0,0 +1,122 @@
+package core
+
+import (
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+// installPackage names an app in each package manager openx suggests;
+// an empty name means the manager doesn't carry it
+type installPackage struct {
+	Brew    string // Homebrew, with --cask for GUI apps
+	Winget  string // winget package id
+	Flatpak string // Flathub application id
+	Snap    string // snap name, with --classic where the snap needs it
+}
+
+// installCatalog maps the config keys of the starter templates to their
+// packages
+var installCatalog = map[string]installPackage{
+	"vscode":    {Brew: "--cask visual-studio-code", Winget: "Microsoft.VisualStudioCode", Flatpak: "com.visualstudio.code", Snap: "code --classic"},
+	"chrome":    {Brew: "--cask google-chrome", Winget: "Google.Chrome", Flatpak: "com.google.Chrome"},
+	"firefox":   {Brew: "--cask firefox", Winget: "Mozilla.Firefox", Flatpak: "org.mozilla.firefox", Snap: "firefox"},
+	"edge":      {Brew: "--cask microsoft-edge", Winget: "Microsoft.Edge", Flatpak: "com.microsoft.Edge"},
+	"brave":     {Brew: "--cask brave-browser", Winget: "Brave.Brave", Flatpak: "com.brave.Browser", Snap: "brave"},
+	"intellij":  {Brew: "--cask intellij-idea", Winget: "JetBrains.IntelliJIDEA.Ultimate", Flatpak: "com.jetbrains.IntelliJ-IDEA-Ultimate", Snap: "intellij-idea-ultimate --classic"},
+	"webstorm":  {Brew: "--cask webstorm", Winget: "JetBrains.WebStorm", Flatpak: "com.jetbrains.WebStorm", Snap: "webstorm --classic"},
+	"pycharm":   {Brew: "--cask pycharm", Winget: "JetBrains.PyCharm.Professional", Flatpak: "com.jetbrains.PyCharm-Professional", Snap: "pycharm-professional --classic"},
+	"goland":    {Brew: "--cask goland", Winget: "JetBrains.GoLand", Flatpak: "com.jetbrains.GoLand", Snap: "goland --classic"},
+	"sublime":   {Brew: "--cask sublime-text", Winget: "SublimeHQ.SublimeText.4", Flatpak: "com.sublimetext.three", Snap: "sublime-text --classic"},
+	"zed":       {Brew: "--cask zed", Winget: "ZedIndustries.Zed", Flatpak: "dev.zed.Zed"},
+	"slack":     {Brew: "--cask slack", Winget: "SlackTechnologies.Slack", Flatpak: "com.slack.Slack", Snap: "slack"},
+	"discord":   {Brew: "--cask discord", Winget: "Discord.Discord", Flatpak: "com.discordapp.Discord", Snap: "discord"},
+	"teams":     {Brew: "--cask microsoft-teams", Winget: "Microsoft.Teams"},
+	"zoom":      {Brew: "--cask zoom", Winget: "Zoom.Zoom", Flatpak: "us.zoom.Zoom", Snap: "zoom-client"},
+	"postman":   {Brew: "--cask postman", Winget: "Postman.Postman", Flatpak: "com.getpostman.Postman", Snap: "postman"},
+	"insomnia":  {Brew: "--cask insomnia", Winget: "Insomnia.Insomnia", Flatpak: "rest.insomnia.Insomnia", Snap: "insomnia"},
+	"figma":     {Brew: "--cask figma", Winget: "Figma.Figma"},
+	"notion":    {Brew: "--cask notion", Winget: "Notion.Notion"},
+	"obsidian":  {Brew: "--cask obsidian", Winget: "Obsidian.Obsidian", Flatpak: "md.obsidian.Obsidian", Snap: "obsidian --classic"},
+	"docker":    {Brew: "--cask docker", Winget: "Docker.DockerDesktop"},
+	"iterm":     {Brew: "--cask iterm2"},
+	"wezterm":   {Brew: "--cask wezterm", Winget: "wez.wezterm", Flatpak: "org.wezfurlong.wezterm"},
+	"alacritty": {Brew: "--cask alacritty", Winget: "Alacritty.Alacritty", Snap: "alacritty --classic"},
+	"dbeaver":   {Brew: "--cask dbeaver-community", Winget: "dbeaver.dbeaver", Flatpak: "io.dbeaver.DBeaverCommunity", Snap: "dbeaver-ce"},
+	"tableplus": {Brew: "--cask tableplus", Winget: "TablePlus.TablePlus"},
+	"spotify":   {Brew: "--cask spotify", Winget: "Spotify.Spotify", Flatpak: "com.spotify.Client", Snap: "spotify"},
+	"git":       {Brew: "git", Winget: "Git.Git"},
+	"node":      {Brew: "node", Winget: "OpenJS.NodeJS.LTS", Snap: "node --classic"},
+}
+
+// installManagers lists the package managers suggested on each OS, most
+// preferred first
+var installManagers = map[string][]string{
+	"darwin":  {"brew"},
+	"windows": {"winget"},
+	"linux":   {"flatpak", "snap"},
+}
+
+// installedManagers reports which package managers are on PATH, once
+var installedManagers = sync.OnceValue(func() map[string]bool {
+	found := map[string]bool{}
+	for _, manager := range installManagers[runtime.GOOS] {
+		if _, err := exec.LookPath(manager); err == nil {
+			found[manager] = true
+		}
+	}
+	return found
+})
+
+// installHint suggests the command installing a missing app, preferring a
+// package manager this machine has; it returns "" for apps the catalog
+// doesn't know
+func installHint(name string) string {
+	key := normalizeAppName(name)
+	if known, ok := knownAppKeys[key]; ok {
+		key = known
+	}
+	pkg, ok := installCatalog[key]
+	if !ok {
+		return ""
+	}
+	return installCommand(pkg, installManagers[runtime.GOOS], installedManagers())
+}
+
+// installCommand picks the first manager that is installed and carries
+// the package, or else the first that carries it
+func installCommand(pkg installPackage, managers []string, installed map[string]bool) string {
+	command := func(manager string) string {
+		switch manager {
+		case "brew":
+			if pkg.Brew != "" {
+				return "brew install " + pkg.Brew
+			}
+		case "winget":
+			if pkg.Winget != "" {
+				return "winget install -e --id " + pkg.Winget
+			}
+		case "flatpak":
+			if pkg.Flatpak != "" {
+				return "flatpak install flathub " + pkg.Flatpak
+			}
+		case "snap":
+			if pkg.Snap != "" {
+				return "sudo snap install " + pkg.Snap
+			}
+		}
+		return ""
+	}
+
+	for _, manager := range managers {
+		if c := command(manager); c != "" && installed[manager] {
+			return c
+		}
+	}
+	for _, manager := range managers {
+		if c := command(manager); c != "" {
+			return c
+		}
+	}
+	return ""
+}
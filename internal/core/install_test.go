@@ -0,0 +1,41 @@
+package core
+
+import "testing"
+
+func TestInstallCommand(t *testing.T) {
+	postman := installCatalog["postman"]
+	chrome := installCatalog["chrome"]
+
+	tests := []struct {
+		name      string
+		pkg       installPackage
+		managers  []string
+		installed map[string]bool
+		want      string
+	}{
+		{"brew cask", postman, []string{"brew"}, map[string]bool{"brew": true}, "brew install --cask postman"},
+		{"winget", postman, []string{"winget"}, map[string]bool{"winget": true}, "winget install -e --id Postman.Postman"},
+		{"installed manager first", postman, []string{"flatpak", "snap"}, map[string]bool{"snap": true}, "sudo snap install postman"},
+		{"preferred when both", postman, []string{"flatpak", "snap"}, map[string]bool{"flatpak": true, "snap": true}, "flatpak install flathub com.getpostman.Postman"},
+		{"no manager installed", postman, []string{"flatpak", "snap"}, nil, "flatpak install flathub com.getpostman.Postman"},
+		{"installed manager lacks it", chrome, []string{"flatpak", "snap"}, map[string]bool{"snap": true}, "flatpak install flathub com.google.Chrome"},
+		{"no package", installPackage{Brew: "--cask iterm2"}, []string{"winget"}, map[string]bool{"winget": true}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := installCommand(tt.pkg, tt.managers, tt.installed); got != tt.want {
+				t.Errorf("installCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallHint_UnknownApp(t *testing.T) {
+	if got := installHint("internal-tool"); got != "" {
+		t.Errorf("installHint(internal-tool) = %q, want empty", got)
+	}
+	if _, ok := installCatalog[knownAppKeys["visualstudiocode"]]; !ok {
+		t.Error("installCatalog has no entry for the key Visual Studio Code scans to")
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jetbrainsProducts maps app names to the prefixes of their JetBrains
+// config directories (e.g. IntelliJIdea2024.1)
+var jetbrainsProducts = map[string][]string{
+	"idea":     {"IntelliJIdea", "IdeaIC"},
+	"pycharm":  {"PyCharm"},
+	"webstorm": {"WebStorm"},
+	"goland":   {"GoLand"},
+	"rider":    {"Rider"},
+	"clion":    {"CLion"},
+	"phpstorm": {"PhpStorm"},
+	"rubymine": {"RubyMine"},
+	"datagrip": {"DataGrip"},
+}
+
+// recentProject is an entry of a JetBrains IDE's recent projects list
+type recentProject struct {
+	Path     string
+	OpenedAt int64 // milliseconds since the epoch, 0 when unknown
+}
+
+// jetbrainsConfigRoot returns the directory holding JetBrains IDE configs
+func jetbrainsConfigRoot() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(getHomeDir(), "Library", "Application Support", "JetBrains")
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "JetBrains")
+	default:
+		if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+			return filepath.Join(xdgConfig, "JetBrains")
+		}
+		return filepath.Join(getHomeDir(), ".config", "JetBrains")
+	}
+}
+
+// resolveJetBrainsProject turns `openx idea api` into the path of the
+// recent project best matching "api". Arguments that are existing paths,
+// flags or several arguments are left alone.
+func resolveJetBrainsProject(name string, args []string) []string {
+	prefixes, ok := jetbrainsProducts[name]
+	if !ok || len(args) != 1 || strings.HasPrefix(args[0], "-") || exists(expandTilde(args[0])) {
+		return args
+	}
+
+	projects := recentJetBrainsProjects(jetbrainsConfigRoot(), prefixes)
+	if path, ok := matchProject(args[0], projects); ok {
+		return []string{path}
+	}
+	return args
+}
+
+// recentJetBrainsProjects reads the recent projects of every installed
+// version of the product, most recently opened first
+func recentJetBrainsProjects(root string, prefixes []string) []recentProject {
+	home := getHomeDir()
+	seen := map[string]bool{}
+	var projects []recentProject
+
+	for _, prefix := range prefixes {
+		files, _ := filepath.Glob(filepath.Join(root, prefix+"*", "options", "recentProjects.xml"))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			for _, project := range parseRecentProjects(data, home) {
+				if !seen[project.Path] {
+					seen[project.Path] = true
+					projects = append(projects, project)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].OpenedAt > projects[j].OpenedAt
+	})
+	return projects
+}
+
+// parseRecentProjects reads project paths from recentProjects.xml. Newer
+// IDEs list them as additionalInfo map entries, older ones as recentPaths.
+func parseRecentProjects(data []byte, home string) []recentProject {
+	var projects []recentProject
+	var current *recentProject
+	section := ""
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		attrs := map[string]string{}
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+
+		switch {
+		case start.Name.Local == "option" && (attrs["name"] == "additionalInfo" || attrs["name"] == "recentPaths"):
+			section = attrs["name"]
+		case start.Name.Local == "entry" && section == "additionalInfo" && attrs["key"] != "":
+			projects = append(projects, recentProject{Path: expandJetBrainsPath(attrs["key"], home)})
+			current = &projects[len(projects)-1]
+		case start.Name.Local == "option" && attrs["name"] == "projectOpenTimestamp" && current != nil:
+			current.OpenedAt, _ = strconv.ParseInt(attrs["value"], 10, 64)
+		case start.Name.Local == "option" && section == "recentPaths" && attrs["value"] != "":
+			projects = append(projects, recentProject{Path: expandJetBrainsPath(attrs["value"], home)})
+		}
+	}
+
+	return projects
+}
+
+// expandJetBrainsPath replaces the $USER_HOME$ macro in a project path
+func expandJetBrainsPath(path, home string) string {
+	return filepath.FromSlash(strings.ReplaceAll(path, "$USER_HOME$", filepath.ToSlash(home)))
+}
+
+// matchProject finds the project whose directory name best matches query:
+// an exact name beats a prefix, which beats a substring. Ties go to the
+// most recently opened project.
+func matchProject(query string, projects []recentProject) (string, bool) {
+	want := normalizeAppName(query)
+	if want == "" {
+		return "", false
+	}
+
+	best, bestRank := "", 0
+	for _, project := range projects {
+		base := normalizeAppName(filepath.Base(project.Path))
+		rank := 0
+		switch {
+		case base == want:
+			rank = 3
+		case strings.HasPrefix(base, want):
+			rank = 2
+		case strings.Contains(base, want):
+			rank = 1
+		}
+		if rank > bestRank {
+			best, bestRank = project.Path, rank
+		}
+	}
+	return best, bestRank > 0
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testRecentProjects = `<application>
+  <component name="RecentProjectsManager">
+    <option name="additionalInfo">
+      <map>
+        <entry key="$USER_HOME$/src/api-gateway">
+          <value>
+            <RecentProjectMetaInfo frameTitle="api-gateway">
+              <option name="projectOpenTimestamp" value="1700000000000" />
+            </RecentProjectMetaInfo>
+          </value>
+        </entry>
+        <entry key="$USER_HOME$/src/api">
+          <value>
+            <RecentProjectMetaInfo frameTitle="api">
+              <option name="projectOpenTimestamp" value="1600000000000" />
+            </RecentProjectMetaInfo>
+          </value>
+        </entry>
+      </map>
+    </option>
+  </component>
+</application>`
+
+const testLegacyRecentProjects = `<application>
+  <component name="RecentProjectsManager">
+    <option name="recentPaths">
+      <list>
+        <option value="$USER_HOME$/work/billing" />
+      </list>
+    </option>
+  </component>
+</application>`
+
+func TestParseRecentProjects(t *testing.T) {
+	home := filepath.FromSlash("/home/dev")
+
+	projects := parseRecentProjects([]byte(testRecentProjects), home)
+	want := []recentProject{
+		{Path: filepath.Join(home, "src", "api-gateway"), OpenedAt: 1700000000000},
+		{Path: filepath.Join(home, "src", "api"), OpenedAt: 1600000000000},
+	}
+	if len(projects) != len(want) {
+		t.Fatalf("parseRecentProjects() = %v, want %v", projects, want)
+	}
+	for i := range want {
+		if projects[i] != want[i] {
+			t.Errorf("parseRecentProjects()[%d] = %v, want %v", i, projects[i], want[i])
+		}
+	}
+
+	legacy := parseRecentProjects([]byte(testLegacyRecentProjects), home)
+	if len(legacy) != 1 || legacy[0].Path != filepath.Join(home, "work", "billing") {
+		t.Errorf("parseRecentProjects(recentPaths) = %v", legacy)
+	}
+}
+
+func TestMatchProject(t *testing.T) {
+	projects := []recentProject{
+		{Path: "/src/api-gateway"},
+		{Path: "/src/api"},
+		{Path: "/src/web-api"},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+		found bool
+	}{
+		{"api", "/src/api", true},
+		{"API", "/src/api", true},
+		{"gate", "/src/api-gateway", true},
+		{"web", "/src/web-api", true},
+		{"billing", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, found := matchProject(tt.query, projects)
+			if got != tt.want || found != tt.found {
+				t.Errorf("matchProject(%q) = %q, %v, want %q, %v", tt.query, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestRecentJetBrainsProjects(t *testing.T) {
+	root := t.TempDir()
+	for dir, content := range map[string]string{
+		"IntelliJIdea2024.1": testRecentProjects,
+		"IdeaIC2023.3":       testLegacyRecentProjects,
+		"PyCharm2024.1":      testLegacyRecentProjects,
+	} {
+		options := filepath.Join(root, dir, "options")
+		if err := os.MkdirAll(options, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(options, "recentProjects.xml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	projects := recentJetBrainsProjects(root, jetbrainsProducts["idea"])
+	if len(projects) != 3 {
+		t.Fatalf("recentJetBrainsProjects() = %v, want 3 projects", projects)
+	}
+	if filepath.Base(projects[0].Path) != "api-gateway" {
+		t.Errorf("most recent project = %s, want api-gateway", projects[0].Path)
+	}
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// KillPreview is what openx --kill would do to an app right now
+type KillPreview struct {
+	Alias     string         `json:"alias"`
+	Derived   bool           `json:"derived"`             // patterns come from the launch path, not kill
+	Protected bool           `json:"protected,omitempty"` // the kill needs --force
+	Target    string         `json:"target,omitempty"`    // docker container or Store package stopped instead
+	Patterns  []PatternMatch `json:"patterns,omitempty"`
+}
+
+// PatternMatch is the processes one kill pattern selects
+type PatternMatch struct {
+	Pattern   string           `json:"pattern"`
+	Exclude   []string         `json:"exclude,omitempty"` // exclusions that apply to the pattern
+	Processes []MatchedProcess `json:"processes"`
+}
+
+// MatchedProcess is a process a kill would stop, or leave alone when
+// Excluded is set
+type MatchedProcess struct {
+	PID      int    `json:"pid"`
+	Command  string `json:"command"`
+	Child    bool   `json:"child,omitempty"`    // killed along with a matched parent
+	Excluded bool   `json:"excluded,omitempty"` // matched, but kill_exclude or protected spares it
+}
+
+// RunKillTest prints the kill patterns of an app and the processes they
+// match, without killing anything
+func RunKillTest(alias string, jsonOutput bool) error {
+	preview, err := PreviewKill(alias)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(preview)
+	}
+
+	if preview.Protected {
+		fmt.Printf("%s is protected, --kill needs --force\n", alias)
+	}
+	if preview.Target != "" {
+		fmt.Printf("%s is stopped as a whole: %s\n", alias, preview.Target)
+		return nil
+	}
+
+	source := "explicit"
+	if preview.Derived {
+		source = "derived from the launch path"
+	}
+	fmt.Printf("Kill patterns for %s (%s):\n", alias, source)
+	for _, match := range preview.Patterns {
+		fmt.Printf("  %s\n", match.Pattern)
+		if len(match.Processes) == 0 {
+			fmt.Printf("    %sno matching processes%s\n", ColorGray, ColorReset)
+		}
+		for _, p := range match.Processes {
+			switch {
+			case p.Excluded:
+				fmt.Printf("    %s%-7d %s (excluded)%s\n", ColorGray, p.PID, p.Command, ColorReset)
+			case p.Child:
+				fmt.Printf("    %-7d   %s (child)\n", p.PID, p.Command)
+			default:
+				fmt.Printf("    %-7d %s\n", p.PID, p.Command)
+			}
+		}
+	}
+	return nil
+}
+
+// PreviewKill resolves an app's kill patterns and matches them against the
+// running processes with the same code a kill uses
+func PreviewKill(alias string) (*KillPreview, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	name, app, err := lookupApp(config, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &KillPreview{Alias: alias, Protected: app.Protected, Derived: len(app.Kill) == 0}
+	if isDockerApp(app) {
+		preview.Target = "docker container " + dockerContainerName(name, app)
+		return preview, nil
+	}
+	if isUWPOnly(app) {
+		aumid, _ := uwpAppID(app.GetLaunchPath())
+		preview.Target = "Store package " + uwpPackageFamily(aumid)
+		return preview, nil
+	}
+
+	patterns := appKillPatterns(app)
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no kill patterns available for %s", alias)
+	}
+	for _, pattern := range patterns {
+		if _, err := parseKillPattern(pattern); err != nil {
+			return nil, fmt.Errorf("%s: %w", alias, err)
+		}
+	}
+
+	processes, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	exclude := append(append(append([]string{}, app.KillExclude...), config.Protected...), defaultProtectedProcesses...)
+	for _, pattern := range patterns {
+		preview.Patterns = append(preview.Patterns, matchPattern(processes, pattern, exclude))
+	}
+	return preview, nil
+}
+
+// matchPattern lists the processes a kill pattern stops: the matches, the
+// children that go with them, and the matches exclusions spare
+func matchPattern(processes []processInfo, pattern string, exclude []string) PatternMatch {
+	match := PatternMatch{Pattern: pattern, Exclude: exclusionsFor(pattern, exclude), Processes: []MatchedProcess{}}
+	all := matchingProcesses(processes, pattern)
+	matched := excludeProcesses(all, match.Exclude)
+
+	roots := map[int]bool{}
+	for _, p := range matched {
+		roots[p.PID] = true
+	}
+	tree := processTree(processes, matched, match.Exclude)
+	for _, p := range all {
+		if _, killed := tree[p.PID]; !killed && !roots[p.PID] {
+			match.Processes = append(match.Processes, MatchedProcess{PID: p.PID, Command: p.Command, Excluded: true})
+		}
+	}
+	for pid, command := range tree {
+		match.Processes = append(match.Processes, MatchedProcess{PID: pid, Command: command, Child: !roots[pid]})
+	}
+
+	// Matches first, then children, then what is spared, each by pid
+	rank := func(p MatchedProcess) int {
+		switch {
+		case p.Excluded:
+			return 2
+		case p.Child:
+			return 1
+		}
+		return 0
+	}
+	sort.Slice(match.Processes, func(i, j int) bool {
+		a, b := match.Processes[i], match.Processes[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		return a.PID < b.PID
+	})
+	return match
+}
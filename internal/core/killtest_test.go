@@ -0,0 +1,71 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPattern(t *testing.T) {
+	processes := []processInfo{
+		{PID: 500, PPID: 400, Name: "code", Command: "/usr/share/code/code"},
+		{PID: 510, PPID: 500, Name: "helper", Command: "/usr/lib/electron/helper --type=renderer"},
+		{PID: 600, PPID: 400, Name: "node", Command: "/home/dev/.vscode-server/node code-server"},
+		{PID: 700, PPID: 400, Name: "bash", Command: "bash"},
+	}
+
+	got := matchPattern(processes, "code", []string{"vscode-server", "sshd"})
+	want := PatternMatch{
+		Pattern: "code",
+		Exclude: []string{"vscode-server", "sshd"},
+		Processes: []MatchedProcess{
+			{PID: 500, Command: "/usr/share/code/code"},
+			{PID: 510, Command: "/usr/lib/electron/helper --type=renderer", Child: true},
+			{PID: 600, Command: "/home/dev/.vscode-server/node code-server", Excluded: true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchPattern() = %+v, want %+v", got, want)
+	}
+
+	if got := matchPattern(processes, "slack", nil); len(got.Processes) != 0 {
+		t.Errorf("matchPattern() = %+v, want no processes", got)
+	}
+}
+
+func TestPreviewKill(t *testing.T) {
+	testContent := `
+apps:
+  db:
+    type: docker
+    image: postgres:16
+  vault:
+    linux: "/bin/sleep"
+    darwin: "/bin/sleep"
+    windows: "sleep.exe"
+    kill: ["openx-preview-vault"]
+    protected: true`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	preview, err := PreviewKill("db")
+	if err != nil {
+		t.Fatalf("PreviewKill(db) unexpected error: %v", err)
+	}
+	if preview.Target != "docker container db" || len(preview.Patterns) != 0 {
+		t.Errorf("PreviewKill(db) = %+v, want the container", preview)
+	}
+
+	preview, err = PreviewKill("vault")
+	if err != nil {
+		t.Fatalf("PreviewKill(vault) unexpected error: %v", err)
+	}
+	if !preview.Protected || preview.Derived || len(preview.Patterns) != 1 || len(preview.Patterns[0].Processes) != 0 {
+		t.Errorf("PreviewKill(vault) = %+v, want one explicit pattern matching nothing", preview)
+	}
+
+	if _, err := PreviewKill("missing"); err == nil {
+		t.Error("PreviewKill() expected error for an unknown app")
+	}
+}
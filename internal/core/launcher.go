@@ -1,18 +1,149 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
+	"time"
 )
 
+// defaultRetryBackoff is the wait before the first launch retry
+const defaultRetryBackoff = time.Second
+
+// LaunchOptions controls how a single application is started
+type LaunchOptions struct {
+	Wait bool // block until the app exits and report its exit code
+	Log  bool // capture the app's stdout/stderr in its log file
+
+	// StartupCheck watches the app for this long after starting it and
+	// reports a LaunchFailure when it exits with an error or shows no window
+	StartupCheck time.Duration
+
+	// windowPatterns find the app's window for the startup check
+	windowPatterns []string
+
+	// Follow captures the app's output and prints it as it is written
+	// until openx is interrupted, leaving the app running
+	Follow bool
+
+	// NewInstance opens a new window/instance of single-instance apps
+	NewInstance bool
+
+	// Elevated launches with administrator privileges (sudo/osascript/RunAs)
+	Elevated bool
+
+	// Profile opens a browser profile, by name from the app's profiles or
+	// as the browser knows it (openx chrome@work)
+	Profile string
+
+	// appArgs come from the app's config and go before the user's
+	// arguments without path resolution
+	appArgs []string
+
+	// Terminal runs a CLI tool inside a terminal emulator window
+	Terminal bool
+
+	// terminalPath is the terminal used with Terminal, empty for the default
+	terminalPath string
+
+	// User launches the app as another user (sudo -u / runas /user:)
+	User string
+
+	// Priority starts the app at low, below_normal, normal, above_normal
+	// or high priority
+	Priority string
+
+	// Limits caps the app's memory and open files
+	Limits *Limits
+
+	// Mode ties the app to openx or detaches it; empty picks attached
+	// with Wait and detached otherwise
+	Mode LaunchMode
+
+	// report, when set, is filled in with what was started
+	report *LaunchReport
+}
+
+// LaunchMode controls whether a launched app outlives openx and its terminal
+type LaunchMode string
+
+const (
+	// LaunchModeDetached runs the app in its own session, so it survives
+	// the terminal closing
+	LaunchModeDetached LaunchMode = "detached"
+	// LaunchModeAttached keeps the app in openx's session; while openx
+	// waits for it, the app goes down with openx
+	LaunchModeAttached LaunchMode = "attached"
+)
+
+// launchMode returns the effective launch mode
+func (o LaunchOptions) launchMode() LaunchMode {
+	if o.Mode != "" {
+		return o.Mode
+	}
+	if o.Wait {
+		return LaunchModeAttached
+	}
+	return LaunchModeDetached
+}
+
+// parseLaunchMode validates a launch mode from the config or command line
+func parseLaunchMode(mode string) (LaunchMode, error) {
+	switch LaunchMode(mode) {
+	case "", LaunchModeDetached, LaunchModeAttached:
+		return LaunchMode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown launch mode %q (use detached or attached)", mode)
+	}
+}
+
+// AppExitError reports a non-zero exit of an app launched with Wait
+type AppExitError struct {
+	Alias string
+	Code  int
+}
+
+func (e *AppExitError) Error() string {
+	return fmt.Sprintf("%s exited with code %d", e.Alias, e.Code)
+}
+
 // LaunchApp launches an application with the given arguments
 func LaunchApp(alias string, args []string) error {
+	return LaunchAppWithOptions(alias, args, LaunchOptions{})
+}
+
+// LaunchAppWithOptions launches an application with the given arguments
+// and launch options, and records it among the recent launches
+func LaunchAppWithOptions(alias string, args []string, opts LaunchOptions) error {
+	err := launchApp(alias, args, opts)
+	if launched(err) {
+		recordRecent(alias, args)
+	}
+	return err
+}
+
+// launched reports whether a launch returning err started the app: an
+// app that ran and exited with an error still launched
+func launched(err error) bool {
+	var exitErr *AppExitError
+	return err == nil || errors.As(err, &exitErr)
+}
+
+// launchApp launches an app by name, alias@profile or path
+func launchApp(alias string, args []string, opts LaunchOptions) error {
 	// Check if it's a direct path to an application
 	if isDirectPath(alias) {
-		return launchDirectPath(alias, args)
+		err := launchDirectPathWithOptions(alias, args, opts)
+		if launched(err) {
+			recordHistory(HistoryLaunch, alias, "", args)
+		}
+		return err
 	}
 
 	config, err := loadConfig()
@@ -20,102 +151,435 @@ func LaunchApp(alias string, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	app, exists := config.Apps[alias]
-	if !exists {
-		// Check if it's an alias
-		if canonical, ok := config.Aliases[alias]; ok {
-			app, exists = config.Apps[canonical]
-			if !exists {
-				return fmt.Errorf("alias '%s' points to unknown app '%s'", alias, canonical)
-			}
-		} else {
-			return fmt.Errorf("unknown app: %s", alias)
+	// chrome@work opens chrome with its work profile
+	if _, _, err := lookupApp(config, alias); err != nil {
+		if base, profile := SplitAliasProfile(alias); profile != "" {
+			alias, opts.Profile = base, profile
+		}
+	}
+
+	name, app, err := lookupApp(config, alias)
+	if err != nil {
+		return err
+	}
+	debug("resolved", "alias", alias, "app", name, "profile", opts.Profile)
+
+	// Bring up anything the app needs before launching it
+	if len(app.Needs) > 0 {
+		if err := startDependencies(config, name); err != nil {
+			return err
 		}
 	}
 
+	return launchConfiguredApp(config, alias, name, app, args, opts)
+}
+
+// launchConfiguredApp launches an already resolved app from the config.
+// alias is the name the user typed, name the app's key in the config.
+func launchConfiguredApp(config *Config, alias, name string, app *App, args []string, opts LaunchOptions) (err error) {
+	typed := args
+	if opts.report != nil {
+		opts.report.App = name
+	}
+	defer func() {
+		if launched(err) {
+			recordHistory(HistoryLaunch, alias, name, typed)
+		}
+	}()
+
+	if isDockerApp(app) {
+		return launchDockerApp(alias, name, app, args, opts)
+	}
+
 	launchPath := app.GetLaunchPath()
 	if launchPath == "" {
-		return fmt.Errorf("no launch path configured for %s on %s", alias, runtime.GOOS)
+		return fmt.Errorf("%w for %s on %s", ErrNoPathForPlatform, alias, runtime.GOOS)
+	}
+
+	// AppImages are named without their version and found on disk
+	if strings.HasPrefix(launchPath, appImagePrefix) {
+		path, err := discoverAppImage(strings.TrimPrefix(launchPath, appImagePrefix))
+		if err != nil {
+			return err
+		}
+		debug("found AppImage", "path", path)
+		launchPath = path
+	}
+
+	// Deep links take their arguments inside the URL
+	if isURL(launchPath) {
+		target, err := expandURLTemplate(launchPath, args)
+		if err != nil {
+			return err
+		}
+		debug("expanded URL template", "template", launchPath, "args", args, "url", target)
+		launchPath, args = target, nil
+	}
+
+	// openx idea <project> opens a recent project by name
+	args = resolveJetBrainsProject(name, args)
+
+	// Per-app settings switch options on, CLI flags can't switch them off
+	opts.Log = opts.Log || app.Log || opts.Follow
+	opts.Elevated = opts.Elevated || app.Elevated
+	if opts.User == "" {
+		opts.User = app.User
+	}
+	if opts.Priority == "" {
+		opts.Priority = app.Priority
+	}
+	if opts.Limits == nil {
+		opts.Limits = app.Limits
+	}
+	opts.Terminal = opts.Terminal || app.Terminal
+	if opts.StartupCheck == 0 {
+		opts.StartupCheck = app.StartupCheck
+	}
+	if opts.StartupCheck > 0 && !opts.Terminal {
+		opts.windowPatterns = appKillPatterns(app)
+	}
+	if opts.Terminal {
+		opts.terminalPath = resolveTerminal(config)
+	}
+	if opts.Mode == "" {
+		mode, err := parseLaunchMode(app.Mode)
+		if err != nil {
+			return fmt.Errorf("%s: %w", alias, err)
+		}
+		opts.Mode = mode
 	}
 
+	if opts.NewInstance {
+		opts.appArgs = append(opts.appArgs, app.GetNewInstanceArgs(name)...)
+	}
+	if opts.Profile != "" {
+		profile, err := profileArgs(name, app, opts.Profile)
+		if err != nil {
+			return err
+		}
+		opts.appArgs = append(opts.appArgs, profile...)
+	}
+
+	// Window placement is best effort: the app is usable even if it fails
+	if hasWindowSettings(app) {
+		if err := prepareWindowPlacement(app); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", alias, err)
+		}
+	}
+
+	offset := logOffset(alias)
+	cmd, err := launchWithRetries(alias, launchPath, args, opts, app.Retries, app.RetryBackoff)
+	if err != nil {
+		return err
+	}
+
+	if hasWindowSettings(app) {
+		if err := applyWindowPlacement(app); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not place %s window: %v\n", alias, err)
+		}
+	}
+
+	if opts.Wait {
+		return waitForExit(alias, cmd)
+	}
+	if opts.Follow {
+		return followAppLog(alias, offset)
+	}
+	return nil
+}
+
+// runApp resolves the arguments, starts the application and, with
+// opts.Wait, blocks until it exits
+func runApp(alias, launchPath string, args []string, opts LaunchOptions) error {
+	cmd, err := launchProcess(alias, launchPath, args, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Wait {
+		return waitForExit(alias, cmd)
+	}
+	return nil
+}
+
+// launchWithRetries starts the application, retrying up to retries more
+// times with a doubling backoff when it fails to start
+func launchWithRetries(alias, launchPath string, args []string, opts LaunchOptions, retries int, backoff time.Duration) (*exec.Cmd, error) {
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		cmd, err := launchProcess(alias, launchPath, args, opts)
+		if err == nil {
+			return cmd, nil
+		}
+		if attempt > retries {
+			if retries > 0 {
+				return nil, fmt.Errorf("%w (gave up after %d attempts)", err, attempt)
+			}
+			return nil, err
+		}
+
+		info("%v; retrying in %s (attempt %d of %d)", err, backoff, attempt+1, retries+1)
+		sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// launchProcess resolves the arguments and starts the application
+func launchProcess(alias, launchPath string, args []string, opts LaunchOptions) (*exec.Cmd, error) {
 	// Resolve and prepare arguments
-	resolvedArgs := resolveTargets(args)
+	resolvedArgs := append(append([]string{}, opts.appArgs...), resolveTargets(args)...)
+
+	// The startup check reports the app's output when it fails
+	check := opts.StartupCheck > 0 && !opts.Wait
+	opts.Log = opts.Log || check
+	offset := logOffset(alias)
 
 	// Launch the application
-	if err := executeApp(launchPath, resolvedArgs); err != nil {
-		return fmt.Errorf("failed to launch %s: %w", alias, err)
+	cmd, err := startApp(alias, launchPath, resolvedArgs, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch %s: %w", alias, err)
+	}
+	if check {
+		if err := checkStartup(alias, cmd, opts, offset); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.Wait {
+		recordLaunch(alias, args, cmd)
+	}
+	if opts.report != nil {
+		opts.report.Path, opts.report.Args = launchPath, resolvedArgs
+		if cmd.Process != nil {
+			opts.report.PID = cmd.Process.Pid
+		}
 	}
 
-	fmt.Printf("Launched: %s\n", alias)
+	info("Launched: %s", alias)
 	if len(args) > 0 {
-		fmt.Printf("Arguments: %v\n", args)
+		info("Arguments: %v", args)
 	}
+	return cmd, nil
+}
+
+// waitForExit blocks until cmd exits, turning a non-zero exit into an
+// AppExitError. Interrupts and termination requests sent to openx while
+// it waits are passed on to the app.
+func waitForExit(alias string, cmd *exec.Cmd) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if err := cmd.Process.Signal(sig); err != nil {
+					// Windows can't deliver signals to other processes
+					cmd.Process.Kill()
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
 
+	err := cmd.Wait()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &AppExitError{Alias: alias, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed waiting for %s: %w", alias, err)
+	}
 	return nil
 }
 
 // executeApp handles the actual launching of the application
 func executeApp(launchPath string, args []string) error {
+	_, err := startApp(launchPath, launchPath, args, LaunchOptions{})
+	return err
+}
+
+// startApp builds the platform command for launchPath and starts it
+func startApp(alias, launchPath string, args []string, opts LaunchOptions) (*exec.Cmd, error) {
+	cmd := appCommand(launchPath, args, opts)
+	if opts.Elevated && opts.User != "" {
+		return nil, fmt.Errorf("elevated launches can't also run as user %s", opts.User)
+	}
+	if err := validatePriority(opts.Priority); err != nil {
+		return nil, err
+	}
+	if opts.Limits != nil {
+		limited, err := limitedCommand(runtime.GOOS, cmd, opts.Limits)
+		if err != nil {
+			return nil, err
+		}
+		cmd = limited
+	}
+	if opts.Priority != "" {
+		cmd = applyPriority(cmd, opts.Priority)
+	}
+	if opts.User != "" {
+		asUser, err := userCommand(cmd, opts.User)
+		if err != nil {
+			return nil, err
+		}
+		cmd = asUser
+	}
+	if opts.Elevated {
+		elevated, err := elevateCommand(cmd, opts.Wait)
+		if err != nil {
+			return nil, err
+		}
+		cmd = elevated
+	}
+
+	mode := opts.launchMode()
+	usesSudo := (opts.Elevated && runtime.GOOS == "linux") || (opts.User != "" && runtime.GOOS != "windows")
+	if usesSudo {
+		// sudo only trusts its cached credentials within the terminal's session
+		mode = LaunchModeAttached
+	}
+	applyLaunchMode(cmd, mode, opts.Wait)
+
+	if opts.Wait {
+		// Attach the terminal so editors and merge tools can interact with it
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+
+	if opts.Log {
+		logFile, err := openAppLog(alias)
+		if err != nil {
+			return nil, err
+		}
+		// The child keeps its own handle, ours can go once it has started
+		defer logFile.Close()
+		cmd.Stdout, cmd.Stderr = logFile, logFile
+		info("Logging output to: %s", logFile.Name())
+	}
+
+	debug("exec", "command", cmd.Args, "dir", cmd.Dir, "mode", mode, "log", opts.Log)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	if opts.Limits != nil {
+		if err := assignJobLimits(cmd, opts.Limits); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s started without its limits: %v\n", alias, err)
+		}
+	}
+	return cmd, nil
+}
+
+// appCommand builds the command that launches launchPath on this platform
+func appCommand(launchPath string, args []string, opts LaunchOptions) *exec.Cmd {
+	// CLI tools in terminal mode get a terminal window of their own
+	if opts.Terminal {
+		return terminalCommand(opts.terminalPath, launchPath, args)
+	}
+
+	// URL launch paths go to the handler registered for their scheme
+	if isURL(launchPath) {
+		return urlOpenerCommand(launchPath)
+	}
+
 	// Handle macOS .app bundles
 	if runtime.GOOS == "darwin" {
+		return macOSAppCommand(launchPath, args, opts)
+	}
+
+	// Desktop entries are started the way the desktop environment would
+	if id, ok := desktopEntryID(launchPath); ok && runtime.GOOS == "linux" {
+		return desktopEntryCommand(id, args)
+	}
 
-		return launchMacOSApp(launchPath, args)
+	// Snaps are started through snap run, which sets up their confinement
+	if strings.HasPrefix(launchPath, snapPrefix) && runtime.GOOS == "linux" {
+		name := strings.TrimPrefix(launchPath, snapPrefix)
+		return exec.Command("snap", append([]string{"run", name}, args...)...)
+	}
+
+	// Store apps are activated by the shell from their AppUserModelId
+	if aumid, ok := uwpAppID(launchPath); ok && runtime.GOOS == "windows" {
+		return uwpCommand(aumid)
+	}
+
+	// Start Menu shortcuts can only be launched through the shell
+	if runtime.GOOS == "windows" && strings.EqualFold(filepath.Ext(launchPath), ".lnk") {
+		cmdArgs := []string{"/c", "start"}
+		if opts.Wait {
+			cmdArgs = append(cmdArgs, "/wait")
+		}
+		cmdArgs = append(cmdArgs, "", launchPath)
+		return exec.Command("cmd", append(cmdArgs, args...)...)
 	}
 
 	// Handle regular executables
-	cmd := exec.Command(launchPath, args...)
-	return cmd.Start()
+	return exec.Command(launchPath, args...)
 }
 
 // launchMacOSApp launches a macOS .app bundle
 func launchMacOSApp(appPath string, args []string) error {
+	return macOSAppCommand(appPath, args, LaunchOptions{}).Start()
+}
+
+// macOSAppCommand runs the executable inside a .app bundle, falling back
+// to the 'open' command when it can't be found
+func macOSAppCommand(appPath string, args []string, opts LaunchOptions) *exec.Cmd {
 	// Find the actual executable inside the .app bundle
 	execPath, err := findAppExecutable(appPath)
 	if err != nil {
-		// Fallback to using 'open' command
-		return launchWithOpen(appPath, args)
+		return openCommand(appPath, args, opts)
 	}
 
-	// Launch the executable directly
-	cmd := exec.Command(execPath, args...)
-	return cmd.Start()
+	return exec.Command(execPath, args...)
 }
 
 // launchWithOpen uses macOS 'open' command as fallback
 func launchWithOpen(appPath string, args []string) error {
-	openArgs := []string{"-a", appPath}
-	if len(args) > 0 {
-		// openArgs = append(openArgs, "--args")
-		openArgs = append(openArgs, args...)
-	}
-	fmt.Printf("Using 'open' command: open %s\n", strings.Join(openArgs, " "))
+	cmd := openCommand(appPath, args, LaunchOptions{})
+	debug("exec", "command", cmd.Args)
 
-	cmd := exec.Command("open", openArgs...)
 	err := cmd.Start()
 	if err != nil {
-		fmt.Printf("Error with 'open -a %s': %v\n", appPath, err)
 		return fmt.Errorf("failed to launch %s with 'open' command: %w", appPath, err)
 	}
 
-	fmt.Printf("Successfully launched with 'open -a %s'\n", appPath)
+	info("Successfully launched with 'open -a %s'", appPath)
 	return nil
 }
 
+// openCommand builds an 'open -a' command. With Wait, open blocks until
+// the application quits; with NewInstance, it starts another instance.
+func openCommand(appPath string, args []string, opts LaunchOptions) *exec.Cmd {
+	openArgs := []string{"-a", appPath}
+	if opts.Wait {
+		openArgs = append([]string{"-W"}, openArgs...)
+	}
+	if opts.NewInstance {
+		openArgs = append([]string{"-n"}, openArgs...)
+	}
+	return exec.Command("open", append(openArgs, args...)...)
+}
+
 // launchMultipleApps launches multiple applications
 func launchMultipleApps(aliases []string) error {
-	errors := 0
-	for _, alias := range aliases {
-		if err := LaunchApp(alias, []string{}); err != nil {
-			fmt.Fprintf(os.Stderr, "Error launching %s: %v\n", alias, err)
-			errors++
-		}
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if errors > 0 {
-		return fmt.Errorf("%d apps failed to launch", errors)
+	plan, err := buildLaunchPlan(config, groupMembers(aliases...))
+	if err != nil {
+		return err
 	}
-
-	return nil
+	_, err = executeLaunchPlan(config, plan, defaultLaunchConcurrency)
+	return err
 }
 
 // isDirectPath checks if the given string is a direct path to an application
@@ -129,23 +593,15 @@ func isDirectPath(path string) bool {
 
 // launchDirectPath launches an application using a direct path
 func launchDirectPath(appPath string, args []string) error {
+	return launchDirectPathWithOptions(appPath, args, LaunchOptions{})
+}
+
+// launchDirectPathWithOptions launches a direct path with launch options
+func launchDirectPathWithOptions(appPath string, args []string, opts LaunchOptions) error {
 	// Check if the application exists
 	if !exists(appPath) {
-		return fmt.Errorf("application not found: %s", appPath)
+		return fmt.Errorf("%w: %s", ErrAppNotFound, appPath)
 	}
 
-	// Resolve and prepare arguments
-	resolvedArgs := resolveTargets(args)
-
-	// Launch the application
-	if err := executeApp(appPath, resolvedArgs); err != nil {
-		return fmt.Errorf("failed to launch %s: %w", appPath, err)
-	}
-
-	fmt.Printf("Launched: %s\n", appPath)
-	if len(args) > 0 {
-		fmt.Printf("Arguments: %v\n", args)
-	}
-
-	return nil
+	return runApp(appPath, appPath, args, opts)
 }
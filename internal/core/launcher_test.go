@@ -1,10 +1,14 @@
 package core
 
 import (
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLaunchApp(t *testing.T) {
@@ -455,3 +459,179 @@ func TestLaunchApp_DirectPath(t *testing.T) {
 		})
 	}
 }
+
+func TestLaunchAppWithOptions_Wait(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping true/false tests on Windows")
+	}
+
+	testContent := `
+apps:
+  succeed:
+    darwin: "/usr/bin/true"
+    linux: "/bin/true"
+  fail:
+    darwin: "/usr/bin/false"
+    linux: "/bin/false"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := LaunchAppWithOptions("succeed", []string{}, LaunchOptions{Wait: true}); err != nil {
+		t.Errorf("LaunchAppWithOptions(succeed) unexpected error: %v", err)
+	}
+
+	err := LaunchAppWithOptions("fail", []string{}, LaunchOptions{Wait: true})
+	var exitErr *AppExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("LaunchAppWithOptions(fail) error = %v, want AppExitError", err)
+	}
+	if exitErr.Code != 1 {
+		t.Errorf("LaunchAppWithOptions(fail) exit code = %d, want 1", exitErr.Code)
+	}
+}
+
+func TestLaunchAppWithOptions_NewInstance(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	testContent := `
+apps:
+  chrome:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  custom:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+    new_instance: ["--fresh"]
+
+aliases:
+  gc: chrome`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"gc", "--new-window https://example.com"},
+		{"custom", "--fresh https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			opts := LaunchOptions{Wait: true, Log: true, NewInstance: true}
+			if err := LaunchAppWithOptions(tt.alias, []string{"https://example.com"}, opts); err != nil {
+				t.Fatalf("LaunchAppWithOptions() unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(getLogPath(tt.alias))
+			if err != nil {
+				t.Fatalf("failed to read log: %v", err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("launched with %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchWithRetries(t *testing.T) {
+	var slept []time.Duration
+	oldSleep := sleep
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { sleep = oldSleep }()
+
+	var err error
+	stdout := captureStdout(t, func() {
+		_, err = launchWithRetries("missing", "/nonexistent/app", nil, LaunchOptions{}, 2, 100*time.Millisecond)
+	})
+	if err == nil {
+		t.Fatal("launchWithRetries() expected error for a missing app")
+	}
+	if !strings.Contains(stdout, "retrying in 100ms (attempt 2 of 3)") {
+		t.Errorf("launchWithRetries() printed %q, want the retries", stdout)
+	}
+	if !strings.Contains(err.Error(), "gave up after 3 attempts") {
+		t.Errorf("launchWithRetries() error = %v, want attempt count", err)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if len(slept) != len(want) || slept[0] != want[0] || slept[1] != want[1] {
+		t.Errorf("backoff = %v, want %v", slept, want)
+	}
+
+	// Without retries the error is returned as is
+	slept = nil
+	if _, err := launchWithRetries("missing", "/nonexistent/app", nil, LaunchOptions{}, 0, 0); err == nil || strings.Contains(err.Error(), "gave up") {
+		t.Errorf("launchWithRetries() error = %v, want plain launch error", err)
+	}
+	if len(slept) != 0 {
+		t.Errorf("launchWithRetries() slept %v without retries", slept)
+	}
+
+	// Quiet keeps the retries to itself
+	SetLogLevel(slog.LevelWarn)
+	defer SetLogLevel(slog.LevelInfo)
+	stdout = captureStdout(t, func() {
+		launchWithRetries("missing", "/nonexistent/app", nil, LaunchOptions{}, 1, 0)
+	})
+	if stdout != "" {
+		t.Errorf("launchWithRetries() printed %q when quiet, want nothing", stdout)
+	}
+}
+
+func TestLaunchMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LaunchOptions
+		expected LaunchMode
+	}{
+		{"default detaches", LaunchOptions{}, LaunchModeDetached},
+		{"wait attaches", LaunchOptions{Wait: true}, LaunchModeAttached},
+		{"explicit mode wins", LaunchOptions{Wait: true, Mode: LaunchModeDetached}, LaunchModeDetached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.launchMode(); got != tt.expected {
+				t.Errorf("launchMode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := parseLaunchMode("background"); err == nil {
+		t.Error("parseLaunchMode() expected error for unknown mode")
+	}
+}
+
+func TestStartApp_Detached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping sh tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// A detached app leads its own session: its session id is its pid
+	opts := LaunchOptions{Wait: true, Log: true, Mode: LaunchModeDetached}
+	cmd, err := startApp("session", "/bin/sh", []string{"-c", "ps -o sid= -p $$; echo $$"}, opts)
+	if err != nil {
+		t.Fatalf("startApp() unexpected error: %v", err)
+	}
+	if err := waitForExit("session", cmd); err != nil {
+		t.Fatalf("waitForExit() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(getLogPath("session"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] != fields[1] {
+		t.Errorf("session id and pid = %v, want the app to lead its own session", fields)
+	}
+}
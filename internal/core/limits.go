@@ -0,0 +1,100 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps size suffixes to their multiplier
+var byteUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes such as 2GB, 512M or 1048576
+func parseByteSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512MB or 2GB)", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// limitedCommand wraps cmd so the app starts with its resource limits.
+// Windows limits are applied after start, see assignJobLimits.
+func limitedCommand(goos string, cmd *exec.Cmd, limits *Limits) (*exec.Cmd, error) {
+	memory, err := parseByteSize(limits.Memory)
+	if err != nil {
+		return nil, err
+	}
+	if goos == "windows" {
+		if limits.Files > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: file descriptor limits are not supported on Windows\n")
+		}
+		return cmd, nil
+	}
+	if goos == "darwin" && memory > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: memory limits are not enforced on macOS\n")
+		memory = 0
+	}
+
+	_, err = exec.LookPath("systemd-run")
+	argv := unixLimitArgs(goos, cmd.Path, cmd.Args[1:], memory, limits.Files, err == nil)
+	if argv == nil {
+		return cmd, nil
+	}
+	return exec.Command(argv[0], argv[1:]...), nil
+}
+
+// unixLimitArgs builds the command line that runs path with the limits.
+// On Linux memory is capped by a systemd cgroup scope when possible; the
+// ulimit -v fallback limits virtual memory, which is much larger than what
+// apps like Electron actually use.
+func unixLimitArgs(goos, path string, args []string, memory int64, files int, systemdRun bool) []string {
+	var ulimits []string
+	if files > 0 {
+		ulimits = append(ulimits, fmt.Sprintf("ulimit -n %d", files))
+	}
+
+	var prefix []string
+	if memory > 0 {
+		if goos == "linux" && systemdRun {
+			prefix = []string{"systemd-run", "--user", "--scope", "--quiet", "-p", fmt.Sprintf("MemoryMax=%d", memory), "--"}
+		} else {
+			ulimits = append(ulimits, fmt.Sprintf("ulimit -v %d", memory/1024))
+		}
+	}
+
+	if len(prefix) == 0 && len(ulimits) == 0 {
+		return nil
+	}
+
+	command := append([]string{path}, args...)
+	if len(ulimits) > 0 {
+		// setrlimit happens in the shell right before it execs the app
+		script := strings.Join(ulimits, "; ") + `; exec "$0" "$@"`
+		command = append([]string{"sh", "-c", script}, command...)
+	}
+	return append(prefix, command...)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package core
+
+import "os/exec"
+
+// assignJobLimits is a no-op outside Windows, where limits are set before exec
+func assignJobLimits(cmd *exec.Cmd, limits *Limits) error {
+	return nil
+}
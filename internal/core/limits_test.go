@@ -0,0 +1,87 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		size      string
+		expected  int64
+		expectErr bool
+	}{
+		{"", 0, false},
+		{"1048576", 1 << 20, false},
+		{"512MB", 512 << 20, false},
+		{"2GB", 2 << 30, false},
+		{"1.5g", 3 << 29, false},
+		{"64 KB", 64 << 10, false},
+		{"lots", 0, true},
+		{"-1GB", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseByteSize(tt.size)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("parseByteSize(%q) error = %v, expectErr %v", tt.size, err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.size, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnixLimitArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		goos       string
+		memory     int64
+		files      int
+		systemdRun bool
+		want       string
+	}{
+		{"no limits", "linux", 0, 0, true, ""},
+		{"files", "darwin", 0, 1024, false, `sh|-c|ulimit -n 1024; exec "$0" "$@"|/usr/bin/app|--flag`},
+		{"memory via systemd", "linux", 2 << 30, 0, true, "systemd-run|--user|--scope|--quiet|-p|MemoryMax=2147483648|--|/usr/bin/app|--flag"},
+		{"memory via ulimit", "linux", 2 << 30, 0, false, `sh|-c|ulimit -v 2097152; exec "$0" "$@"|/usr/bin/app|--flag`},
+		{"both", "linux", 1 << 30, 256, true, `systemd-run|--user|--scope|--quiet|-p|MemoryMax=1073741824|--|sh|-c|ulimit -n 256; exec "$0" "$@"|/usr/bin/app|--flag`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argv := unixLimitArgs(tt.goos, "/usr/bin/app", []string{"--flag"}, tt.memory, tt.files, tt.systemdRun)
+			if got := strings.Join(argv, "|"); got != tt.want {
+				t.Errorf("unixLimitArgs() = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartApp_FileLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping ulimit tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	opts := LaunchOptions{Wait: true, Log: true, Limits: &Limits{Files: 64}}
+	cmd, err := startApp("limited", "/bin/sh", []string{"-c", "ulimit -n"}, opts)
+	if err != nil {
+		t.Fatalf("startApp() unexpected error: %v", err)
+	}
+	if err := waitForExit("limited", cmd); err != nil {
+		t.Fatalf("waitForExit() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(getLogPath("limited"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "64" {
+		t.Errorf("open file limit = %q, want 64", got)
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Kinds of names openx launches
+const (
+	ListKindApp     = "app"
+	ListKindAlias   = "alias"
+	ListKindGroup   = "group"
+	ListKindProject = "project" // opened with openx project, not listed
+)
+
+// ListEntry is a name openx launches: an app, an alias or a group
+type ListEntry struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+
+	// Target is the app's path on this OS, the app an alias points at, or
+	// a group's members
+	Target string `json:"target"`
+}
+
+// ListEntries returns every configured app, alias and group, each kind
+// sorted by name
+func ListEntries() ([]ListEntry, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return listEntries(config), nil
+}
+
+// RunList prints every configured app, alias and group
+func RunList(jsonOutput bool) error {
+	entries, err := ListEntries()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	fmt.Printf("%-20s %-6s %s\n", "NAME", "KIND", "TARGET")
+	for _, entry := range entries {
+		target := entry.Target
+		if target == "" && entry.Kind == ListKindApp {
+			target = ColorGray + "(no path for " + runtime.GOOS + ")" + ColorReset
+		}
+		fmt.Printf("%-20s %-6s %s\n", entry.Name, entry.Kind, target)
+	}
+	return nil
+}
+
+// listEntries lists the apps, then the aliases, then the groups of cfg
+func listEntries(cfg *Config) []ListEntry {
+	var entries []ListEntry
+	for _, name := range slices.Sorted(maps.Keys(cfg.Apps)) {
+		entries = append(entries, ListEntry{Name: name, Kind: ListKindApp, Target: cfg.Apps[name].GetLaunchPath()})
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Aliases)) {
+		entries = append(entries, ListEntry{Name: name, Kind: ListKindAlias, Target: cfg.Aliases[name]})
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Groups)) {
+		members := make([]string, len(cfg.Groups[name]))
+		for i, member := range cfg.Groups[name] {
+			members[i] = member.App
+		}
+		entries = append(entries, ListEntry{Name: name, Kind: ListKindGroup, Target: strings.Join(members, ", ")})
+	}
+	return entries
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestListEntries(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"slack":  {Paths: map[string]string{runtime.GOOS: "/opt/slack"}},
+			"editor": {Paths: map[string]string{"plan9": "acme"}},
+		},
+		Aliases: map[string]string{"chat": "slack"},
+		Groups:  map[string][]GroupMember{"work": {{App: "slack"}, {App: "editor"}}},
+	}
+
+	want := []ListEntry{
+		{Name: "editor", Kind: ListKindApp, Target: ""},
+		{Name: "slack", Kind: ListKindApp, Target: "/opt/slack"},
+		{Name: "chat", Kind: ListKindAlias, Target: "slack"},
+		{Name: "work", Kind: ListKindGroup, Target: "slack, editor"},
+	}
+	if got := listEntries(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("listEntries() = %+v, want %+v", got, want)
+	}
+}
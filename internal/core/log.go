@@ -0,0 +1,52 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// logLevel is how much openx reports. At the default, info level, it
+// prints what it did; debug adds the steps behind it, warn leaves only
+// warnings and errors.
+var logLevel = new(slog.LevelVar)
+
+// logger writes the debug records shown with -v to stderr
+var logger = newLogger(os.Stderr)
+
+// SetLogLevel sets how much openx reports: slog.LevelDebug shows how
+// names resolve and what runs, slog.LevelWarn silences the progress
+// lines such as "Launched: chrome"
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+// newLogger returns a logger writing records at logLevel to w as
+// key=value lines, without the time
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// Records are read as they're written, the time adds nothing
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// debug logs a step behind what openx does, shown with -v
+func debug(msg string, args ...any) {
+	logger.Debug(msg, args...)
+}
+
+// info prints a progress line to stdout unless openx was asked to be
+// quiet
+func info(format string, args ...any) {
+	if logger.Enabled(context.Background(), slog.LevelInfo) {
+		fmt.Printf(format+"\n", args...)
+	}
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogLevels(t *testing.T) {
+	var debugOutput bytes.Buffer
+	oldLogger := logger
+	logger = newLogger(&debugOutput)
+	defer func() {
+		logger = oldLogger
+		SetLogLevel(slog.LevelInfo)
+	}()
+
+	tests := []struct {
+		name      string
+		level     slog.Level
+		wantInfo  bool
+		wantDebug bool
+	}{
+		{"default", slog.LevelInfo, true, false},
+		{"verbose", slog.LevelDebug, true, true},
+		{"quiet", slog.LevelWarn, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetLogLevel(tt.level)
+			debugOutput.Reset()
+
+			stdout := captureStdout(t, func() {
+				info("Launched: %s", "chrome")
+				debug("resolved", "alias", "gc", "app", "chrome")
+			})
+
+			if got := stdout == "Launched: chrome\n"; got != tt.wantInfo {
+				t.Errorf("info printed %q, want printed = %v", stdout, tt.wantInfo)
+			}
+			if got := debugOutput.String(); (got != "") != tt.wantDebug {
+				t.Errorf("debug logged %q, want logged = %v", got, tt.wantDebug)
+			} else if tt.wantDebug && (strings.Contains(got, "time=") || !strings.Contains(got, "alias=gc app=chrome")) {
+				t.Errorf("debug logged %q, want the attributes without a time", got)
+			}
+		})
+	}
+}
+
+// captureStdout returns what f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	f()
+	os.Stdout = old
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLogSize is the size at which an app log is rotated
+	maxLogSize = 5 * 1024 * 1024
+	// maxLogBackups is how many rotated logs are kept per app
+	maxLogBackups = 3
+)
+
+// followPollInterval is how often a followed log is checked for new output
+var followPollInterval = 250 * time.Millisecond
+
+// getStateDir returns the directory for openx runtime state
+func getStateDir() string {
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "openx")
+	}
+
+	return filepath.Join(getHomeDir(), ".local", "state", "openx")
+}
+
+// getLogPath returns the log file used for an app's captured output
+func getLogPath(alias string) string {
+	name := strings.NewReplacer("/", "_", `\`, "_", ":", "_").Replace(alias)
+	return filepath.Join(getStateDir(), "logs", name+".log")
+}
+
+// openAppLog opens the app's log for appending, rotating it first when
+// it has grown past maxLogSize
+func openAppLog(alias string) (*os.File, error) {
+	logPath := getLogPath(alias)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	if info, err := os.Stat(logPath); err == nil && info.Size() >= maxLogSize {
+		rotateLog(logPath)
+	}
+
+	return os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+}
+
+// rotateLog shifts app.log to app.log.1, app.log.1 to app.log.2 and so on,
+// dropping the oldest backup
+func rotateLog(logPath string) {
+	os.Remove(fmt.Sprintf("%s.%d", logPath, maxLogBackups))
+	for i := maxLogBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", logPath, i), fmt.Sprintf("%s.%d", logPath, i+1))
+	}
+	os.Rename(logPath, logPath+".1")
+}
+
+// logOffset returns where the app's next output will start in its log:
+// the current size, or zero when the log is missing or about to be rotated
+func logOffset(alias string) int64 {
+	info, err := os.Stat(getLogPath(alias))
+	if err != nil || info.Size() >= maxLogSize {
+		return 0
+	}
+	return info.Size()
+}
+
+// followAppLog prints the app's log from offset on until openx is
+// interrupted. The app itself keeps running.
+func followAppLog(alias string, offset int64) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	stop := make(chan struct{})
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	info("Following %s output (Ctrl-C to stop, the app keeps running)", alias)
+	return followLog(getLogPath(alias), offset, os.Stdout, stop)
+}
+
+// followLog copies what is appended to the file at path from offset on
+// to out, like tail -f, until stop is closed
+func followLog(path string, offset int64, out io.Writer, stop <-chan struct{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	for {
+		// Start over when the log was truncated under us
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			offset = 0
+		}
+
+		n, err := io.Copy(out, io.NewSectionReader(f, offset, 1<<62))
+		offset += n
+		if err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(followPollInterval):
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetLogPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/state")
+
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"chrome", filepath.Join("/state", "openx", "logs", "chrome.log")},
+		{"/usr/bin/app", filepath.Join("/state", "openx", "logs", "_usr_bin_app.log")},
+	}
+
+	for _, tt := range tests {
+		if got := getLogPath(tt.alias); got != tt.want {
+			t.Errorf("getLogPath(%s) = %s, want %s", tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestOpenAppLog_Rotation(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	logPath := getLogPath("app")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath, make([]byte, maxLogSize), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logPath+".1", []byte("older"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := openAppLog("app")
+	if err != nil {
+		t.Fatalf("openAppLog() unexpected error: %v", err)
+	}
+	f.Close()
+
+	if info, err := os.Stat(logPath); err != nil || info.Size() != 0 {
+		t.Errorf("openAppLog() should start a fresh log after rotation")
+	}
+	if info, err := os.Stat(logPath + ".1"); err != nil || info.Size() != maxLogSize {
+		t.Errorf("rotated log not moved to .1")
+	}
+	if data, err := os.ReadFile(logPath + ".2"); err != nil || string(data) != "older" {
+		t.Errorf("previous backup not shifted to .2")
+	}
+}
+
+func TestLaunchAppWithOptions_Log(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	testContent := `
+apps:
+  echo:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  logged:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+    log: true`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := LaunchAppWithOptions("echo", []string{"https://flag.example"}, LaunchOptions{Wait: true, Log: true}); err != nil {
+		t.Fatalf("LaunchAppWithOptions() unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(getLogPath("echo"))
+	if err != nil || !strings.Contains(string(data), "https://flag.example") {
+		t.Errorf("log for echo = %q, %v; want captured output", data, err)
+	}
+
+	// Per-app config enables logging without the option
+	if err := LaunchAppWithOptions("logged", []string{"https://config.example"}, LaunchOptions{Wait: true}); err != nil {
+		t.Fatalf("LaunchAppWithOptions() unexpected error: %v", err)
+	}
+	data, err = os.ReadFile(getLogPath("logged"))
+	if err != nil || !strings.Contains(string(data), "https://config.example") {
+		t.Errorf("log for logged = %q, %v; want captured output", data, err)
+	}
+}
+
+func TestFollowLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logPath, []byte("previous run\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if offset := logOffset("missing"); offset != 0 {
+		t.Errorf("logOffset() of a missing log = %d, want 0", offset)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	var out strings.Builder
+	go func() {
+		done <- followLog(logPath, int64(len("previous run\n")), &out, stop)
+	}()
+
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("starting\n")
+	f.Close()
+
+	// Give the follower a poll to pick up the new line, then stop it
+	time.Sleep(2 * followPollInterval)
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("followLog() unexpected error: %v", err)
+	}
+
+	if got := out.String(); got != "starting\n" {
+		t.Errorf("followLog() printed %q, want only the new output", got)
+	}
+}
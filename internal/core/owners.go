@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// processOwners returns the user owning each of the given processes: the
+// uid on Unix, DOMAIN\user on Windows. Pids that exited meanwhile, and on
+// Windows processes whose owner openx may not read, are missing from the
+// result.
+func processOwners(pids []int) (map[int]string, error) {
+	if len(pids) == 0 {
+		return map[int]string{}, nil
+	}
+
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(pid)
+	}
+
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		// uids, since ps truncates long user names
+		output, err := exec.Command("ps", "-o", "pid=,uid=", "-p", strings.Join(ids, ",")).Output()
+		if err != nil && len(output) == 0 {
+			// ps exits non-zero when none of the pids exist
+			return map[int]string{}, nil
+		}
+		return parsePSOwners(string(output)), nil
+	case "windows":
+		script := fmt.Sprintf(`$ids = @(%s); Get-CimInstance Win32_Process | Where-Object { $ids -contains $_.ProcessId } | ForEach-Object { $o = Invoke-CimMethod -InputObject $_ -MethodName GetOwner; if ($o.User) { "$($_.ProcessId)`+"`t"+`$($o.Domain)\$($o.User)" } }`, strings.Join(ids, ","))
+		output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read process owners: %w", err)
+		}
+		return parsePSOwners(string(output)), nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// parsePSOwners reads `ps -o pid=,uid=` output, or the pid and tab
+// separated owner rows on Windows, where user names may have spaces
+func parsePSOwners(output string) map[int]string {
+	owners := map[int]string{}
+	for _, line := range strings.Split(output, "\n") {
+		pid, owner, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			pid, owner, ok = strings.Cut(pid, " ")
+		}
+		id, err := strconv.Atoi(pid)
+		owner = strings.TrimSpace(owner)
+		if !ok || err != nil || owner == "" {
+			continue
+		}
+		owners[id] = owner
+	}
+	return owners
+}
+
+// addOwners notes the users other than the current one owning a running
+// app's processes, which openx can't kill
+func addOwners(statuses []ProcessStatus) error {
+	current, err := user.Current()
+	if err != nil {
+		return fmt.Errorf("failed to look up the current user: %w", err)
+	}
+	self := current.Uid
+	if runtime.GOOS == "windows" {
+		self = current.Username
+	}
+
+	var pids []int
+	for _, status := range statuses {
+		pids = append(pids, status.PIDs...)
+	}
+	owners, err := processOwners(pids)
+	if err != nil {
+		return err
+	}
+
+	for i := range statuses {
+		statuses[i].OtherUsers = otherUsers(statuses[i].PIDs, owners, self)
+	}
+	return nil
+}
+
+// otherUsers lists, sorted by name, the owners of pids that aren't the
+// current user, with Unix uids turned into user names
+func otherUsers(pids []int, owners map[int]string, current string) []string {
+	seen := map[string]bool{}
+	var others []string
+	for _, pid := range pids {
+		owner, ok := owners[pid]
+		// Windows compares DOMAIN\user case-insensitively
+		if !ok || strings.EqualFold(owner, current) || seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		if runtime.GOOS != "windows" {
+			if u, err := user.LookupId(owner); err == nil {
+				owner = u.Username
+			}
+		}
+		others = append(others, owner)
+	}
+	sort.Strings(others)
+	return others
+}
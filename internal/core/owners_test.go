@@ -0,0 +1,55 @@
+package core
+
+import (
+	"os/user"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParsePSOwners(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[int]string
+	}{
+		{"ps", "  101   501\n  202     0\n", map[int]string{101: "501", 202: "0"}},
+		{"windows", "101\tHOST\\Jane Doe\r\n202\tNT AUTHORITY\\SYSTEM\r\n", map[int]string{101: "HOST\\Jane Doe", 202: "NT AUTHORITY\\SYSTEM"}},
+		{"skips malformed rows", "pid uid\n303\n\n404 77\n", map[int]string{404: "77"}},
+		{"empty", "", map[int]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePSOwners(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePSOwners() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOtherUsers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		owners := map[int]string{1: `HOST\jane`, 2: `NT AUTHORITY\SYSTEM`, 3: `HOST\Jane`}
+		got := otherUsers([]int{1, 2, 3, 4}, owners, `HOST\JANE`)
+		if want := []string{`NT AUTHORITY\SYSTEM`}; !reflect.DeepEqual(got, want) {
+			t.Errorf("otherUsers() = %v, want %v", got, want)
+		}
+		return
+	}
+
+	// Unknown uids stay numeric, known ones become names
+	owners := map[int]string{1: "501", 2: "4000000001", 3: "4000000001", 4: "0"}
+	root := "0"
+	if u, err := user.LookupId("0"); err == nil {
+		root = u.Username
+	}
+	got := otherUsers([]int{1, 2, 3, 4, 5}, owners, "501")
+	if want := []string{"4000000001", root}; !reflect.DeepEqual(got, want) {
+		t.Errorf("otherUsers() = %v, want %v", got, want)
+	}
+
+	if got := otherUsers([]int{1}, owners, "501"); got != nil {
+		t.Errorf("otherUsers(own pids) = %v, want none", got)
+	}
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// quarantineAttribute marks files downloaded from the internet on macOS;
+// Gatekeeper checks apps carrying it before their first launch
+const quarantineAttribute = "com.apple.quarantine"
+
+// appLaunchIssue finds what keeps an app doctor reports available from
+// launching: an executable without execute permission and, on macOS, a
+// quarantined app Gatekeeper blocks or asks about. It returns "" when
+// nothing is in the way.
+func appLaunchIssue(path string) string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	executable := appExecutable(path)
+	if executable == "" {
+		return ""
+	}
+	if info, err := os.Stat(executable); err == nil && !executableMode(info.Mode()) {
+		return executable + " is not executable (chmod +x)"
+	}
+
+	if runtime.GOOS != "darwin" || !strings.HasSuffix(strings.TrimSuffix(path, "/"), ".app") {
+		return ""
+	}
+	if exec.Command("xattr", "-p", quarantineAttribute, path).Run() != nil {
+		return ""
+	}
+	// spctl only assesses, it doesn't prompt; rejected apps are unsigned,
+	// unnotarized or damaged
+	if exec.Command("spctl", "--assess", "--type", "execute", path).Run() != nil {
+		return "blocked by Gatekeeper, unsigned or not notarized (allow it in System Settings > Privacy & Security, or xattr -d " + quarantineAttribute + ")"
+	}
+	return "quarantined, macOS asks to confirm the first launch"
+}
+
+// executableMode reports whether a regular file has any execute bit set
+func executableMode(mode os.FileMode) bool {
+	return !mode.IsRegular() || mode.Perm()&0111 != 0
+}
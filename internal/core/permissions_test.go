@@ -0,0 +1,35 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestAppLaunchIssue_ExecuteBit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("execute bits are a Unix check")
+	}
+
+	dir := t.TempDir()
+	runnable := filepath.Join(dir, "runnable")
+	plain := filepath.Join(dir, "plain")
+	if err := os.WriteFile(runnable, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(plain, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if issue := appLaunchIssue(runnable); issue != "" {
+		t.Errorf("appLaunchIssue(runnable) = %q, want none", issue)
+	}
+	if issue := appLaunchIssue(plain); !strings.Contains(issue, "not executable") {
+		t.Errorf("appLaunchIssue(plain) = %q, want not executable", issue)
+	}
+	if issue := appLaunchIssue("https://example.com"); issue != "" {
+		t.Errorf("appLaunchIssue(url) = %q, want none", issue)
+	}
+}
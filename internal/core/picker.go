@@ -0,0 +1,283 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Actions the picker takes on the chosen entry
+const (
+	PickLaunch = "launch"
+	PickKill   = "kill"
+)
+
+// pickerRows caps how many matches the picker shows at once
+const pickerRows = 10
+
+// ErrNotTerminal is returned by Pick when stdin or stdout isn't a terminal
+var ErrNotTerminal = errors.New("not a terminal")
+
+// PickerChoice is the app or group picked and what to do with it
+type PickerChoice struct {
+	Name   string
+	Action string
+}
+
+// pickerEntry is an app or group the picker offers, found by its name or
+// any of its aliases
+type pickerEntry struct {
+	ListEntry
+	keys []string
+}
+
+// picker is the state of the interactive picker: the query typed, the
+// entries matching it, best first, and the highlighted one
+type picker struct {
+	entries []pickerEntry
+	query   []rune
+	matches []pickerEntry
+	cursor  int
+	drawn   int // lines drawn last time, cleared before drawing again
+}
+
+// Pick shows a searchable list of the configured apps and groups on the
+// terminal. Typing narrows it, Enter launches the highlighted entry and
+// Ctrl-K kills it. It returns nil when the picker is left with Esc.
+func Pick() (*PickerChoice, error) {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return nil, ErrNotTerminal
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer restore()
+	return newPicker(config).run(os.Stdin, os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// newPicker offers the apps and groups in cfg
+func newPicker(cfg *Config) *picker {
+	p := &picker{}
+	for _, entry := range listEntries(cfg) {
+		if entry.Kind == ListKindAlias {
+			continue
+		}
+		keys := []string{entry.Name}
+		for alias, app := range cfg.Aliases {
+			if app == entry.Name {
+				keys = append(keys, alias)
+			}
+		}
+		p.entries = append(p.entries, pickerEntry{ListEntry: entry, keys: keys})
+	}
+	p.filter()
+	return p
+}
+
+// run reads keys from in and draws the picker on out until an entry is
+// chosen or the picker is left
+func (p *picker) run(in io.Reader, out io.Writer) (*PickerChoice, error) {
+	keys := bufio.NewReader(in)
+	for {
+		p.draw(out)
+		key, err := readKey(keys)
+		if err == io.EOF {
+			p.clear(out)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case keyEnter, keyKill:
+			if len(p.matches) == 0 {
+				continue
+			}
+			p.clear(out)
+			action := PickLaunch
+			if key == keyKill {
+				action = PickKill
+			}
+			return &PickerChoice{Name: p.matches[p.cursor].Name, Action: action}, nil
+		case keyQuit:
+			p.clear(out)
+			return nil, nil
+		case keyUp:
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case keyDown:
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+		case keyBackspace:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+		default:
+			if key > 0 && unicode.IsPrint(key) {
+				p.query = append(p.query, key)
+				p.filter()
+			}
+		}
+	}
+}
+
+// filter keeps the entries matching the query, best match first
+func (p *picker) filter() {
+	query := string(p.query)
+	scores := map[string]int{}
+	p.matches = p.matches[:0]
+	for _, entry := range p.entries {
+		best, found := 0, false
+		for _, key := range entry.keys {
+			if score, ok := fuzzyScore(query, key); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			scores[entry.Name] = best
+			p.matches = append(p.matches, entry)
+		}
+	}
+	// Without a query the entries keep the order openx list shows
+	if query != "" {
+		slices.SortStableFunc(p.matches, func(a, b pickerEntry) int {
+			return scores[b.Name] - scores[a.Name]
+		})
+	}
+	p.cursor = 0
+}
+
+// draw replaces what the picker drew last with the query and the matches
+// around the highlighted one
+func (p *picker) draw(out io.Writer) {
+	p.clear(out)
+	var lines []string
+	lines = append(lines, fmt.Sprintf("> %s  %s(Enter launch · Ctrl-K kill · Esc quit)%s", string(p.query), ColorGray, ColorReset))
+
+	first := max(0, p.cursor-pickerRows+1)
+	for i := first; i < len(p.matches) && i < first+pickerRows; i++ {
+		entry := p.matches[i]
+		line := fmt.Sprintf("%-20s %-6s %s", entry.Name, entry.Kind, entry.Target)
+		if i == p.cursor {
+			lines = append(lines, fmt.Sprintf("%s▸ %s%s", ColorGreen, line, ColorReset))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, ColorGray+"  no matches"+ColorReset)
+	}
+
+	// Raw mode doesn't turn \n into \r\n; leave the cursor after the query
+	fmt.Fprint(out, strings.Join(lines, "\r\n"))
+	if len(lines) > 1 {
+		fmt.Fprintf(out, "\033[%dA", len(lines)-1)
+	}
+	fmt.Fprintf(out, "\r\033[%dC", 2+len(p.query))
+	p.drawn = len(lines)
+}
+
+// clear erases what the picker drew
+func (p *picker) clear(out io.Writer) {
+	if p.drawn > 0 {
+		fmt.Fprint(out, "\r\033[J")
+		p.drawn = 0
+	}
+}
+
+// Keys the picker acts on besides the characters typed into the query
+const (
+	keyEnter rune = -1 - iota
+	keyKill
+	keyQuit
+	keyUp
+	keyDown
+	keyBackspace
+)
+
+// readKey reads one key press from a raw terminal
+func readKey(r *bufio.Reader) (rune, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	switch c {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 0x0b: // Ctrl-K
+		return keyKill, nil
+	case 0x03, 0x04: // Ctrl-C, Ctrl-D
+		return keyQuit, nil
+	case 0x10: // Ctrl-P
+		return keyUp, nil
+	case 0x0e: // Ctrl-N
+		return keyDown, nil
+	case 0x7f, 0x08:
+		return keyBackspace, nil
+	case 0x1b:
+		// Arrow keys arrive as ESC [ A in one read; a lone ESC quits
+		if r.Buffered() == 0 {
+			return keyQuit, nil
+		}
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil {
+			return 0, err
+		}
+		switch string(seq) {
+		case "[A", "OA":
+			return keyUp, nil
+		case "[B", "OB":
+			return keyDown, nil
+		}
+		return 0, nil
+	}
+	return c, nil
+}
+
+// fuzzyScore reports whether the letters of query appear in name in
+// order, and how well: letters in a row and at the start of a word score
+// higher, and so do shorter names
+func fuzzyScore(query, name string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	n := []rune(strings.ToLower(name))
+	score, matched, previous := 0, 0, -2
+	for i, c := range n {
+		if matched == len(q) || c != q[matched] {
+			continue
+		}
+		score++
+		if i == previous+1 {
+			score += 2
+		}
+		if i == 0 || strings.ContainsRune(" -_.", n[i-1]) {
+			score += 3
+		}
+		previous = i
+		matched++
+	}
+	if matched < len(q) {
+		return 0, false
+	}
+	return score*10 - len(n), true
+}
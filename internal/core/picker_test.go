@@ -0,0 +1,101 @@
+package core
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, name string
+		match       bool
+	}{
+		{"", "vscode", true},
+		{"vsc", "vscode", true},
+		{"vcd", "vscode", true},
+		{"VS", "vscode", true},
+		{"cv", "vscode", false},
+		{"chromex", "chrome", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.name); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.name, ok, tt.match)
+		}
+	}
+
+	// Letters in a row and at word starts rank a name higher
+	better, _ := fuzzyScore("post", "postman")
+	worse, _ := fuzzyScore("post", "photoshop-tools")
+	if better <= worse {
+		t.Errorf("fuzzyScore(post): postman = %d, want above photoshop-tools = %d", better, worse)
+	}
+}
+
+func TestPickerFilter(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"chrome":  {},
+			"postman": {},
+			"vscode":  {},
+		},
+		Aliases: map[string]string{"editor": "vscode"},
+		Groups:  map[string][]GroupMember{"work": {{App: "chrome"}}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"chrome", "postman", "vscode", "work"}},
+		{"o", []string{"work", "chrome", "vscode", "postman"}}, // shorter first
+		{"edit", []string{"vscode"}},                           // by its alias
+		{"wk", []string{"work"}},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		p := newPicker(cfg)
+		p.query = []rune(tt.query)
+		p.filter()
+		var got []string
+		for _, entry := range p.matches {
+			got = append(got, entry.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filter(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestPickerRun(t *testing.T) {
+	cfg := &Config{
+		Apps:   map[string]*App{"chrome": {}, "postman": {}, "vscode": {}},
+		Groups: map[string][]GroupMember{"work": {{App: "chrome"}}},
+	}
+
+	tests := []struct {
+		name string
+		keys string
+		want *PickerChoice
+	}{
+		{"enter launches the best match", "vs\r", &PickerChoice{Name: "vscode", Action: PickLaunch}},
+		{"ctrl-k kills", "post\x0b", &PickerChoice{Name: "postman", Action: PickKill}},
+		{"arrows move", "\x1b[B\x1b[B\x1b[A\r", &PickerChoice{Name: "postman", Action: PickLaunch}},
+		{"backspace widens the search", "vsx\x7f\x7f\x7fw\r", &PickerChoice{Name: "work", Action: PickLaunch}},
+		{"enter without a match does nothing", "xyz\r\x1b", nil},
+		{"esc quits", "\x1b", nil},
+		{"end of input quits", "ch", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newPicker(cfg).run(strings.NewReader(tt.keys), io.Discard)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("run(%q) = %+v, want %+v", tt.keys, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultLaunchConcurrency is how many group members launch at once
+const defaultLaunchConcurrency = 4
+
+// defaultStartTimeout bounds how long dependents wait for an app to come up
+const defaultStartTimeout = 60 * time.Second
+
+// dependencyPollInterval is how often a dependency is re-checked while waiting
+var dependencyPollInterval = 500 * time.Millisecond
+
+// sleep is swapped out in tests to avoid real delays
+var sleep = time.Sleep
+
+// launchStep is a single entry of an ordered launch plan
+type launchStep struct {
+	Alias    string        // name as written in the group or needs list
+	Delay    time.Duration // wait before launching this step
+	Needs    []string      // apps that must be up before this step launches
+	Implicit bool          // pulled in as a dependency rather than requested
+}
+
+// buildLaunchPlan orders group members so that every app comes after the
+// apps it needs. Dependencies missing from the group are added implicitly.
+func buildLaunchPlan(config *Config, members []GroupMember) ([]launchStep, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+
+	plan := []launchStep{}
+	state := map[string]int{}
+	index := map[string]int{} // canonical name -> position in plan
+
+	var visit func(alias string, delay time.Duration, implicit bool, path []string) error
+	visit = func(alias string, delay time.Duration, implicit bool, path []string) error {
+		name, app, err := lookupApp(config, alias)
+		if err != nil {
+			// Keep unknown apps in the plan so the launch reports the error
+			plan = append(plan, launchStep{Alias: alias, Delay: delay, Implicit: implicit})
+			return nil
+		}
+
+		switch state[name] {
+		case done:
+			if !implicit {
+				plan[index[name]].Implicit = false
+			}
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		state[name] = visiting
+		for _, need := range app.Needs {
+			if err := visit(need, 0, true, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+
+		index[name] = len(plan)
+		plan = append(plan, launchStep{Alias: alias, Delay: delay, Needs: app.Needs, Implicit: implicit})
+		return nil
+	}
+
+	for _, member := range members {
+		if err := visit(member.App, member.Delay, false, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// LaunchResult is the outcome of launching a single plan step
+type LaunchResult struct {
+	Alias   string `json:"alias"`
+	Skipped bool   `json:"skipped,omitempty"` // dependency that was already running
+	Err     error  `json:"-"`
+}
+
+// executeLaunchPlan launches the plan with up to concurrency apps starting
+// at once. Steps with a delay or dependencies act as barriers: they wait for
+// every earlier step before starting, so ordering guarantees are kept.
+func executeLaunchPlan(config *Config, plan []launchStep, concurrency int) ([]LaunchResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]LaunchResult, len(plan))
+	failed := map[string]bool{}
+
+	for _, stage := range planStages(plan) {
+		first := plan[stage[0]]
+		if first.Delay > 0 {
+			info("Waiting %s before launching %s", first.Delay, first.Alias)
+			sleep(first.Delay)
+		}
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, concurrency)
+		for _, i := range stage {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				results[i] = runLaunchStep(config, plan[i], failed)
+			}(i)
+		}
+		wg.Wait()
+
+		// Failures only become visible to later stages, which read the map
+		// after this stage's workers are done
+		for _, i := range stage {
+			if results[i].Err != nil {
+				if name, _, err := lookupApp(config, plan[i].Alias); err == nil {
+					failed[name] = true
+				}
+				failed[plan[i].Alias] = true
+			}
+		}
+	}
+
+	errors := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error launching %s: %v\n", result.Alias, result.Err)
+			errors++
+		}
+	}
+
+	if errors > 0 {
+		return results, fmt.Errorf("%d apps failed to launch", errors)
+	}
+
+	return results, nil
+}
+
+// planStages splits a plan into groups of step indices that may launch
+// concurrently. A step with a delay or dependencies starts a new stage.
+func planStages(plan []launchStep) [][]int {
+	var stages [][]int
+	for i, step := range plan {
+		if len(stages) == 0 || step.Delay > 0 || len(step.Needs) > 0 {
+			stages = append(stages, []int{})
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], i)
+	}
+	return stages
+}
+
+// runLaunchStep waits for a step's dependencies and launches it
+func runLaunchStep(config *Config, step launchStep, failed map[string]bool) LaunchResult {
+	result := LaunchResult{Alias: step.Alias}
+
+	if err := waitForNeeds(config, step.Needs, failed); err != nil {
+		result.Err = err
+		return result
+	}
+
+	name, app, err := lookupApp(config, step.Alias)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if step.Implicit && isAppUp(name, app) {
+		info("Already running: %s", name)
+		result.Skipped = true
+		return result
+	}
+
+	result.Err = launchConfiguredApp(config, step.Alias, name, app, []string{}, LaunchOptions{})
+	return result
+}
+
+// startDependencies launches everything the named app needs and waits for it
+func startDependencies(config *Config, name string) error {
+	plan, err := buildLaunchPlan(config, groupMembers(name))
+	if err != nil {
+		return err
+	}
+
+	// The last step is the app itself, launched by the caller with its args
+	deps := plan[:len(plan)-1]
+	for i := range deps {
+		deps[i].Implicit = true
+	}
+
+	if _, err := executeLaunchPlan(config, deps, 1); err != nil {
+		return fmt.Errorf("failed to start dependencies of %s: %w", name, err)
+	}
+	return waitForNeeds(config, config.Apps[name].Needs, nil)
+}
+
+// waitForNeeds blocks until every needed app is up or its start timeout expires
+func waitForNeeds(config *Config, needs []string, failed map[string]bool) error {
+	for _, need := range needs {
+		name, app, err := lookupApp(config, need)
+		if err != nil {
+			return fmt.Errorf("dependency %s: %w", need, err)
+		}
+		if failed[name] {
+			return fmt.Errorf("dependency %s failed to launch", need)
+		}
+		if err := waitUntilUp(name, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitUntilUp polls an app until it is up or its start timeout expires
+func waitUntilUp(name string, app *App) error {
+	timeout := app.StartTimeout
+	if timeout <= 0 {
+		timeout = defaultStartTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	for !isAppUp(name, app) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("dependency %s did not come up within %s", name, timeout)
+		}
+		sleep(dependencyPollInterval)
+	}
+	return nil
+}
+
+// isAppUp reports whether an app is ready: its health check passes, or
+// without one, one of its processes is running
+func isAppUp(name string, app *App) bool {
+	if app.Health != "" {
+		return checkHealth(app.Health)
+	}
+	return isAppRunning(name, app)
+}
+
+// isAppRunning checks if any process matching the app's kill patterns is
+// running, or for Docker apps whether the container is
+func isAppRunning(name string, app *App) bool {
+	if isDockerApp(app) {
+		return dockerContainerState(dockerContainerName(name, app)) == "running"
+	}
+	if aumid, ok := uwpAppID(app.GetLaunchPath()); ok && len(app.Kill) == 0 {
+		return isUWPAppRunning(aumid)
+	}
+	for _, pattern := range appKillPatterns(app) {
+		if isProcessRunning(pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBuildLaunchPlan(t *testing.T) {
+	testContent := `
+apps:
+  docker:
+    linux: "docker-desktop"
+  postgres:
+    linux: "postgres"
+    needs: [docker]
+  dbclient:
+    linux: "dbclient"
+    needs: [pg, docker]
+  browser:
+    linux: "browser"
+
+aliases:
+  pg: postgres`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	plan, err := buildLaunchPlan(config, groupMembers("browser", "dbclient", "docker"))
+	if err != nil {
+		t.Fatalf("buildLaunchPlan() unexpected error: %v", err)
+	}
+
+	var order []string
+	for _, step := range plan {
+		order = append(order, step.Alias)
+	}
+	if got := strings.Join(order, ","); got != "browser,docker,pg,dbclient" {
+		t.Errorf("buildLaunchPlan() order = %s, want browser,docker,pg,dbclient", got)
+	}
+
+	// docker is listed in the group, so it is no longer an implicit dependency
+	if plan[1].Implicit {
+		t.Error("docker should be explicit once listed in the group")
+	}
+	if !plan[2].Implicit {
+		t.Error("pg should be an implicit dependency")
+	}
+}
+
+func TestBuildLaunchPlan_Cycle(t *testing.T) {
+	testContent := `
+apps:
+  a:
+    linux: "a"
+    needs: [b]
+  b:
+    linux: "b"
+    needs: [a]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	_, err = buildLaunchPlan(config, groupMembers("a"))
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("buildLaunchPlan() error = %v, want dependency cycle", err)
+	}
+}
+
+func TestExecuteLaunchPlan_Delays(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	testContent := `
+apps:
+  echo:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  other:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	var slept []time.Duration
+	oldSleep := sleep
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { sleep = oldSleep }()
+
+	plan, err := buildLaunchPlan(config, []GroupMember{
+		{App: "echo"},
+		{App: "other", Delay: 3 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("buildLaunchPlan() unexpected error: %v", err)
+	}
+
+	if _, err := executeLaunchPlan(config, plan, 1); err != nil {
+		t.Fatalf("executeLaunchPlan() unexpected error: %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 3*time.Second {
+		t.Errorf("executeLaunchPlan() slept %v, want [3s]", slept)
+	}
+}
+
+func TestLaunchApp_WaitsForNeeds(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	var ready atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testContent := `
+apps:
+  service:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+    health: "` + server.URL + `"
+    start_timeout: 5s
+  client:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+    needs: [service]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	polls := 0
+	oldSleep := sleep
+	sleep = func(time.Duration) {
+		polls++
+		ready.Store(polls >= 2)
+	}
+	defer func() { sleep = oldSleep }()
+
+	if err := LaunchApp("client", []string{}); err != nil {
+		t.Fatalf("LaunchApp() unexpected error: %v", err)
+	}
+	if polls < 2 {
+		t.Errorf("LaunchApp() polled %d times, want at least 2", polls)
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		target string
+		want   bool
+	}{
+		{"http ok", server.URL, true},
+		{"http error status", server.URL + "/down", false},
+		{"tcp open", "tcp://" + strings.TrimPrefix(server.URL, "http://"), true},
+		{"tcp closed", "tcp://127.0.0.1:1", false},
+		{"unsupported scheme", "ftp://example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkHealth(tt.target); got != tt.want {
+				t.Errorf("checkHealth(%s) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanStages(t *testing.T) {
+	plan := []launchStep{
+		{Alias: "a"},
+		{Alias: "b"},
+		{Alias: "c", Delay: time.Second},
+		{Alias: "d"},
+		{Alias: "e", Needs: []string{"c"}},
+	}
+
+	stages := planStages(plan)
+
+	want := [][]int{{0, 1}, {2, 3}, {4}}
+	if len(stages) != len(want) {
+		t.Fatalf("planStages() = %v, want %v", stages, want)
+	}
+	for i := range want {
+		if len(stages[i]) != len(want[i]) {
+			t.Errorf("stage %d = %v, want %v", i, stages[i], want[i])
+			continue
+		}
+		for j := range want[i] {
+			if stages[i][j] != want[i][j] {
+				t.Errorf("stage %d = %v, want %v", i, stages[i], want[i])
+			}
+		}
+	}
+}
+
+func TestLaunchGroupWithConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+
+	testContent := `
+apps:
+  one:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  two:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  three:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+
+groups:
+  work: [one, two, missing, three]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	results, err := LaunchGroupWithConcurrency("work", 2)
+	if err == nil {
+		t.Error("LaunchGroupWithConcurrency() expected error for missing member")
+	}
+	if len(results) != 4 {
+		t.Fatalf("LaunchGroupWithConcurrency() returned %d results, want 4", len(results))
+	}
+
+	for _, result := range results {
+		failed := result.Err != nil
+		if failed != (result.Alias == "missing") {
+			t.Errorf("result for %s: err = %v", result.Alias, result.Err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// Priority levels accepted by the per-app priority option
+const (
+	priorityLow         = "low"
+	priorityBelowNormal = "below_normal"
+	priorityNormal      = "normal"
+	priorityAboveNormal = "above_normal"
+	priorityHigh        = "high"
+)
+
+// priorityNice maps priority levels to Unix nice values. Raising priority
+// above normal needs root; nice warns and runs the app anyway.
+var priorityNice = map[string]int{
+	priorityLow:         19,
+	priorityBelowNormal: 10,
+	priorityNormal:      0,
+	priorityAboveNormal: -5,
+	priorityHigh:        -10,
+}
+
+// priorityClass maps priority levels to Windows process priority classes
+var priorityClass = map[string]uint32{
+	priorityLow:         0x00000040, // IDLE_PRIORITY_CLASS
+	priorityBelowNormal: 0x00004000, // BELOW_NORMAL_PRIORITY_CLASS
+	priorityNormal:      0x00000020, // NORMAL_PRIORITY_CLASS
+	priorityAboveNormal: 0x00008000, // ABOVE_NORMAL_PRIORITY_CLASS
+	priorityHigh:        0x00000080, // HIGH_PRIORITY_CLASS
+}
+
+// validatePriority checks the app's priority option
+func validatePriority(priority string) error {
+	if _, ok := priorityNice[priority]; ok || priority == "" {
+		return nil
+	}
+	return fmt.Errorf("unknown priority %q (use low, below_normal, normal, above_normal or high)", priority)
+}
+
+// niceCommand wraps cmd in nice so the app starts, and forks, at the
+// priority's nice value
+func niceCommand(cmd *exec.Cmd, priority string) *exec.Cmd {
+	niceArgs := []string{"-n", strconv.Itoa(priorityNice[priority]), cmd.Path}
+	return exec.Command("nice", append(niceArgs, cmd.Args[1:]...)...)
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidatePriority(t *testing.T) {
+	for _, priority := range []string{"", "low", "below_normal", "normal", "above_normal", "high"} {
+		if err := validatePriority(priority); err != nil {
+			t.Errorf("validatePriority(%q) unexpected error: %v", priority, err)
+		}
+	}
+	if err := validatePriority("realtime"); err == nil {
+		t.Error("validatePriority(realtime) expected error")
+	}
+}
+
+func TestNiceCommand(t *testing.T) {
+	cmd := niceCommand(exec.Command("/usr/bin/indexer", "--watch", "~/src"), "low")
+	want := "nice -n 19 /usr/bin/indexer --watch ~/src"
+	if got := strings.Join(cmd.Args, " "); got != want {
+		t.Errorf("niceCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestStartApp_Priority(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping nice tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	out, err := exec.Command("nice").Output()
+	if err != nil {
+		t.Skipf("nice not available: %v", err)
+	}
+	base, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+	want := strconv.Itoa(min(base+10, 19))
+
+	opts := LaunchOptions{Wait: true, Log: true, Priority: "below_normal"}
+	cmd, err := startApp("niceness", "/bin/sh", []string{"-c", "nice"}, opts)
+	if err != nil {
+		t.Fatalf("startApp() unexpected error: %v", err)
+	}
+	if err := waitForExit("niceness", cmd); err != nil {
+		t.Fatalf("waitForExit() unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(getLogPath("niceness"))
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != want {
+		t.Errorf("niceness = %q, want %s", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package core
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// applyLaunchMode sets up the child's session for the launch mode
+func applyLaunchMode(cmd *exec.Cmd, mode LaunchMode, wait bool) {
+	switch mode {
+	case LaunchModeDetached:
+		// A new session has no controlling terminal, so closing the
+		// terminal doesn't send the app SIGHUP
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	case LaunchModeAttached:
+		if wait {
+			// Take the app down if openx itself is killed while waiting
+			cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+		}
+	}
+}
+
+// applyPriority starts the app at the given priority level
+func applyPriority(cmd *exec.Cmd, priority string) *exec.Cmd {
+	return niceCommand(cmd, priority)
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !windows
+
+package core
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// applyLaunchMode sets up the child's session for the launch mode.
+// Attached apps stay in openx's session and get its signals forwarded.
+func applyLaunchMode(cmd *exec.Cmd, mode LaunchMode, wait bool) {
+	if mode == LaunchModeDetached {
+		// A new session has no controlling terminal, so closing the
+		// terminal doesn't send the app SIGHUP
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	}
+}
+
+// applyPriority starts the app at the given priority level
+func applyPriority(cmd *exec.Cmd, priority string) *exec.Cmd {
+	return niceCommand(cmd, priority)
+}
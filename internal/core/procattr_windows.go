@@ -0,0 +1,36 @@
+//go:build windows
+
+package core
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS creation flag, which the
+// syscall package doesn't define
+const detachedProcess = 0x00000008
+
+// applyLaunchMode sets up the child's console for the launch mode.
+// Attached apps share openx's console and get its signals forwarded.
+func applyLaunchMode(cmd *exec.Cmd, mode LaunchMode, wait bool) {
+	if mode == LaunchModeDetached {
+		// No console and its own process group, so closing the terminal
+		// or pressing Ctrl+C there leaves the app running
+		sysProcAttr(cmd).CreationFlags |= detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP
+	}
+}
+
+// applyPriority starts the app in the priority level's priority class
+func applyPriority(cmd *exec.Cmd, priority string) *exec.Cmd {
+	sysProcAttr(cmd).CreationFlags |= priorityClass[priority]
+	return cmd
+}
+
+// sysProcAttr returns cmd's SysProcAttr, creating it if needed
+func sysProcAttr(cmd *exec.Cmd) *syscall.SysProcAttr {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	return cmd.SysProcAttr
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// processInfo is a running process as seen by the process table
+type processInfo struct {
+	PID     int
+	PPID    int
+	Name    string // executable name, e.g. chrome.exe
+	Command string // full command line
+}
+
+// listProcesses returns the running processes
+func listProcesses() ([]processInfo, error) {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		output, err := exec.Command("ps", "-axo", "pid=,ppid=,command=").Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+		// Executable names may hold spaces too, so they come on their own
+		names, err := exec.Command("ps", "-axo", "pid=,comm=").Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+		return parsePSOutput(string(output), string(names)), nil
+	case "windows":
+		script := `Get-CimInstance Win32_Process | ForEach-Object { "$($_.ProcessId)` + "`t" + `$($_.ParentProcessId)` + "`t" + `$($_.Name)` + "`t" + `$($_.CommandLine)" }`
+		output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+		return parseWindowsProcesses(string(output)), nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// linuxCommLen is how much of an executable name Linux keeps as comm
+const linuxCommLen = 15
+
+// parsePSOutput reads `ps -axo pid=,ppid=,command=` output, naming each
+// process from `ps -axo pid=,comm=` output in names
+func parsePSOutput(output, names string) []processInfo {
+	comms := parsePSNames(names)
+	var processes []processInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		// The command keeps its own spacing after the two numeric columns
+		command := strings.TrimSpace(line)
+		for range 2 {
+			command = strings.TrimSpace(command[strings.IndexAny(command, " \t"):])
+		}
+		// Exited children that were never reaped are not running
+		if strings.HasSuffix(command, "<defunct>") {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, PPID: ppid, Name: psName(comms[pid], fields[2]), Command: command})
+	}
+	return processes
+}
+
+// parsePSNames reads `ps -axo pid=,comm=` output into executables by pid,
+// keeping the spaces in their names
+func parsePSNames(output string) map[int]string {
+	comms := map[int]string{}
+	for _, line := range strings.Split(output, "\n") {
+		pidField, comm, ok := strings.Cut(strings.TrimSpace(line), " ")
+		pid, err := strconv.Atoi(pidField)
+		if !ok || err != nil {
+			continue
+		}
+		comms[pid] = strings.TrimSpace(comm)
+	}
+	return comms
+}
+
+// psName returns a process's executable name from its comm, the full path
+// on macOS, falling back to the command's first word when ps didn't list
+// it. Linux cuts comm short, the first word then has the whole name.
+func psName(comm, arg0 string) string {
+	arg0 = filepath.Base(arg0)
+	if comm == "" {
+		return arg0
+	}
+	name := comm
+	if filepath.IsAbs(comm) {
+		name = filepath.Base(comm)
+	}
+	if len(comm) == linuxCommLen && strings.HasPrefix(arg0, name) {
+		return arg0
+	}
+	return name
+}
+
+// parseWindowsProcesses reads tab-separated pid, parent pid, name and
+// command line rows
+func parseWindowsProcesses(output string) []processInfo {
+	var processes []processInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimRight(line, "\r"), "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		processes = append(processes, processInfo{PID: pid, PPID: ppid, Name: fields[2], Command: fields[3]})
+	}
+	return processes
+}
+
+// killMatcher is a kill pattern with its match mode resolved
+type killMatcher struct {
+	prefix string         // killExactPrefix, killWordPrefix, killRegexPrefix or "" for a substring
+	value  string         // the pattern without its prefix
+	re     *regexp.Regexp // word and regex patterns
+}
+
+// parseKillPattern resolves a kill pattern's match mode from its prefix
+func parseKillPattern(pattern string) (killMatcher, error) {
+	for _, prefix := range []string{killExactPrefix, killWordPrefix, killRegexPrefix} {
+		value, ok := strings.CutPrefix(pattern, prefix)
+		if !ok {
+			continue
+		}
+		if value == "" {
+			return killMatcher{}, fmt.Errorf("empty kill pattern %q", pattern)
+		}
+
+		m := killMatcher{prefix: prefix, value: value}
+		switch prefix {
+		case killWordPrefix:
+			m.re = regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(value) + `(\W|$)`)
+		case killRegexPrefix:
+			re, err := regexp.Compile("(?i)" + value)
+			if err != nil {
+				return killMatcher{}, fmt.Errorf("invalid kill pattern %q: %w", pattern, err)
+			}
+			m.re = re
+		}
+		return m, nil
+	}
+	return killMatcher{value: pattern}, nil
+}
+
+// processMatches reports whether a kill pattern selects the process. Plain
+// patterns match the way the platform's kill does: by command line
+// (pkill -i -f) on Unix and by image name (taskkill /IM) on Windows. Exact
+// patterns compare the process name, word and regex patterns the command
+// line, on every platform.
+func processMatches(p processInfo, m killMatcher) bool {
+	command := p.Command
+	if command == "" {
+		command = p.Name
+	}
+
+	switch m.prefix {
+	case killExactPrefix:
+		return strings.EqualFold(p.Name, m.value) || strings.EqualFold(p.Name, m.value+".exe")
+	case killWordPrefix, killRegexPrefix:
+		return m.re.MatchString(command)
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(p.Name, m.value) || strings.EqualFold(p.Name, m.value+".exe")
+	}
+	return strings.Contains(strings.ToLower(command), strings.ToLower(m.value))
+}
+
+// pkillArgs returns the pgrep and pkill arguments that select a kill
+// pattern's processes when there is no process table to match against
+func pkillArgs(pattern string) []string {
+	m, err := parseKillPattern(pattern)
+	if err != nil {
+		return []string{"-i", "-f", pattern}
+	}
+
+	switch m.prefix {
+	case killExactPrefix:
+		return []string{"-i", "-x", m.value}
+	case killWordPrefix:
+		return []string{"-i", "-f", "(^|[^[:alnum:]_])" + regexp.QuoteMeta(m.value) + "([^[:alnum:]_]|$)"}
+	}
+	return []string{"-i", "-f", m.value}
+}
+
+// matchingProcesses returns the processes a kill pattern selects, leaving
+// out openx and the processes that started it
+func matchingProcesses(processes []processInfo, pattern string) []processInfo {
+	m, err := parseKillPattern(pattern)
+	if err != nil {
+		return nil
+	}
+	own := ancestors(processes, os.Getpid())
+
+	var matched []processInfo
+	for _, p := range processes {
+		if !own[p.PID] && processMatches(p, m) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// ancestors returns pid and the pids of its parents
+func ancestors(processes []processInfo, pid int) map[int]bool {
+	parents := map[int]int{}
+	for _, p := range processes {
+		parents[p.PID] = p.PPID
+	}
+
+	seen := map[int]bool{}
+	for pid > 0 && !seen[pid] {
+		seen[pid] = true
+		pid = parents[pid]
+	}
+	return seen
+}
+
+// confirm asks a yes/no question on the terminal, answering no when
+// there is no terminal to ask on; tests replace it. The question goes to
+// stderr, keeping stdout for output such as --json.
+var confirm = func(question string) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintf(os.Stderr, "%s no (not a terminal, use --yes to confirm)\n", question)
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
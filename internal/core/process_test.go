@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParsePSOutput(t *testing.T) {
+	output := `    1     0 /sbin/init splash
+  812     1 /usr/lib/slack/slack --enable-crashpad
+  830   812 /usr/lib/slack/slack --type=renderer  --lang=en
+  901   812 [slack] <defunct>
+  950     1 /Applications/Google Chrome.app/Contents/MacOS/Google Chrome --restore-last-session
+  960     1 /usr/libexec/gnome-terminal-server
+  970     1 /usr/bin/python3 /usr/bin/some-daemon
+ junk line
+`
+	names := `    1 init
+  812 slack
+  830 slack
+  950 /Applications/Google Chrome.app/Contents/MacOS/Google Chrome
+  960 gnome-terminal-
+  970 some-daemon
+`
+
+	got := parsePSOutput(output, names)
+	want := []processInfo{
+		{PID: 1, PPID: 0, Name: "init", Command: "/sbin/init splash"},
+		{PID: 812, PPID: 1, Name: "slack", Command: "/usr/lib/slack/slack --enable-crashpad"},
+		{PID: 830, PPID: 812, Name: "slack", Command: "/usr/lib/slack/slack --type=renderer  --lang=en"},
+		{PID: 950, PPID: 1, Name: "Google Chrome", Command: "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome --restore-last-session"},
+		// Linux's comm is cut to 15 characters
+		{PID: 960, PPID: 1, Name: "gnome-terminal-server", Command: "/usr/libexec/gnome-terminal-server"},
+		{PID: 970, PPID: 1, Name: "some-daemon", Command: "/usr/bin/python3 /usr/bin/some-daemon"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parsePSOutput() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parsePSOutput()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseWindowsProcesses(t *testing.T) {
+	output := "4\t0\tSystem\t\r\n5120\t4400\tchrome.exe\t\"C:\\Program Files\\Google\\Chrome\\chrome.exe\" --type=gpu\r\n"
+
+	got := parseWindowsProcesses(output)
+	if len(got) != 2 {
+		t.Fatalf("parseWindowsProcesses() = %+v, want 2 processes", got)
+	}
+	if got[1].PID != 5120 || got[1].PPID != 4400 || got[1].Name != "chrome.exe" || got[1].Command != `"C:\Program Files\Google\Chrome\chrome.exe" --type=gpu` {
+		t.Errorf("parseWindowsProcesses()[1] = %+v", got[1])
+	}
+}
+
+func TestMatchingProcesses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows matches by image name")
+	}
+
+	// openx's own command line may contain the pattern, it must not match
+	self := os.Getpid()
+	processes := []processInfo{
+		{PID: 1, PPID: 0, Command: "/sbin/init"},
+		{PID: 50, PPID: 1, Command: "bash -c openx --kill node"},
+		{PID: self, PPID: 50, Command: "openx --kill node"},
+		{PID: 60, PPID: 1, Command: "node server.js"},
+		{PID: 61, PPID: 1, Command: "/usr/bin/NODE build.js"},
+	}
+
+	matched := matchingProcesses(processes, "node")
+	if len(matched) != 2 || matched[0].PID != 60 || matched[1].PID != 61 {
+		t.Errorf("matchingProcesses() = %+v, want pids 60 and 61", matched)
+	}
+}
+
+func TestMatchingProcesses_MatchModes(t *testing.T) {
+	processes := []processInfo{
+		{PID: 10, PPID: 1, Name: "code", Command: "/usr/share/code/code --unity-launch"},
+		{PID: 11, PPID: 1, Name: "code-server", Command: "/usr/lib/code-server/code-server --port 8080"},
+		{PID: 12, PPID: 1, Name: "node", Command: "node /home/dev/vscode-tools/encode.js"},
+	}
+
+	tests := []struct {
+		pattern string
+		want    []int
+	}{
+		{"exact:code", []int{10}},
+		{"exact:CODE-SERVER", []int{11}},
+		{"word:code", []int{10, 11}},
+		{"word:encode.js", []int{12}},
+		{"regex:code-server\\s+--port", []int{11}},
+		{"regex:^node ", []int{12}},
+		{"regex:[", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var got []int
+			for _, p := range matchingProcesses(processes, tt.pattern) {
+				got = append(got, p.PID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("matchingProcesses(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKillPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		prefix  string
+		value   string
+		wantErr bool
+	}{
+		{"Google Chrome", "", "Google Chrome", false},
+		{"exact:slack", killExactPrefix, "slack", false},
+		{"word:code", killWordPrefix, "code", false},
+		{"regex:^java .*idea", killRegexPrefix, "^java .*idea", false},
+		{"regex:(", "", "", true},
+		{"exact:", "", "", true},
+	}
+
+	for _, tt := range tests {
+		m, err := parseKillPattern(tt.pattern)
+		if (err != nil) != tt.wantErr || m.prefix != tt.prefix || m.value != tt.value {
+			t.Errorf("parseKillPattern(%q) = %+v, %v, want %q %q, error %v", tt.pattern, m, err, tt.prefix, tt.value, tt.wantErr)
+		}
+	}
+}
+
+func TestPkillArgs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"slack", "-i -f slack"},
+		{"exact:slack", "-i -x slack"},
+		{"word:code", "-i -f (^|[^[:alnum:]_])code([^[:alnum:]_]|$)"},
+		{"regex:^java .*idea", "-i -f ^java .*idea"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(pkillArgs(tt.pattern), " "); got != tt.want {
+			t.Errorf("pkillArgs(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
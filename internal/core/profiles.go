@@ -0,0 +1,34 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitAliasProfile splits "chrome@work" into the alias and profile name
+func SplitAliasProfile(alias string) (string, string) {
+	if i := strings.LastIndex(alias, "@"); i > 0 && i < len(alias)-1 {
+		return alias[:i], alias[i+1:]
+	}
+	return alias, ""
+}
+
+// profileArgs returns the arguments that open the app with a profile.
+// Profile names are looked up in the app's profiles, anything else is
+// passed to the browser as is.
+func profileArgs(name string, app *App, profile string) ([]string, error) {
+	template := app.GetProfileArgs(name)
+	if len(template) == 0 {
+		return nil, fmt.Errorf("%s has no profile support (set profile_args in the config)", name)
+	}
+
+	if mapped, ok := app.Profiles[profile]; ok {
+		profile = mapped
+	}
+
+	args := make([]string, len(template))
+	for i, arg := range template {
+		args[i] = strings.ReplaceAll(arg, "{profile}", profile)
+	}
+	return args, nil
+}
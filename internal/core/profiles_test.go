@@ -0,0 +1,89 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitAliasProfile(t *testing.T) {
+	tests := []struct {
+		input   string
+		alias   string
+		profile string
+	}{
+		{"chrome@work", "chrome", "work"},
+		{"chrome", "chrome", ""},
+		{"@work", "@work", ""},
+		{"chrome@", "chrome@", ""},
+		{"a@b@c", "a@b", "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			alias, profile := SplitAliasProfile(tt.input)
+			if alias != tt.alias || profile != tt.profile {
+				t.Errorf("SplitAliasProfile(%q) = %q, %q, want %q, %q", tt.input, alias, profile, tt.alias, tt.profile)
+			}
+		})
+	}
+}
+
+func TestLaunchApp_Profile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	testContent := `
+apps:
+  chrome:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+    profiles:
+      work: "Profile 1"
+  firefox:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+  notes:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+aliases:
+  gc: chrome`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{"gc@work", "--profile-directory=Profile 1 https://example.com"},
+		{"chrome@Default", "--profile-directory=Default https://example.com"},
+		{"firefox@dev", "-P dev https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			opts := LaunchOptions{Wait: true, Log: true}
+			if err := LaunchAppWithOptions(tt.alias, []string{"https://example.com"}, opts); err != nil {
+				t.Fatalf("LaunchAppWithOptions() unexpected error: %v", err)
+			}
+
+			base, _ := SplitAliasProfile(tt.alias)
+			data, err := os.ReadFile(getLogPath(base))
+			if err != nil {
+				t.Fatalf("failed to read log: %v", err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("launched with %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := LaunchAppWithOptions("notes@work", nil, LaunchOptions{}); err == nil {
+		t.Error("LaunchAppWithOptions(notes@work) expected error for an app without profile support")
+	}
+}
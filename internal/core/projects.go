@@ -0,0 +1,44 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LaunchProject opens a configured project directory with its editor
+func LaunchProject(name string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	project, ok := config.Projects[name]
+	if !ok {
+		return UnknownName(config, ListKindProject, name)
+	}
+	if project.Editor == "" || project.Path == "" {
+		return fmt.Errorf("project %s needs both an editor and a path", name)
+	}
+
+	path := expandTilde(project.Path)
+	if !exists(path) {
+		return fmt.Errorf("project %s directory not found: %s", name, path)
+	}
+
+	return LaunchApp(project.Editor, []string{path})
+}
+
+// ProjectNames returns the configured project names, sorted
+func ProjectNames() ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := make([]string, 0, len(config.Projects))
+	for name := range config.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
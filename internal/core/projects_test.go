@@ -0,0 +1,68 @@
+package core
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLaunchProject(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping echo tests on Windows")
+	}
+	projectDir := t.TempDir()
+
+	testContent := `
+apps:
+  vscode:
+    darwin: "/bin/echo"
+    linux: "/bin/echo"
+aliases:
+  code: vscode
+projects:
+  api:
+    editor: code
+    path: ` + projectDir + `
+  gone:
+    editor: code
+    path: /nonexistent/project
+  broken:
+    path: ` + projectDir
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	tests := []struct {
+		name      string
+		expectErr string
+	}{
+		{"api", ""},
+		{"gone", "directory not found"},
+		{"broken", "needs both an editor and a path"},
+		{"missing", "unknown project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LaunchProject(tt.name)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Errorf("LaunchProject(%s) unexpected error: %v", tt.name, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("LaunchProject(%s) error = %v, want %q", tt.name, err, tt.expectErr)
+			}
+		})
+	}
+
+	names, err := ProjectNames()
+	if err != nil {
+		t.Fatalf("ProjectNames() failed: %v", err)
+	}
+	if strings.Join(names, ",") != "api,broken,gone" {
+		t.Errorf("ProjectNames() = %v, want sorted project names", names)
+	}
+}
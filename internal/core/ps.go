@@ -0,0 +1,242 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessStatus is the runtime view of a configured application
+type ProcessStatus struct {
+	Name    string     `json:"name"`
+	Running bool       `json:"running"`
+	PIDs    []int      `json:"pids,omitempty"`    // top-level processes, children are left out
+	Started *time.Time `json:"started,omitempty"` // when openx launched the running instance
+	Groups  []string   `json:"groups,omitempty"`  // workspace groups the app belongs to
+
+	// OtherUsers own some of the app's processes; --kill can't stop those
+	OtherUsers []string `json:"otherUsers,omitempty"`
+
+	// Usage sums the resources of the app's processes and their children,
+	// when stats were asked for
+	Usage *ResourceUsage `json:"usage,omitempty"`
+
+	tree []int // the app's processes with their children
+}
+
+// RunPS lists every configured application with its running status, and
+// with stats the CPU, memory and open files of the running ones
+func RunPS(jsonOutput, stats bool) error {
+	statuses, err := ProcessStatuses(stats)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+
+	usageColumns := func(usage *ResourceUsage) string {
+		if !stats {
+			return ""
+		}
+		if usage == nil {
+			return fmt.Sprintf("%6s %7s %6s ", "", "", "")
+		}
+		files := "-"
+		if usage.OpenFiles > 0 {
+			files = strconv.Itoa(usage.OpenFiles)
+		}
+		return fmt.Sprintf("%6.1f %7s %6s ", usage.CPU, formatBytes(usage.RSS), files)
+	}
+
+	header := fmt.Sprintf("%-20s %-8s %-20s %-17s ", "NAME", "STATUS", "PID", "STARTED")
+	if stats {
+		header += fmt.Sprintf("%6s %7s %6s ", "CPU%", "RSS", "FILES")
+	}
+	fmt.Println(header + "GROUPS")
+	for _, status := range statuses {
+		state := "stopped"
+		color := ColorGray
+		if status.Running {
+			state, color = "running", ColorGreen
+		}
+
+		pids := make([]string, len(status.PIDs))
+		for i, pid := range status.PIDs {
+			pids[i] = strconv.Itoa(pid)
+		}
+		started := ""
+		if status.Started != nil {
+			started = status.Started.Format("2006-01-02 15:04")
+		}
+		line := fmt.Sprintf("%-20s %s%-8s%s %-20s %-17s %s%s", status.Name, color, state, ColorReset, strings.Join(pids, ","), started, usageColumns(status.Usage), strings.Join(status.Groups, ","))
+		fmt.Println(strings.TrimRight(line, " "))
+		if len(status.OtherUsers) > 0 {
+			fmt.Printf("  %s└─ owned by %s, --kill can't stop it%s\n", ColorYellow, strings.Join(status.OtherUsers, ", "), ColorReset)
+		}
+	}
+	return nil
+}
+
+// ProcessStatuses returns the runtime status of every configured app,
+// sorted by name, with the resource usage of running apps when stats is set
+func ProcessStatuses(stats bool) ([]ProcessStatus, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	processes, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	state, err := loadState()
+	if err != nil {
+		// Without the state file start times are unknown, nothing more
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		state = &launchState{}
+	}
+
+	names := make([]string, 0, len(config.Apps))
+	for name := range config.Apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := appGroups(config)
+	statuses := make([]ProcessStatus, 0, len(names))
+	for _, name := range names {
+		status := processStatus(config, name, processes, state.Launches)
+		status.Groups = groups[name]
+		statuses = append(statuses, status)
+	}
+
+	if err := addOwners(statuses); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if stats {
+		if err := addUsage(statuses); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return statuses, nil
+}
+
+// addUsage reads the resource usage of the running apps' processes
+func addUsage(statuses []ProcessStatus) error {
+	var pids []int
+	for _, status := range statuses {
+		pids = append(pids, status.tree...)
+	}
+	usages, err := processUsages(pids)
+	if err != nil {
+		return err
+	}
+
+	for i := range statuses {
+		if len(statuses[i].tree) > 0 {
+			usage := sumUsage(statuses[i].tree, usages)
+			statuses[i].Usage = &usage
+		}
+	}
+	return nil
+}
+
+// processStatus finds a configured app's processes: those its kill
+// patterns select and those openx launched for it
+func processStatus(config *Config, name string, processes []processInfo, launches []launchRecord) ProcessStatus {
+	app := config.Apps[name]
+	status := ProcessStatus{Name: name}
+	exclude := append(append(append([]string{}, app.KillExclude...), config.Protected...), defaultProtectedProcesses...)
+	var roots []processInfo
+
+	switch {
+	case isDockerApp(app):
+		status.Running = dockerContainerState(dockerContainerName(name, app)) == "running"
+	case isUWPOnly(app):
+		aumid, _ := uwpAppID(app.GetLaunchPath())
+		status.Running = isUWPAppRunning(aumid)
+	default:
+		for _, pattern := range appKillPatterns(app) {
+			roots = append(roots, excludeProcesses(matchingProcesses(processes, pattern), exclusionsFor(pattern, exclude))...)
+		}
+		status.PIDs = topLevelPIDs(roots)
+	}
+
+	// The newest launch that is still running gives the start time. A
+	// launcher's pid is gone, its launch counts while the app runs.
+	running := status.Running || len(status.PIDs) > 0
+	for i := len(launches) - 1; i >= 0; i-- {
+		launch := launches[i]
+		if launchName, _, err := lookupApp(config, launch.Alias); err != nil || launchName != name {
+			continue
+		}
+		p := recordedProcess(launch, processes)
+		if p == nil && !(launch.Launcher && running) {
+			continue
+		}
+		if p != nil && !slices.Contains(status.PIDs, p.PID) {
+			status.PIDs = append(status.PIDs, p.PID)
+			slices.Sort(status.PIDs)
+			roots = append(roots, *p)
+		}
+		started := launch.Started
+		status.Started = &started
+		break
+	}
+
+	status.Running = status.Running || len(status.PIDs) > 0
+	if len(roots) > 0 {
+		status.tree = slices.Sorted(maps.Keys(processTree(processes, roots, exclude)))
+	}
+	return status
+}
+
+// isUWPOnly reports whether a Store app is found by package rather than
+// by kill patterns
+func isUWPOnly(app *App) bool {
+	_, ok := uwpAppID(app.GetLaunchPath())
+	return ok && len(app.Kill) == 0
+}
+
+// topLevelPIDs returns the sorted pids of the processes whose parent is
+// not among them, so an app's helpers don't crowd the listing
+func topLevelPIDs(processes []processInfo) []int {
+	matched := map[int]bool{}
+	for _, p := range processes {
+		matched[p.PID] = true
+	}
+
+	var pids []int
+	for _, p := range processes {
+		if !matched[p.PPID] && !slices.Contains(pids, p.PID) {
+			pids = append(pids, p.PID)
+		}
+	}
+	slices.Sort(pids)
+	return pids
+}
+
+// appGroups maps each app to the sorted names of the groups it belongs to
+func appGroups(config *Config) map[string][]string {
+	groups := map[string][]string{}
+	for group, members := range config.Groups {
+		for _, member := range members {
+			if name, _, err := lookupApp(config, member.App); err == nil && !slices.Contains(groups[name], group) {
+				groups[name] = append(groups[name], group)
+			}
+		}
+	}
+	for _, names := range groups {
+		sort.Strings(names)
+	}
+	return groups
+}
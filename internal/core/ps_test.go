@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProcessStatus(t *testing.T) {
+	testContent := `
+apps:
+  slack:
+    linux: "/usr/lib/slack/slack"
+    darwin: "/Applications/Slack.app"
+    windows: "slack.exe"
+    kill: ["slack"]
+  notes:
+    linux: "/usr/bin/notes"
+    darwin: "/Applications/Notes.app"
+    windows: "notes.exe"
+    kill: ["notes-app"]
+  preview:
+    linux: "/usr/bin/preview"
+    darwin: "/Applications/Preview.app"
+    windows: "preview.exe"
+    kill: ["preview"]
+aliases:
+  n: notes
+groups:
+  comms: [slack]
+  work: [slack, n]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+
+	processes := []processInfo{
+		{PID: 812, PPID: 1, Name: "slack", Command: "/usr/lib/slack/slack"},
+		{PID: 830, PPID: 812, Name: "slack", Command: "/usr/lib/slack/slack --type=renderer"},
+		{PID: 900, PPID: 1, Name: "notes", Command: "/usr/bin/notes"},
+	}
+	started := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	launches := []launchRecord{
+		{Alias: "n", PID: 900, Name: "notes", Started: started},
+		{Alias: "preview", Launcher: true, Started: started},
+	}
+
+	slack := processStatus(config, "slack", processes, launches)
+	if !slack.Running || fmt.Sprint(slack.PIDs) != "[812]" || slack.Started != nil {
+		t.Errorf("slack status = %+v, want running as pid 812 without a start time", slack)
+	}
+
+	// notes' kill pattern misses it, the recorded launch still finds it
+	notes := processStatus(config, "notes", processes, launches)
+	if !notes.Running || fmt.Sprint(notes.PIDs) != "[900]" || notes.Started == nil || !notes.Started.Equal(started) {
+		t.Errorf("notes status = %+v, want running as pid 900 since the launch", notes)
+	}
+
+	// A launcher's launch doesn't make a stopped app running
+	preview := processStatus(config, "preview", processes, launches)
+	if preview.Running || len(preview.PIDs) != 0 || preview.Started != nil {
+		t.Errorf("preview status = %+v, want stopped", preview)
+	}
+
+	groups := appGroups(config)
+	if fmt.Sprint(groups["slack"]) != "[comms work]" || fmt.Sprint(groups["notes"]) != "[work]" {
+		t.Errorf("appGroups() = %v, want slack in comms and work, notes in work", groups)
+	}
+}
+
+func TestTopLevelPIDs(t *testing.T) {
+	processes := []processInfo{
+		{PID: 830, PPID: 812},
+		{PID: 812, PPID: 1},
+		{PID: 845, PPID: 830},
+		{PID: 700, PPID: 1},
+	}
+
+	if got := fmt.Sprint(topLevelPIDs(processes)); got != "[700 812]" {
+		t.Errorf("topLevelPIDs() = %s, want [700 812]", got)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package core
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// rawTerminal hands each key press to openx as it's typed, without
+// echoing it, and returns the function putting the terminal back
+func rawTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+// stty runs stty on openx's terminal
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxRecentLaunches is how many distinct launches openx recent keeps
+const maxRecentLaunches = 20
+
+// RecentLaunch is an app launched lately, as typed, with its arguments
+// made absolute so it relaunches the same from any directory
+type RecentLaunch struct {
+	Alias string    `json:"alias"`
+	Args  []string  `json:"args,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+// recordRecent puts a launch at the top of the recent launches, moving it
+// there when it was launched before with the same arguments. Unlike the
+// launch records, recent launches outlive kill --session.
+func recordRecent(alias string, args []string) {
+	launch := RecentLaunch{Alias: alias, Args: resolveTargets(args), Time: time.Now()}
+	err := updateState(func(state *launchState) error {
+		state.Recent = slices.DeleteFunc(state.Recent, func(r RecentLaunch) bool {
+			return r.Alias == launch.Alias && slices.Equal(r.Args, launch.Args)
+		})
+		state.Recent = append(state.Recent, launch)
+		if extra := len(state.Recent) - maxRecentLaunches; extra > 0 {
+			state.Recent = state.Recent[extra:]
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: launch of %s not recorded: %v\n", alias, err)
+	}
+}
+
+// RecentLaunches returns the latest distinct launches, newest first
+func RecentLaunches() ([]RecentLaunch, error) {
+	state, err := loadState()
+	if err != nil {
+		return nil, err
+	}
+	recent := slices.Clone(state.Recent)
+	slices.Reverse(recent)
+	return recent, nil
+}
+
+// RunRecent prints the latest distinct launches, newest first
+func RunRecent(jsonOutput bool) error {
+	recent, err := RecentLaunches()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(recent)
+	}
+
+	if len(recent) == 0 {
+		fmt.Println("Nothing launched yet")
+		return nil
+	}
+	for _, launch := range recent {
+		fmt.Printf("%s  %-20s %s\n", launch.Time.Local().Format("2006-01-02 15:04"), launch.Alias, strings.Join(launch.Args, " "))
+	}
+	return nil
+}
+
+// RelaunchLast launches the most recent app again with the same
+// arguments, as openx - does
+func RelaunchLast(opts LaunchOptions) error {
+	recent, err := RecentLaunches()
+	if err != nil {
+		return err
+	}
+	if len(recent) == 0 {
+		return ErrNothingLaunched
+	}
+
+	last := recent[0]
+	info("Relaunching: %s", strings.Join(append([]string{last.Alias}, last.Args...), " "))
+	return LaunchAppWithOptions(last.Alias, last.Args, opts)
+}
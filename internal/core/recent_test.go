@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordRecent(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	recordRecent("chrome", nil)
+	recordRecent("code", []string{"/work/app"})
+	recordRecent("chrome", []string{"https://example.com"})
+	// Launching again with the same arguments moves it to the top
+	recordRecent("code", []string{"/work/app"})
+
+	recent, err := RecentLaunches()
+	if err != nil {
+		t.Fatalf("RecentLaunches() unexpected error: %v", err)
+	}
+	var got []string
+	for _, launch := range recent {
+		got = append(got, strings.TrimSpace(launch.Alias+" "+strings.Join(launch.Args, " ")))
+	}
+	want := []string{"code /work/app", "chrome https://example.com", "chrome"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecentLaunches() = %q, want %q", got, want)
+	}
+}
+
+func TestRecordRecentKeepsLatest(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	for i := range maxRecentLaunches + 5 {
+		recordRecent(fmt.Sprintf("app%d", i), nil)
+	}
+
+	recent, err := RecentLaunches()
+	if err != nil {
+		t.Fatalf("RecentLaunches() unexpected error: %v", err)
+	}
+	if len(recent) != maxRecentLaunches {
+		t.Fatalf("RecentLaunches() kept %d launches, want %d", len(recent), maxRecentLaunches)
+	}
+	if first, last := recent[0].Alias, recent[len(recent)-1].Alias; first != "app24" || last != "app5" {
+		t.Errorf("RecentLaunches() runs from %s to %s, want app24 to app5", first, last)
+	}
+}
+
+func TestRelaunchLastWithoutLaunches(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if err := RelaunchLast(LaunchOptions{}); err == nil || !strings.Contains(err.Error(), "nothing launched yet") {
+		t.Errorf("RelaunchLast() error = %v, want nothing launched yet", err)
+	}
+}
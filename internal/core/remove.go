@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// removal is everything removing an app takes out of the config
+type removal struct {
+	App        string
+	Aliases    []string            // aliases of the app
+	Groups     map[string][]string // group -> names of the app dropped from it
+	EmptyGroup []string            // groups left without members, removed too
+	Needs      []string            // apps no longer needing it
+	Extensions []string            // file extensions no longer opened with it
+	Terminal   bool                // the terminal setting named it
+	Projects   []string            // projects still naming it as editor, kept
+}
+
+// RemoveApp deletes an app from the config with the aliases, group
+// members, needs and extensions pointing at it, after showing what goes
+// and asking unless yes is set
+func RemoveApp(name string, yes bool) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	r, err := planRemoval(config, name)
+	if err != nil {
+		return err
+	}
+	for _, line := range r.describe() {
+		fmt.Println(line)
+	}
+	if !yes && !confirm("Remove?") {
+		return fmt.Errorf("%s not removed", name)
+	}
+
+	r.apply(config)
+	if err := saveConfig(config); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s\n", name)
+	return nil
+}
+
+// planRemoval finds what removing the app name takes out of cfg
+func planRemoval(cfg *Config, name string) (*removal, error) {
+	if _, exists := cfg.Apps[name]; !exists {
+		if target, isAlias := cfg.Aliases[name]; isAlias {
+			return nil, fmt.Errorf("%s is an alias of %s, remove the app by its name", name, target)
+		}
+		return nil, UnknownName(cfg, ListKindApp, name)
+	}
+
+	r := &removal{App: name, Groups: map[string][]string{}}
+	names := map[string]bool{name: true}
+	for _, alias := range slices.Sorted(maps.Keys(cfg.Aliases)) {
+		if cfg.Aliases[alias] == name {
+			r.Aliases = append(r.Aliases, alias)
+			names[alias] = true
+		}
+	}
+
+	for _, group := range slices.Sorted(maps.Keys(cfg.Groups)) {
+		kept := 0
+		for _, member := range cfg.Groups[group] {
+			if names[member.App] {
+				r.Groups[group] = append(r.Groups[group], member.App)
+			} else {
+				kept++
+			}
+		}
+		if len(r.Groups[group]) > 0 && kept == 0 {
+			r.EmptyGroup = append(r.EmptyGroup, group)
+		}
+	}
+	for _, other := range slices.Sorted(maps.Keys(cfg.Apps)) {
+		if other != name && slices.ContainsFunc(cfg.Apps[other].Needs, func(n string) bool { return names[n] }) {
+			r.Needs = append(r.Needs, other)
+		}
+	}
+	for _, ext := range slices.Sorted(maps.Keys(cfg.Extensions)) {
+		if names[cfg.Extensions[ext]] {
+			r.Extensions = append(r.Extensions, ext)
+		}
+	}
+	r.Terminal = names[cfg.Terminal]
+	for _, project := range slices.Sorted(maps.Keys(cfg.Projects)) {
+		if names[cfg.Projects[project].Editor] {
+			r.Projects = append(r.Projects, project)
+		}
+	}
+	return r, nil
+}
+
+// describe lists what the removal takes out, one line each
+func (r *removal) describe() []string {
+	lines := []string{fmt.Sprintf("Removing app %s", r.App)}
+	if len(r.Aliases) > 0 {
+		lines = append(lines, "  aliases: "+strings.Join(r.Aliases, ", "))
+	}
+	for _, group := range slices.Sorted(maps.Keys(r.Groups)) {
+		if slices.Contains(r.EmptyGroup, group) {
+			lines = append(lines, fmt.Sprintf("  group %s, which has no other members", group))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  from group %s", group))
+	}
+	if len(r.Needs) > 0 {
+		lines = append(lines, "  from the needs of "+strings.Join(r.Needs, ", "))
+	}
+	if len(r.Extensions) > 0 {
+		lines = append(lines, "  extensions: "+strings.Join(r.Extensions, ", "))
+	}
+	if r.Terminal {
+		lines = append(lines, "  the terminal setting, back to the system terminal")
+	}
+	for _, project := range r.Projects {
+		lines = append(lines, fmt.Sprintf("%sKeeping project %s, which opens with %s: give it another editor%s", ColorYellow, project, r.App, ColorReset))
+	}
+	return lines
+}
+
+// apply takes the removal out of cfg
+func (r *removal) apply(cfg *Config) {
+	names := map[string]bool{r.App: true}
+	for _, alias := range r.Aliases {
+		names[alias] = true
+		delete(cfg.Aliases, alias)
+	}
+	delete(cfg.Apps, r.App)
+
+	for group := range r.Groups {
+		if slices.Contains(r.EmptyGroup, group) {
+			delete(cfg.Groups, group)
+			continue
+		}
+		cfg.Groups[group] = slices.DeleteFunc(cfg.Groups[group], func(m GroupMember) bool { return names[m.App] })
+	}
+	for _, other := range r.Needs {
+		app := cfg.Apps[other]
+		app.Needs = slices.DeleteFunc(app.Needs, func(n string) bool { return names[n] })
+	}
+	for _, ext := range r.Extensions {
+		delete(cfg.Extensions, ext)
+	}
+	if r.Terminal {
+		cfg.Terminal = ""
+	}
+}
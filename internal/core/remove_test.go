@@ -0,0 +1,73 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanRemoval(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"postgres": {},
+			"api":      {Needs: []string{"pg", "redis"}},
+			"redis":    {},
+		},
+		Aliases:    map[string]string{"pg": "postgres", "db": "postgres", "r": "redis"},
+		Groups:     map[string][]GroupMember{"backend": {{App: "pg"}, {App: "api"}}, "data": {{App: "postgres"}}},
+		Extensions: map[string]string{".sql": "db", ".rdb": "redis"},
+		Projects:   map[string]Project{"schema": {Editor: "postgres", Path: "~/schema"}},
+		Terminal:   "postgres",
+	}
+
+	r, err := planRemoval(cfg, "postgres")
+	if err != nil {
+		t.Fatalf("planRemoval() error: %v", err)
+	}
+	want := &removal{
+		App:        "postgres",
+		Aliases:    []string{"db", "pg"},
+		Groups:     map[string][]string{"backend": {"pg"}, "data": {"postgres"}},
+		EmptyGroup: []string{"data"},
+		Needs:      []string{"api"},
+		Extensions: []string{".sql"},
+		Terminal:   true,
+		Projects:   []string{"schema"},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Fatalf("planRemoval() = %+v, want %+v", r, want)
+	}
+	if summary := strings.Join(r.describe(), "\n"); !strings.Contains(summary, "group data, which has no other members") || !strings.Contains(summary, "Keeping project schema") {
+		t.Errorf("describe() = %s", summary)
+	}
+
+	r.apply(cfg)
+	if _, ok := cfg.Apps["postgres"]; ok {
+		t.Error("app still configured")
+	}
+	if !reflect.DeepEqual(cfg.Aliases, map[string]string{"r": "redis"}) {
+		t.Errorf("aliases = %v", cfg.Aliases)
+	}
+	if !reflect.DeepEqual(cfg.Groups, map[string][]GroupMember{"backend": {{App: "api"}}}) {
+		t.Errorf("groups = %v", cfg.Groups)
+	}
+	if !reflect.DeepEqual(cfg.Apps["api"].Needs, []string{"redis"}) {
+		t.Errorf("needs = %v", cfg.Apps["api"].Needs)
+	}
+	if !reflect.DeepEqual(cfg.Extensions, map[string]string{".rdb": "redis"}) || cfg.Terminal != "" {
+		t.Errorf("extensions = %v, terminal = %q", cfg.Extensions, cfg.Terminal)
+	}
+	if _, ok := cfg.Projects["schema"]; !ok {
+		t.Error("project removed, it should be kept")
+	}
+}
+
+func TestPlanRemoval_NotAnApp(t *testing.T) {
+	cfg := &Config{Apps: map[string]*App{"postgres": {}}, Aliases: map[string]string{"pg": "postgres"}}
+	if _, err := planRemoval(cfg, "pg"); err == nil || !strings.Contains(err.Error(), "alias of postgres") {
+		t.Errorf("planRemoval(alias) error = %v", err)
+	}
+	if _, err := planRemoval(cfg, "mysql"); err == nil || !strings.Contains(err.Error(), "unknown app") {
+		t.Errorf("planRemoval(unknown) error = %v", err)
+	}
+}
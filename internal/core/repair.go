@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// RepairPaths looks for the apps doctor reports missing in the standard
+// install locations and on PATH, and rewrites the config path of each one
+// found, after asking unless yes is set
+func RepairPaths(yes bool) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := make([]string, 0, len(config.Apps))
+	for name := range config.Apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var installed []ScannedApp
+	scanned := false
+	missing, fixed := 0, 0
+	for _, name := range names {
+		app := config.Apps[name]
+		path := app.GetLaunchPath()
+		if isDockerApp(app) || path == "" || appExists(path) {
+			continue
+		}
+		missing++
+
+		if !scanned {
+			if installed, err = DiscoverApps(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			scanned = true
+		}
+
+		replacement, found := findReplacement(name, path, installed)
+		if !found {
+			fmt.Printf("%s: %s is missing, no installed match found\n", name, path)
+			continue
+		}
+
+		fmt.Printf("%s: %s is missing, found %s\n", name, path, replacement)
+		if !yes && !confirm("Use it?") {
+			continue
+		}
+		app.Paths[runtime.GOOS] = replacement
+		fixed++
+	}
+
+	if missing == 0 {
+		fmt.Println("No missing apps to repair")
+		return nil
+	}
+	if fixed > 0 {
+		if err := saveConfig(config); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Repaired %d of %d missing apps\n", fixed, missing)
+	return nil
+}
+
+// findReplacement picks the installed application that most likely is the
+// missing one: a name matching the app or its old path exactly, then the
+// command on PATH, then an installed name that starts with it, such as a
+// versioned IntelliJ IDEA 2024.1
+func findReplacement(name, path string, installed []ScannedApp) (string, bool) {
+	base := filepath.Base(strings.ReplaceAll(path, `\`, "/"))
+	wanted := []string{normalizeAppName(name), normalizeAppName(strings.TrimSuffix(base, filepath.Ext(base)))}
+
+	matches := func(scanned ScannedApp, exact bool) bool {
+		normalized := normalizeAppName(scanned.Name)
+		if normalized == "" || scanned.Path == path {
+			return false
+		}
+		for _, want := range wanted {
+			if want == "" {
+				continue
+			}
+			if normalized == want || knownAppKeys[normalized] == want {
+				return true
+			}
+			if !exact && strings.HasPrefix(normalized, want) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, scanned := range installed {
+		if matches(scanned, true) {
+			return scanned.Path, true
+		}
+	}
+	for _, command := range []string{base, name} {
+		if command != "" && command != path {
+			if _, err := exec.LookPath(command); err == nil {
+				return command, true
+			}
+		}
+	}
+	for _, scanned := range installed {
+		if matches(scanned, false) {
+			return scanned.Path, true
+		}
+	}
+	return "", false
+}
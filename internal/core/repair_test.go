@@ -0,0 +1,75 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestFindReplacement(t *testing.T) {
+	installed := []ScannedApp{
+		{Name: "Visual Studio Code", Path: "/Applications/Visual Studio Code.app"},
+		{Name: "IntelliJ IDEA 2024.1", Path: "/Applications/IntelliJ IDEA 2024.1.app"},
+		{Name: "Slack", Path: "/Applications/Slack.app"},
+		{Name: "Slack Helper", Path: "/Applications/Slack Helper.app"},
+	}
+
+	tests := []struct {
+		name  string
+		app   string
+		path  string
+		want  string
+		found bool
+	}{
+		{"known key", "vscode", "/opt/vscode/code", "/Applications/Visual Studio Code.app", true},
+		{"old path name", "chat", "/Applications/Old/Slack.app", "/Applications/Slack.app", true},
+		{"versioned install", "intellij", "/Applications/IntelliJ IDEA.app", "/Applications/IntelliJ IDEA 2024.1.app", true},
+		{"current path is not a fix", "slack", "/Applications/Slack.app", "/Applications/Slack Helper.app", true},
+		{"nothing similar", "photoshop", "/Applications/Adobe Photoshop.app", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := findReplacement(tt.app, tt.path, installed)
+			if got != tt.want || found != tt.found {
+				t.Errorf("findReplacement(%q, %q) = %q, %v, want %q, %v", tt.app, tt.path, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestRepairPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Uses sh from PATH")
+	}
+
+	testContent := `
+apps:
+  shell:
+    ` + runtime.GOOS + `: "/nonexistent/openx-repair/sh"
+  gone:
+    ` + runtime.GOOS + `: "/nonexistent/openx-repair/openx-no-such-app"
+  echo:
+    ` + runtime.GOOS + `: "/bin/echo"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := RepairPaths(true); err != nil {
+		t.Fatalf("RepairPaths() unexpected error: %v", err)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+	if got := config.Apps["shell"].GetLaunchPath(); got != "sh" {
+		t.Errorf("shell path = %q, want sh from PATH", got)
+	}
+	if got := config.Apps["gone"].GetLaunchPath(); got != "/nonexistent/openx-repair/openx-no-such-app" {
+		t.Errorf("gone path = %q, want it left alone", got)
+	}
+	if got := config.Apps["echo"].GetLaunchPath(); got != "/bin/echo" {
+		t.Errorf("echo path = %q, want it left alone", got)
+	}
+}
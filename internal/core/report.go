@@ -0,0 +1,86 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Launch statuses of a LaunchReport
+const (
+	LaunchStatusLaunched = "launched" // started and left running
+	LaunchStatusExited   = "exited"   // ran to its end with Wait
+	LaunchStatusFailed   = "failed"   // not found or didn't start
+)
+
+// LaunchReport is how a launch went, for scripts reading --json
+type LaunchReport struct {
+	Alias string `json:"alias"`
+	App   string `json:"app,omitempty"` // the app's name in the config, empty for paths
+
+	// Path and Args are what was started, PID its process; they stay
+	// empty for Docker apps and launches that failed before starting
+	Path string   `json:"path,omitempty"`
+	Args []string `json:"args,omitempty"`
+	PID  int      `json:"pid,omitempty"`
+
+	Status   string `json:"status"`             // one of the LaunchStatus values
+	ExitCode *int   `json:"exitCode,omitempty"` // with Wait, once the app exited
+	Error    string `json:"error,omitempty"`
+}
+
+// LaunchAppReport launches an application like LaunchAppWithOptions and
+// reports what was started
+func LaunchAppReport(alias string, args []string, opts LaunchOptions) (*LaunchReport, error) {
+	report := &LaunchReport{Alias: alias}
+	opts.report = report
+	err := LaunchAppWithOptions(alias, args, opts)
+
+	var exitErr *AppExitError
+	switch {
+	case errors.As(err, &exitErr):
+		report.Status, report.ExitCode = LaunchStatusExited, &exitErr.Code
+	case err != nil:
+		report.Status, report.Error = LaunchStatusFailed, err.Error()
+	case opts.Wait:
+		code := 0
+		report.Status, report.ExitCode = LaunchStatusExited, &code
+	default:
+		report.Status = LaunchStatusLaunched
+	}
+	return report, err
+}
+
+// MarshalJSON adds the error of a failed member as a string
+func (r LaunchResult) MarshalJSON() ([]byte, error) {
+	type result LaunchResult
+	return json.Marshal(struct {
+		result
+		Error string `json:"error,omitempty"`
+	}{result(r), errorString(r.Err)})
+}
+
+// MarshalJSON adds the error closing the app as a string
+func (r CloseResult) MarshalJSON() ([]byte, error) {
+	type result CloseResult
+	return json.Marshal(struct {
+		result
+		Error string `json:"error,omitempty"`
+	}{result(r), errorString(r.Err)})
+}
+
+// MarshalJSON adds the error stopping the pattern's processes as a string
+func (r PatternReport) MarshalJSON() ([]byte, error) {
+	type report PatternReport
+	return json.Marshal(struct {
+		report
+		Error string `json:"error,omitempty"`
+	}{report(r), errorString(r.Err)})
+}
+
+// errorString returns err's message, empty for nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLaunchAppReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping true/false tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	testContent := `
+apps:
+  succeed:
+    darwin: "/usr/bin/true"
+    linux: "/bin/true"
+  fail:
+    darwin: "/usr/bin/false"
+    linux: "/bin/false"
+  gone:
+    linux: "/nonexistent/app"
+    darwin: "/nonexistent/app"
+aliases:
+  ok: succeed`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	report, err := LaunchAppReport("ok", nil, LaunchOptions{Wait: true})
+	if err != nil {
+		t.Fatalf("LaunchAppReport(ok) unexpected error: %v", err)
+	}
+	if report.App != "succeed" || report.Status != LaunchStatusExited || report.ExitCode == nil || *report.ExitCode != 0 {
+		t.Errorf("LaunchAppReport(ok) = %+v, want succeed exited with 0", report)
+	}
+	if !strings.HasSuffix(report.Path, "true") || report.PID == 0 {
+		t.Errorf("LaunchAppReport(ok) path %q pid %d, want the true command and its pid", report.Path, report.PID)
+	}
+
+	report, err = LaunchAppReport("fail", nil, LaunchOptions{Wait: true})
+	var exitErr *AppExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("LaunchAppReport(fail) error = %v, want AppExitError", err)
+	}
+	if report.Status != LaunchStatusExited || report.ExitCode == nil || *report.ExitCode != 1 {
+		t.Errorf("LaunchAppReport(fail) = %+v, want exited with 1", report)
+	}
+
+	report, err = LaunchAppReport("gone", nil, LaunchOptions{})
+	if err == nil {
+		t.Fatal("LaunchAppReport(gone) expected an error")
+	}
+	if report.Status != LaunchStatusFailed || report.Error != err.Error() || report.PID != 0 {
+		t.Errorf("LaunchAppReport(gone) = %+v, want failed with the error", report)
+	}
+}
+
+func TestResultsJSON(t *testing.T) {
+	results := []CloseResult{
+		{Alias: "chrome", Running: true, Report: &KillReport{Alias: "chrome", Patterns: []PatternReport{
+			{Pattern: "chrome", Matched: true, PIDs: []int{42}, Method: KillMethodSignal, Stopped: false, Err: errors.New("permission denied")},
+		}}},
+		{Alias: "slack", Err: errors.New("unknown alias")},
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	want := `[{"alias":"chrome","running":true,"report":{"alias":"chrome","patterns":[{"pattern":"chrome","matched":true,"pids":[42],"method":"signal","stopped":false,"error":"permission denied"}]}},{"alias":"slack","running":false,"error":"unknown alias"}]`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s\nwant %s", data, want)
+	}
+
+	data, err = json.Marshal(LaunchResult{Alias: "code", Skipped: true})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %v", err)
+	}
+	if want := `{"alias":"code","skipped":true}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
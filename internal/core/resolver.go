@@ -272,8 +272,8 @@ func getSystemOpener() (string, []string) {
 
 // resolveTarget processes a target (file, URL, directory) and returns the resolved path
 func resolveTarget(target string) string {
-	// Don't modify URLs
-	if isURL(target) {
+	// Don't modify URLs or flags meant for the application
+	if isURL(target) || strings.HasPrefix(target, "-") {
 		return target
 	}
 
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+)
+
+// RestartApp closes an application and launches it again with args once
+// its old processes are gone. An app that isn't running is just launched.
+func RestartApp(alias string, args []string, opts KillOptions) error {
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// A single-instance app would hand the launch to its exiting self
+	opts.Wait = true
+
+	if _, err := closeRunning(config, alias, opts); err != nil {
+		return err
+	}
+	return LaunchApp(alias, args)
+}
+
+// RestartGroup bounces a workspace group: members close in reverse launch
+// order, then the group launches again in dependency order with at most
+// concurrency apps starting at once. Dependencies pulled in by needs stay
+// up throughout. Nothing is launched when a member fails to close.
+func RestartGroup(name string, opts KillOptions, concurrency int) ([]LaunchResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	members, exists := lookupGroup(config, name)
+	if !exists {
+		return nil, UnknownName(config, ListKindGroup, name)
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group %s has no members", name)
+	}
+	plan, err := buildLaunchPlan(config, members)
+	if err != nil {
+		return nil, err
+	}
+
+	info("Restarting group: %s", name)
+	opts.Wait = true
+	results := closeMembers(config, shutdownOrder(config, members), opts)
+	if err := reportClosed("apps in "+name, results); err != nil {
+		return nil, fmt.Errorf("not restarting %s: %w", name, err)
+	}
+
+	return executeLaunchPlan(config, plan, concurrency)
+}
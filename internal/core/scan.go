@@ -0,0 +1,406 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ScannedApp is an installed application found on this machine
+type ScannedApp struct {
+	Name   string `json:"name"`   // display name, e.g. "Visual Studio Code"
+	Path   string `json:"path"`   // launch path for the current OS
+	Source string `json:"source"` // where it was found, one of the Source constants
+}
+
+// Where DiscoverApps found an application
+const (
+	SourceFolder    = "folder"     // Applications folders, Program Files
+	SourceSpotlight = "spotlight"  // macOS Spotlight index
+	SourceDesktop   = "desktop"    // freedesktop .desktop entries
+	SourceAppPaths  = "app-paths"  // Windows App Paths registry key
+	SourceStartMenu = "start-menu" // Windows Start Menu shortcuts
+)
+
+// knownAppKeys maps normalized display names to the config keys used
+// by the starter templates and built-in synonyms
+var knownAppKeys = map[string]string{
+	"visualstudiocode":     "vscode",
+	"code":                 "vscode",
+	"googlechrome":         "chrome",
+	"googlechromestable":   "chrome",
+	"mozillafirefox":       "firefox",
+	"microsoftedge":        "edge",
+	"bravebrowser":         "brave",
+	"intellijidea":         "intellij",
+	"intellijideace":       "intellij",
+	"intellijideaultimate": "intellij",
+	"sublimetext":          "sublime",
+	"iterm2":               "iterm",
+	"microsoftword":        "word",
+	"microsoftexcel":       "excel",
+	"microsoftpowerpoint":  "powerpoint",
+	"microsoftteams":       "teams",
+	"dockerdesktop":        "docker",
+	"windowsterminal":      "windowsterminal",
+}
+
+// InitScannedConfig writes a config built from the applications installed
+// on this machine. An existing config is only replaced when force is set.
+// The apps found are listed first, and unless yes is set the user may
+// leave some of them out.
+func InitScannedConfig(force, yes bool) error {
+	configPath := getConfigPath()
+	if exists(configPath) && !force {
+		return fmt.Errorf("config already exists at %s (use --force to overwrite)", configPath)
+	}
+
+	apps, err := DiscoverApps()
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		return fmt.Errorf("no applications found on this machine")
+	}
+
+	config := buildScannedConfig(apps)
+	keys := slices.Sorted(maps.Keys(config.Apps))
+	printScannedConfig(config, keys)
+	if !yes {
+		answer := prompt("Numbers of apps to leave out, such as 2 5-7 (Enter keeps all):")
+		skipped, err := parseSelection(answer, len(keys))
+		if err != nil {
+			return err
+		}
+		for _, i := range skipped {
+			dropScannedApp(config, keys[i])
+		}
+		if len(config.Apps) == 0 {
+			return fmt.Errorf("every application left out, %s not written", configPath)
+		}
+	}
+
+	if err := saveConfig(config); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d applications to %s\n", len(config.Apps), configPath)
+	fmt.Printf("Edit %s to customize your environment.\n", configPath)
+	return nil
+}
+
+// printScannedConfig lists the apps of a scanned config, numbered in the
+// order of keys, with their paths, kill patterns and aliases
+func printScannedConfig(config *Config, keys []string) {
+	aliases := map[string][]string{}
+	for _, alias := range slices.Sorted(maps.Keys(config.Aliases)) {
+		aliases[config.Aliases[alias]] = append(aliases[config.Aliases[alias]], alias)
+	}
+
+	fmt.Printf("Discovered %d applications:\n", len(keys))
+	for i, key := range keys {
+		app := config.Apps[key]
+		fmt.Printf("%4d) %-20s %s\n", i+1, key, app.GetLaunchPath())
+		if len(app.Kill) > 0 {
+			fmt.Printf("      %skill: %s%s\n", ColorGray, strings.Join(app.Kill, ", "), ColorReset)
+		}
+		if len(aliases[key]) > 0 {
+			fmt.Printf("      %saliases: %s%s\n", ColorGray, strings.Join(aliases[key], ", "), ColorReset)
+		}
+	}
+}
+
+// parseSelection reads numbers and ranges such as "2 5-7" or "2,5-7",
+// counting from 1 up to n, into indexes counting from 0
+func parseSelection(answer string, n int) ([]int, error) {
+	var selected []int
+	for _, field := range strings.Fields(strings.ReplaceAll(answer, ",", " ")) {
+		first, last, isRange := strings.Cut(field, "-")
+		from, err := strconv.Atoi(first)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(last)
+		}
+		if err != nil || from > to {
+			return nil, fmt.Errorf("invalid selection %q: give numbers such as 2 or ranges such as 5-7", field)
+		}
+		if from < 1 || to > n {
+			return nil, fmt.Errorf("invalid selection %q: apps are numbered 1 to %d", field, n)
+		}
+		for i := from; i <= to; i++ {
+			selected = append(selected, i-1)
+		}
+	}
+	return selected, nil
+}
+
+// dropScannedApp removes an app and its aliases from a scanned config
+func dropScannedApp(config *Config, key string) {
+	delete(config.Apps, key)
+	maps.DeleteFunc(config.Aliases, func(_, app string) bool { return app == key })
+}
+
+// buildScannedConfig turns scanned applications into a config with
+// derived keys and aliases for the current OS
+func buildScannedConfig(apps []ScannedApp) *Config {
+	config := &Config{
+		Apps:    map[string]*App{},
+		Aliases: map[string]string{},
+	}
+
+	for _, scanned := range apps {
+		normalized := normalizeAppName(scanned.Name)
+		if normalized == "" {
+			continue
+		}
+
+		key := normalized
+		if known, ok := knownAppKeys[normalized]; ok {
+			key = known
+		}
+		if _, taken := config.Apps[key]; taken {
+			continue
+		}
+
+		app := &App{Paths: map[string]string{runtime.GOOS: scanned.Path}}
+		app.Kill = appKillPatterns(app)
+		config.Apps[key] = app
+	}
+
+	// Derive aliases once all keys are known so they never shadow an app
+	keys := make([]string, 0, len(config.Apps))
+	for key := range config.Apps {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, alias := range suggestAliases(key) {
+			if _, isApp := config.Apps[alias]; isApp {
+				continue
+			}
+			if _, taken := config.Aliases[alias]; taken {
+				continue
+			}
+			config.Aliases[alias] = key
+		}
+	}
+
+	return config
+}
+
+// suggestAliases returns the built-in synonyms that point at key, so the
+// written config documents the shorthands that already work
+func suggestAliases(key string) []string {
+	var aliases []string
+	for synonym, target := range newAliasResolver(nil).synonyms {
+		if target == key && synonym != key {
+			aliases = append(aliases, synonym)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// normalizeAppName lowercases a display name and strips everything but letters and digits
+func normalizeAppName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// scanMacApplications finds .app bundles in dirs and one folder level below
+// (e.g. "/Applications/Adobe Photoshop 2024/Adobe Photoshop 2024.app")
+func scanMacApplications(dirs []string) []ScannedApp {
+	var apps []ScannedApp
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if strings.HasSuffix(entry.Name(), ".app") {
+				apps = append(apps, ScannedApp{Name: strings.TrimSuffix(entry.Name(), ".app"), Path: path, Source: SourceFolder})
+				continue
+			}
+			if !entry.IsDir() {
+				continue
+			}
+			nested, err := os.ReadDir(path)
+			if err != nil {
+				continue
+			}
+			for _, n := range nested {
+				if strings.HasSuffix(n.Name(), ".app") {
+					apps = append(apps, ScannedApp{Name: strings.TrimSuffix(n.Name(), ".app"), Path: filepath.Join(path, n.Name()), Source: SourceFolder})
+				}
+			}
+		}
+	}
+	return apps
+}
+
+// scanDesktopEntries parses freedesktop .desktop files in dirs
+func scanDesktopEntries(dirs []string) []ScannedApp {
+	var apps []ScannedApp
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.desktop"))
+		for _, file := range matches {
+			if app, ok := parseDesktopEntry(file); ok {
+				apps = append(apps, app)
+			}
+		}
+	}
+	return apps
+}
+
+// parseDesktopEntry reads the Name and Exec keys of a visible application entry
+func parseDesktopEntry(file string) (ScannedApp, bool) {
+	entry, err := readDesktopEntry(file)
+	if err != nil {
+		return ScannedApp{}, false
+	}
+
+	name, execLine, entryType := entry["Name"], entry["Exec"], entry["Type"]
+	hidden := entry["NoDisplay"] == "true" || entry["Hidden"] == "true"
+
+	if hidden || name == "" || execLine == "" || (entryType != "" && entryType != "Application") {
+		return ScannedApp{}, false
+	}
+
+	fields := splitExecLine(execLine)
+	if len(fields) == 0 {
+		return ScannedApp{}, false
+	}
+	return ScannedApp{Name: name, Path: fields[0], Source: SourceDesktop}, true
+}
+
+// readDesktopEntry returns the keys of a .desktop file's [Desktop Entry]
+// group. Localized keys such as Name[de] are kept as written.
+func readDesktopEntry(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entry := map[string]string{}
+	inEntry := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, seen := entry[key]; !seen {
+			entry[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return entry, scanner.Err()
+}
+
+// scanWindowsPrograms looks for application executables under programDirs
+// and falls back to Start Menu shortcuts for anything not found there
+func scanWindowsPrograms(programDirs, startMenuDirs []string) []ScannedApp {
+	var apps []ScannedApp
+	seen := map[string]bool{}
+
+	for _, dir := range programDirs {
+		if dir == "" {
+			continue
+		}
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(dir, path)
+			depth := len(strings.Split(rel, string(filepath.Separator)))
+			if d.IsDir() {
+				if depth > 3 {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.EqualFold(filepath.Ext(path), ".exe") {
+				return nil
+			}
+
+			base := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+			if !isMainExecutable(base, path) {
+				return nil
+			}
+			if key := normalizeAppName(base); !seen[key] {
+				seen[key] = true
+				apps = append(apps, ScannedApp{Name: base, Path: path, Source: SourceFolder})
+			}
+			return nil
+		})
+	}
+
+	for _, dir := range startMenuDirs {
+		if dir == "" {
+			continue
+		}
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".lnk") {
+				return nil
+			}
+			name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+			if strings.Contains(strings.ToLower(name), "uninstall") {
+				return nil
+			}
+			if key := normalizeAppName(name); !seen[key] {
+				seen[key] = true
+				apps = append(apps, ScannedApp{Name: name, Path: path, Source: SourceStartMenu})
+			}
+			return nil
+		})
+	}
+
+	return apps
+}
+
+// isMainExecutable reports whether an exe looks like the primary binary of
+// its install folder rather than an updater or helper
+func isMainExecutable(base, path string) bool {
+	lower := strings.ToLower(base)
+	for _, skip := range []string{"unins", "update", "helper", "crash", "setup", "install"} {
+		if strings.Contains(lower, skip) {
+			return false
+		}
+	}
+
+	normalized := normalizeAppName(base)
+	parent := normalizeAppName(filepath.Base(filepath.Dir(path)))
+	grandparent := normalizeAppName(filepath.Base(filepath.Dir(filepath.Dir(path))))
+	if normalized == "" {
+		return false
+	}
+	return strings.Contains(parent, normalized) || strings.Contains(grandparent, normalized) ||
+		(parent != "" && strings.Contains(normalized, parent))
+}
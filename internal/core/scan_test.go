@@ -0,0 +1,158 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestScanMacApplications(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{
+		"Visual Studio Code.app",
+		"Adobe Photoshop 2024/Adobe Photoshop 2024.app",
+		"Utilities/Terminal.app",
+		"NotAnApp",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apps := scanMacApplications([]string{tmpDir, filepath.Join(tmpDir, "missing")})
+
+	names := map[string]bool{}
+	for _, app := range apps {
+		names[app.Name] = true
+	}
+	for _, want := range []string{"Visual Studio Code", "Adobe Photoshop 2024", "Terminal"} {
+		if !names[want] {
+			t.Errorf("scanMacApplications() missing %s, got %v", want, apps)
+		}
+	}
+	if len(apps) != 3 {
+		t.Errorf("scanMacApplications() returned %d apps, want 3", len(apps))
+	}
+}
+
+func TestScanDesktopEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	entries := map[string]string{
+		"firefox.desktop": "[Desktop Entry]\nType=Application\nName=Firefox\nExec=firefox %u\n\n[Desktop Action new-window]\nName=New Window\nExec=firefox --new-window\n",
+		"hidden.desktop":  "[Desktop Entry]\nType=Application\nName=Hidden\nExec=hidden\nNoDisplay=true\n",
+		"link.desktop":    "[Desktop Entry]\nType=Link\nName=Link\nURL=https://example.com\n",
+		"code.desktop":    "[Desktop Entry]\nName=Visual Studio Code\nExec=/usr/share/code/code --unity-launch %F\n",
+		"myapp.desktop":   "[Desktop Entry]\nType=Application\nName=My App\nExec=\"/opt/My App/app\" %U\n",
+	}
+	for name, content := range entries {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apps := scanDesktopEntries([]string{tmpDir})
+
+	paths := map[string]string{}
+	for _, app := range apps {
+		paths[app.Name] = app.Path
+	}
+	if len(apps) != 3 {
+		t.Errorf("scanDesktopEntries() returned %d apps, want 3: %v", len(apps), apps)
+	}
+	if paths["Firefox"] != "firefox" {
+		t.Errorf("Firefox path = %q, want firefox", paths["Firefox"])
+	}
+	if paths["Visual Studio Code"] != "/usr/share/code/code" {
+		t.Errorf("Visual Studio Code path = %q, want /usr/share/code/code", paths["Visual Studio Code"])
+	}
+	if paths["My App"] != "/opt/My App/app" {
+		t.Errorf("My App path = %q, want the quoted path whole", paths["My App"])
+	}
+}
+
+func TestBuildScannedConfig(t *testing.T) {
+	apps := []ScannedApp{
+		{Name: "Visual Studio Code", Path: "/opt/code"},
+		{Name: "Google Chrome", Path: "/opt/chrome"},
+		{Name: "Some Tool", Path: "/opt/tool"},
+		{Name: "Code", Path: "/opt/duplicate"},
+	}
+
+	config := buildScannedConfig(apps)
+
+	if len(config.Apps) != 3 {
+		t.Errorf("buildScannedConfig() produced %d apps, want 3", len(config.Apps))
+	}
+	if got := config.Apps["vscode"].Paths[runtime.GOOS]; got != "/opt/code" {
+		t.Errorf("vscode path = %q, want /opt/code", got)
+	}
+	if _, ok := config.Apps["sometool"]; !ok {
+		t.Error("buildScannedConfig() missing sometool")
+	}
+	if config.Aliases["gc"] != "chrome" {
+		t.Errorf("alias gc = %q, want chrome", config.Aliases["gc"])
+	}
+	if config.Aliases["code"] != "vscode" {
+		t.Errorf("alias code = %q, want vscode", config.Aliases["code"])
+	}
+}
+
+func TestBuildScannedConfigKillPatterns(t *testing.T) {
+	config := buildScannedConfig([]ScannedApp{{Name: "Some Tool", Path: "/opt/tool/some-tool"}})
+	app := config.Apps["sometool"]
+	if app == nil {
+		t.Fatal("buildScannedConfig() missing sometool")
+	}
+	if want := app.DeriveKillPatterns(); !reflect.DeepEqual(app.Kill, want) || len(want) == 0 {
+		t.Errorf("sometool kill = %q, want the derived %q", app.Kill, want)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   []int
+		ok     bool
+	}{
+		{"", nil, true},
+		{"2", []int{1}, true},
+		{"1 3-4", []int{0, 2, 3}, true},
+		{"1,3", []int{0, 2}, true},
+		{"0", nil, false},
+		{"5", nil, false},
+		{"4-2", nil, false},
+		{"chrome", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.answer, 4)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseSelection(%q) error = %v, want ok %v", tt.answer, err, tt.ok)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestDropScannedApp(t *testing.T) {
+	config := buildScannedConfig([]ScannedApp{
+		{Name: "Google Chrome", Path: "/opt/chrome"},
+		{Name: "Visual Studio Code", Path: "/opt/code"},
+	})
+	dropScannedApp(config, "chrome")
+
+	if _, ok := config.Apps["chrome"]; ok {
+		t.Error("dropScannedApp() kept chrome")
+	}
+	for alias, app := range config.Aliases {
+		if app == "chrome" {
+			t.Errorf("dropScannedApp() kept alias %s of chrome", alias)
+		}
+	}
+	if config.Aliases["code"] != "vscode" {
+		t.Errorf("dropScannedApp() lost the alias of vscode: %v", config.Aliases)
+	}
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Fields of an app a search matches in
+const (
+	SearchFieldName  = "name"
+	SearchFieldAlias = "alias"
+	SearchFieldPath  = "path"
+	SearchFieldTag   = "tag"
+)
+
+// SearchResult is an app matching a search, with what it matched in
+type SearchResult struct {
+	Name    string            `json:"name"`
+	Aliases []string          `json:"aliases,omitempty"` // config aliases and built-in synonyms
+	Paths   map[string]string `json:"paths,omitempty"`   // by OS
+	Tags    []string          `json:"tags,omitempty"`
+	Matched []string          `json:"matched"` // the Search* fields the query was found in
+}
+
+// Search finds the configured apps whose name, aliases, paths or tags
+// contain query, ignoring case
+func Search(query string) ([]SearchResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return searchApps(config, query), nil
+}
+
+// RunSearch prints the apps matching query with the matches highlighted
+func RunSearch(query string, jsonOutput bool) error {
+	results, err := Search(query)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No apps match %q\n", query)
+		return nil
+	}
+	for _, result := range results {
+		path := result.Paths[runtime.GOOS]
+		if path == "" {
+			path = ColorGray + "(no path for " + runtime.GOOS + ")" + ColorReset
+		}
+		fmt.Printf("%s  %s\n", highlight(result.Name, query), highlight(path, query))
+		if slices.Contains(result.Matched, SearchFieldAlias) {
+			fmt.Printf("  └─ aliases: %s\n", highlight(strings.Join(result.Aliases, ", "), query))
+		}
+		if slices.Contains(result.Matched, SearchFieldPath) {
+			// The match may be in the path for another OS
+			for _, goos := range slices.Sorted(maps.Keys(result.Paths)) {
+				if goos != runtime.GOOS && containsFold(result.Paths[goos], query) {
+					fmt.Printf("  └─ %s: %s\n", goos, highlight(result.Paths[goos], query))
+				}
+			}
+		}
+		if slices.Contains(result.Matched, SearchFieldTag) {
+			fmt.Printf("  └─ tags: %s\n", highlight(strings.Join(result.Tags, ", "), query))
+		}
+	}
+	return nil
+}
+
+// searchApps returns the apps in cfg matching query, those matching by
+// name first, each part sorted by name
+func searchApps(cfg *Config, query string) []SearchResult {
+	aliases := map[string][]string{}
+	for alias, app := range cfg.Aliases {
+		aliases[app] = append(aliases[app], alias)
+	}
+	for synonym, app := range newAliasResolver(cfg).synonyms {
+		if _, taken := cfg.Aliases[synonym]; !taken {
+			aliases[app] = append(aliases[app], synonym)
+		}
+	}
+
+	var byName, byOther []SearchResult
+	for _, name := range slices.Sorted(maps.Keys(cfg.Apps)) {
+		app := cfg.Apps[name]
+		result := SearchResult{Name: name, Aliases: slices.Sorted(slices.Values(aliases[name])), Paths: app.Paths, Tags: app.Tags}
+		if containsFold(name, query) {
+			result.Matched = append(result.Matched, SearchFieldName)
+		}
+		if slices.ContainsFunc(result.Aliases, func(alias string) bool { return containsFold(alias, query) }) {
+			result.Matched = append(result.Matched, SearchFieldAlias)
+		}
+		if slices.ContainsFunc(slices.Collect(maps.Values(app.Paths)), func(path string) bool { return containsFold(path, query) }) {
+			result.Matched = append(result.Matched, SearchFieldPath)
+		}
+		if slices.ContainsFunc(app.Tags, func(tag string) bool { return containsFold(tag, query) }) {
+			result.Matched = append(result.Matched, SearchFieldTag)
+		}
+
+		switch {
+		case len(result.Matched) == 0:
+		case result.Matched[0] == SearchFieldName:
+			byName = append(byName, result)
+		default:
+			byOther = append(byOther, result)
+		}
+	}
+	return append(byName, byOther...)
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// highlight colors every occurrence of query in s, ignoring case
+func highlight(s, query string) string {
+	if query == "" || ColorYellow == "" {
+		return s
+	}
+	lower, lowerQuery := strings.ToLower(s), strings.ToLower(query)
+	if len(lower) != len(s) {
+		// Lowercasing changed the byte offsets, match positions would be off
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, lowerQuery)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(lowerQuery)
+		b.WriteString(s[:i] + ColorYellow + s[i:end] + ColorReset)
+		s, lower = s[end:], lower[end:]
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchApps(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"intellij": {Paths: map[string]string{"linux": "/opt/jetbrains/idea.sh", "darwin": "/Applications/IntelliJ IDEA.app"}, Tags: []string{"jetbrains"}},
+			"webstorm": {Paths: map[string]string{"linux": "/opt/jetbrains/webstorm.sh"}},
+			"jetty":    {Paths: map[string]string{"linux": "/usr/bin/jetty"}},
+			"slack":    {Paths: map[string]string{"linux": "slack"}, Tags: []string{"chat"}},
+		},
+		Aliases: map[string]string{"chat": "slack"},
+	}
+
+	tests := []struct {
+		query string
+		want  map[string][]string // app -> fields matched, in result order
+		order []string
+	}{
+		{"jet", map[string][]string{
+			"jetty":    {SearchFieldName, SearchFieldPath},
+			"intellij": {SearchFieldPath, SearchFieldTag},
+			"webstorm": {SearchFieldPath},
+		}, []string{"jetty", "intellij", "webstorm"}},
+		{"CHAT", map[string][]string{"slack": {SearchFieldAlias, SearchFieldTag}}, []string{"slack"}},
+		{"idea", map[string][]string{"intellij": {SearchFieldAlias, SearchFieldPath}}, []string{"intellij"}}, // the built-in synonym
+		{"zzz", map[string][]string{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := searchApps(cfg, tt.query)
+			var order []string
+			got := map[string][]string{}
+			for _, result := range results {
+				order = append(order, result.Name)
+				got[result.Name] = result.Matched
+			}
+			if !reflect.DeepEqual(order, tt.order) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchApps(%q) = %v %v, want %v %v", tt.query, order, got, tt.order, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	saved := []string{ColorYellow, ColorReset}
+	defer func() { ColorYellow, ColorReset = saved[0], saved[1] }()
+	ColorYellow, ColorReset = "<", ">"
+
+	tests := []struct {
+		s, query, want string
+	}{
+		{"/opt/jetbrains/jet.sh", "jet", "/opt/<jet>brains/<jet>.sh"},
+		{"IntelliJ IDEA", "idea", "IntelliJ <IDEA>"},
+		{"slack", "zoom", "slack"},
+		{"slack", "", "slack"},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.s, tt.query); got != tt.want {
+			t.Errorf("highlight(%q, %q) = %q, want %q", tt.s, tt.query, got, tt.want)
+		}
+	}
+}
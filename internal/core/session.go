@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// CloseSession closes everything openx launched since the last call, most
+// recent first, and leaves other running apps alone. Apps started through
+// a launcher are closed by alias. Protected apps stay up, and recorded for
+// the next call, unless opts.Force is set.
+func CloseSession(opts KillOptions) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	launches := state.Launches
+	if len(launches) == 0 {
+		info("Nothing launched this session")
+		return nil
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultKillTimeout
+	}
+	if opts.Signal, err = parseSignal(opts.Signal); err != nil {
+		return err
+	}
+
+	// Teardown only touches what openx started, there is nothing to confirm
+	opts.Yes = true
+
+	config, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var kept []launchRecord
+	closed, errors := 0, 0
+	for i := len(launches) - 1; i >= 0; i-- {
+		launch := launches[i]
+
+		if _, app, err := lookupApp(config, launch.Alias); err == nil && app.Protected && !opts.Force {
+			info("Left protected app running: %s", launch.Alias)
+			kept = append([]launchRecord{launch}, kept...)
+			continue
+		}
+
+		var running bool
+		if launch.Launcher {
+			var report *KillReport
+			report, err = closeRunning(config, launch.Alias, opts)
+			running = report.Killed()
+		} else {
+			running, err = closeSessionProcess(launch, opts)
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", launch.Alias, err)
+			errors++
+		} else if running {
+			closed++
+		}
+	}
+
+	info("Closed %d of %d apps launched this session", closed, len(launches))
+	state.Launches = kept
+	if err := saveState(state); err != nil {
+		return fmt.Errorf("failed to clear session: %w", err)
+	}
+	if errors > 0 {
+		return fmt.Errorf("%d apps failed to close", errors)
+	}
+	return nil
+}
+
+// closeSessionProcess stops a launched process and its children by pid,
+// reporting whether it was still running
+func closeSessionProcess(launch launchRecord, opts KillOptions) (bool, error) {
+	processes, err := listProcesses()
+	if err != nil {
+		return false, err
+	}
+
+	root := recordedProcess(launch, processes)
+	if root == nil {
+		info("Already exited: %s", launch.Alias)
+		return false, nil
+	}
+
+	tree := processTree(processes, []processInfo{*root}, nil)
+	target := killTarget{
+		running: func() bool { return len(aliveProcesses(tree)) > 0 },
+		signal:  func(signal string) error { return signalProcesses("", slices.Sorted(maps.Keys(tree)), signal) },
+		force:   func() error { return killProcesses(tree) },
+	}
+	if _, err := stopTarget(target, launch.Alias, opts); err != nil {
+		return true, err
+	}
+	info("Killed %s (pid %d)", launch.Alias, launch.PID)
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCloseSession(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Uses kill signals on Linux")
+	}
+
+	// A script keeps its own name on the command line while it runs
+	script := filepath.Join(t.TempDir(), "sleeper")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nsleep 300\n"), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	testContent := `
+apps:
+  sleeper:
+    linux: "` + script + `"
+  noop:
+    linux: "/bin/true"`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := LaunchApp("noop", nil); err != nil {
+		t.Fatalf("LaunchApp(noop) unexpected error: %v", err)
+	}
+	if err := LaunchApp("sleeper", nil); err != nil {
+		t.Fatalf("LaunchApp(sleeper) unexpected error: %v", err)
+	}
+
+	state, err := loadState()
+	if err != nil || len(state.Launches) != 2 {
+		t.Fatalf("loadState() = %+v, %v, want both launches", state, err)
+	}
+	sleeper := state.Launches[1]
+	if sleeper.Alias != "sleeper" || sleeper.Name != "sleeper" || sleeper.PID == 0 || sleeper.Launcher {
+		t.Errorf("recorded launch = %+v, want sleeper's pid", sleeper)
+	}
+
+	// The launched process is openx's child here; reap it once killed
+	process, _ := os.FindProcess(sleeper.PID)
+	exited := make(chan struct{})
+	go func() {
+		process.Wait()
+		close(exited)
+	}()
+
+	if err := CloseSession(KillOptions{Timeout: time.Second}); err != nil {
+		t.Fatalf("CloseSession() unexpected error: %v", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		process.Kill()
+		t.Fatal("session app still running after CloseSession()")
+	}
+
+	if state, err := loadState(); err != nil || len(state.Launches) != 0 {
+		t.Errorf("CloseSession() should clear the recorded launches, got %+v, %v", state, err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 )
 
 // EnsureConfig ensures that the configuration file exists, creating it if necessary
@@ -17,31 +19,68 @@ func EnsureConfig() error {
 	}
 
 	fmt.Printf("Config not found. Creating starter config at %s\n", configPath)
-	return createStarterConfig(configPath)
+	if err := createStarterConfig(configPath, getStarterTemplate()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created starter config with common %s applications.\n", runtime.GOOS)
+	fmt.Printf("Edit %s to customize your environment.\n", configPath)
+	return nil
 }
 
-// createStarterConfig creates a starter configuration file for the current OS
-func createStarterConfig(configPath string) error {
+// InitConfig writes a starter config seeded from the named template.
+// An existing config is only replaced when force is set.
+func InitConfig(template string, force bool) error {
+	getTemplate, ok := starterTemplates[strings.ToLower(template)]
+	if !ok {
+		return fmt.Errorf("unknown template: %s (available: %s)", template, strings.Join(TemplateNames(), ", "))
+	}
+
+	configPath := getConfigPath()
+	if exists(configPath) && !force {
+		return fmt.Errorf("config already exists at %s (use --force to overwrite)", configPath)
+	}
+
+	if err := createStarterConfig(configPath, getTemplate()); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s starter config at %s\n", strings.ToLower(template), configPath)
+	fmt.Printf("Edit %s to customize your environment.\n", configPath)
+	return nil
+}
+
+// TemplateNames returns the names of the available starter templates
+func TemplateNames() []string {
+	names := make([]string, 0, len(starterTemplates))
+	for name := range starterTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// createStarterConfig writes the given template to configPath
+func createStarterConfig(configPath, template string) error {
 	// Ensure the config directory exists
 	configDir := filepath.Dir(configPath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Get the starter config template for this OS
-	template := getStarterTemplate()
-
 	// Write the config file
 	if err := os.WriteFile(configPath, []byte(template), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	fmt.Printf("Created starter config with common %s applications.\n", runtime.GOOS)
-	fmt.Printf("Edit %s to customize your environment.\n", configPath)
-
 	return nil
 }
 
+// ConfigPath returns the path of the configuration file openx reads
+func ConfigPath() string {
+	return getConfigPath()
+}
+
 // getConfigPath returns the path to the configuration file
 func getConfigPath() string {
 	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
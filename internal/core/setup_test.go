@@ -0,0 +1,70 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStarterTemplates_Parse(t *testing.T) {
+	for _, name := range TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			var config Config
+			if err := yaml.Unmarshal([]byte(starterTemplates[name]()), &config); err != nil {
+				t.Fatalf("template %s is not valid YAML: %v", name, err)
+			}
+
+			if len(config.Apps) == 0 {
+				t.Errorf("template %s has no apps", name)
+			}
+
+			for alias, target := range config.Aliases {
+				if _, ok := config.Apps[target]; !ok {
+					t.Errorf("template %s alias %s points to unknown app %s", name, alias, target)
+				}
+			}
+		})
+	}
+}
+
+func TestInitConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "openx", "config.yaml")
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	if err := InitConfig("backend", false); err != nil {
+		t.Fatalf("InitConfig() unexpected error: %v", err)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() failed: %v", err)
+	}
+	if _, ok := config.Apps["datagrip"]; !ok {
+		t.Error("backend template should include datagrip")
+	}
+
+	// Existing config must not be overwritten without force
+	if err := InitConfig("designer", false); err == nil {
+		t.Error("InitConfig() expected error when config exists")
+	}
+
+	if err := InitConfig("designer", true); err != nil {
+		t.Fatalf("InitConfig() with force unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if !contains(string(data), "designer template") {
+		t.Error("config was not replaced by the designer template")
+	}
+
+	if err := InitConfig("unknown", true); err == nil {
+		t.Error("InitConfig() expected error for unknown template")
+	}
+}
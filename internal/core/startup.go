@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// startupOutputLines is how much captured output a LaunchFailure shows
+const startupOutputLines = 10
+
+// LaunchFailure reports an app that exited or showed no window during its
+// startup check
+type LaunchFailure struct {
+	Alias    string
+	Reason   string
+	ExitCode int    // -1 when the app is still running or was killed
+	Output   string // last lines of the app's captured output
+}
+
+func (e *LaunchFailure) Error() string {
+	msg := fmt.Sprintf("%s failed to start: %s", e.Alias, e.Reason)
+	if e.Output != "" {
+		msg += "\n--- last output ---\n" + e.Output
+	}
+	return msg
+}
+
+// checkStartup watches a freshly started app for opts.StartupCheck. Apps
+// that exit with an error, or have no window when the time is up, fail.
+// Launchers like open -a exit at once, so a clean exit only fails the
+// window check. offset is where the app's output starts in its log.
+func checkStartup(alias string, cmd *exec.Cmd, opts LaunchOptions, offset int64) error {
+	started := time.Now()
+	deadline := started.Add(opts.StartupCheck)
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			failure := &LaunchFailure{Alias: alias, ExitCode: -1, Output: logTail(alias, offset, startupOutputLines)}
+			elapsed := time.Since(started).Round(100 * time.Millisecond)
+
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+				failure.ExitCode = exitErr.ExitCode()
+				failure.Reason = fmt.Sprintf("exited with code %d after %s", failure.ExitCode, elapsed)
+			} else {
+				failure.Reason = fmt.Sprintf("stopped after %s: %v", elapsed, err)
+			}
+			return failure
+		}
+		sleep(time.Until(deadline))
+	case <-time.After(opts.StartupCheck):
+	}
+
+	if len(opts.windowPatterns) == 0 {
+		return nil
+	}
+	// Without a way to list windows the check can't tell, so it passes
+	if found, err := hasWindow(opts.windowPatterns); err == nil && !found {
+		return &LaunchFailure{
+			Alias:    alias,
+			Reason:   fmt.Sprintf("no window appeared within %s", opts.StartupCheck),
+			ExitCode: -1,
+			Output:   logTail(alias, offset, startupOutputLines),
+		}
+	}
+	return nil
+}
+
+// logTail returns the last lines the app wrote to its log after offset
+func logTail(alias string, offset int64, lines int) string {
+	f, err := os.Open(getLogPath(alias))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, 1<<62))
+	if err != nil {
+		return ""
+	}
+
+	all := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.TrimSpace(strings.Join(all, "\n"))
+}
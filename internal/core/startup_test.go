@@ -0,0 +1,59 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckStartup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping sh tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	tests := []struct {
+		name     string
+		script   string
+		wantCode int // 0 when the check should pass
+		wantOut  string
+	}{
+		{"crash reports exit code and output", "echo starting; echo boom >&2; exit 3", 3, "starting\nboom"},
+		{"still running passes", "sleep 2", 0, ""},
+		{"clean exit passes without window check", "exit 0", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := LaunchOptions{Log: true, StartupCheck: 300 * time.Millisecond}
+			// The offset comes first, the script may write before startApp returns
+			offset := logOffset("check")
+			cmd, err := startApp("check", "/bin/sh", []string{"-c", tt.script}, opts)
+			if err != nil {
+				t.Fatalf("startApp() unexpected error: %v", err)
+			}
+			defer cmd.Process.Kill()
+
+			err = checkStartup("check", cmd, opts, offset)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Errorf("checkStartup() unexpected error: %v", err)
+				}
+				return
+			}
+
+			var failure *LaunchFailure
+			if !errors.As(err, &failure) {
+				t.Fatalf("checkStartup() error = %v, want a LaunchFailure", err)
+			}
+			if failure.ExitCode != tt.wantCode || failure.Output != tt.wantOut {
+				t.Errorf("checkStartup() = code %d, output %q; want code %d, output %q", failure.ExitCode, failure.Output, tt.wantCode, tt.wantOut)
+			}
+			if !strings.Contains(err.Error(), "exited with code 3") {
+				t.Errorf("LaunchFailure.Error() = %q, want the exit code", err.Error())
+			}
+		})
+	}
+}
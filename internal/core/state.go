@@ -0,0 +1,162 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLaunchRecords bounds the state file; the oldest launches are dropped
+const maxLaunchRecords = 200
+
+// launchRecord is an app launch openx remembers in its state file
+type launchRecord struct {
+	Alias string   `json:"alias"`
+	PID   int      `json:"pid,omitempty"`
+	Name  string   `json:"name,omitempty"` // executable name, checked so reused pids are left alone
+	Args  []string `json:"args,omitempty"` // arguments given on the command line
+
+	// Launcher is set when the pid belongs to a launcher like open -a or
+	// gtk-launch, or there is no pid (docker): the app is found by alias
+	Launcher bool      `json:"launcher,omitempty"`
+	Started  time.Time `json:"started"`
+}
+
+// launchState is the content of the state file
+type launchState struct {
+	Launches []launchRecord `json:"launches"` // oldest first
+
+	// CPUSamples track each running app's CPU time for --kill-idle
+	CPUSamples map[string][]cpuSample `json:"cpuSamples,omitempty"`
+
+	// DoctorRuns are the latest doctor runs for --doctor --diff, oldest first
+	DoctorRuns []doctorRun `json:"doctorRuns,omitempty"`
+
+	// Recent are the latest distinct launches for openx recent, oldest first
+	Recent []RecentLaunch `json:"recent,omitempty"`
+}
+
+// stateMu serializes state file updates from concurrent group launches
+var stateMu sync.Mutex
+
+// launcherCommands start an app and exit, so their pid isn't the app's
+var launcherCommands = map[string]bool{
+	"open": true, "gtk-launch": true, "gio": true, "xdg-open": true,
+	"explorer.exe": true, "explorer": true, "rundll32": true, "osascript": true,
+}
+
+// getStatePath returns the file recording what openx launched
+func getStatePath() string {
+	return filepath.Join(getStateDir(), "state.json")
+}
+
+// loadState reads the state file. A missing file is an empty state.
+func loadState() (*launchState, error) {
+	state := &launchState{}
+	data, err := os.ReadFile(getStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", getStatePath(), err)
+	}
+	return state, nil
+}
+
+// saveState replaces the state file atomically, so a crash or a second
+// openx writing at the same time never leaves half a file behind
+func saveState(state *launchState) error {
+	path := getStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// updateState loads the state, applies update and saves the result
+func updateState(update func(state *launchState) error) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	if err := update(state); err != nil {
+		return err
+	}
+	return saveState(state)
+}
+
+// recordLaunch adds a launched app to the state file. A failure only means
+// later commands won't know about the launch, so it is a warning.
+func recordLaunch(alias string, args []string, cmd *exec.Cmd) {
+	record := launchRecord{Alias: alias, Args: args, Launcher: true, Started: time.Now()}
+	if cmd != nil && cmd.Process != nil {
+		name := filepath.Base(cmd.Path)
+		record.PID = cmd.Process.Pid
+		record.Name = name
+		record.Launcher = launcherCommands[strings.ToLower(name)]
+	}
+
+	err := updateState(func(state *launchState) error {
+		state.Launches = append(state.Launches, record)
+		if extra := len(state.Launches) - maxLaunchRecords; extra > 0 {
+			state.Launches = state.Launches[extra:]
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: launch of %s not recorded: %v\n", alias, err)
+	}
+}
+
+// recordedProcess returns the process a record's pid still runs, or nil
+// when it exited or the pid now belongs to another program
+func recordedProcess(record launchRecord, processes []processInfo) *processInfo {
+	if record.PID == 0 {
+		return nil
+	}
+	for i, p := range processes {
+		if p.PID == record.PID && recordedProcessMatches(p, record.Name) {
+			return &processes[i]
+		}
+	}
+	return nil
+}
+
+// recordedProcessMatches checks that a pid still runs the recorded program
+func recordedProcessMatches(p processInfo, name string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(p.Name, name)
+	}
+	return strings.Contains(strings.ToLower(p.Command), strings.ToLower(name))
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+func TestRecordLaunch(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	recordLaunch("docs", []string{"notes.pdf"}, exec.Command("open", "-a", "Preview"))
+	recordLaunch("postgres", nil, nil)
+
+	state, err := loadState()
+	if err != nil || len(state.Launches) != 2 {
+		t.Fatalf("loadState() = %+v, %v", state, err)
+	}
+	for _, launch := range state.Launches {
+		if !launch.Launcher {
+			t.Errorf("launch %s should be found by alias", launch.Alias)
+		}
+		if launch.Started.IsZero() {
+			t.Errorf("launch %s should record its start time", launch.Alias)
+		}
+	}
+	if fmt.Sprint(state.Launches[0].Args) != "[notes.pdf]" {
+		t.Errorf("recorded args = %v, want [notes.pdf]", state.Launches[0].Args)
+	}
+}
+
+func TestRecordLaunch_Bounded(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	for i := range maxLaunchRecords + 5 {
+		recordLaunch(fmt.Sprintf("app%d", i), nil, nil)
+	}
+
+	state, err := loadState()
+	if err != nil || len(state.Launches) != maxLaunchRecords {
+		t.Fatalf("loadState() has %d launches, %v, want %d", len(state.Launches), err, maxLaunchRecords)
+	}
+	if state.Launches[0].Alias != "app5" {
+		t.Errorf("oldest kept launch = %s, want app5", state.Launches[0].Alias)
+	}
+}
+
+func TestRecordedProcess(t *testing.T) {
+	processes := []processInfo{
+		{PID: 40, PPID: 1, Name: "slack", Command: "/usr/lib/slack/slack"},
+		{PID: 41, PPID: 1, Name: "bash", Command: "bash"},
+	}
+
+	tests := []struct {
+		name   string
+		record launchRecord
+		want   int
+	}{
+		{"still running", launchRecord{PID: 40, Name: "slack"}, 40},
+		{"pid reused", launchRecord{PID: 41, Name: "slack"}, 0},
+		{"exited", launchRecord{PID: 42, Name: "slack"}, 0},
+		{"no pid", launchRecord{Launcher: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			if p := recordedProcess(tt.record, processes); p != nil {
+				got = p.PID
+			}
+			if got != tt.want {
+				t.Errorf("recordedProcess() = pid %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
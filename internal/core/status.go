@@ -0,0 +1,172 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Group states of a GroupStatus
+const (
+	GroupStateRunning = "running" // every member is running
+	GroupStatePartial = "partial" // some members are running
+	GroupStateStopped = "stopped" // no member is running
+)
+
+// StatusReport is a glance at the environment: the config, how many apps
+// are there and running, which groups are up and what doctor would flag
+type StatusReport struct {
+	Platform   string        `json:"platform"`
+	ConfigPath string        `json:"configPath"`
+	Summary    Summary       `json:"summary"`
+	Aliases    int           `json:"aliases"`
+	Running    []string      `json:"running"` // names of the running apps
+	Groups     []GroupStatus `json:"groups"`
+	Problems   []string      `json:"problems"` // doctor's problems, one line each
+}
+
+// GroupStatus is how much of a workspace group is running
+type GroupStatus struct {
+	Name    string   `json:"name"`
+	State   string   `json:"state"` // one of the GroupState values
+	Running []string `json:"running,omitempty"`
+	Stopped []string `json:"stopped,omitempty"`
+}
+
+// BuildStatus combines the doctor report and the running apps into a
+// StatusReport
+func BuildStatus() (*StatusReport, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	report, err := BuildDoctorReport(false)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &StatusReport{
+		Platform:   report.Platform,
+		ConfigPath: report.ConfigPath,
+		Summary:    report.Summary,
+		Aliases:    len(config.Aliases),
+		Running:    []string{},
+		Groups:     []GroupStatus{},
+	}
+
+	running := map[string]bool{}
+	for _, app := range report.Apps {
+		if app.Running {
+			running[app.Name] = true
+			status.Running = append(status.Running, app.Name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Groups)) {
+		group := GroupStatus{Name: name}
+		for _, member := range config.Groups[name] {
+			app, _, err := lookupApp(config, member.App)
+			if err == nil && running[app] {
+				group.Running = append(group.Running, member.App)
+			} else {
+				group.Stopped = append(group.Stopped, member.App)
+			}
+		}
+		switch {
+		case len(group.Stopped) == 0:
+			group.State = GroupStateRunning
+		case len(group.Running) == 0:
+			group.State = GroupStateStopped
+		default:
+			group.State = GroupStatePartial
+		}
+		status.Groups = append(status.Groups, group)
+	}
+
+	status.Problems = statusProblems(doctorProblems(*report))
+	return status, nil
+}
+
+// statusProblems describes each problem of a problems-only doctor report
+// in a line
+func statusProblems(report DoctorReport) []string {
+	problems := []string{}
+	for _, app := range report.Apps {
+		switch app.Status {
+		case "missing":
+			problems = append(problems, fmt.Sprintf("%s is missing: %s", app.Name, app.LaunchPath))
+		case "no-path":
+			problems = append(problems, fmt.Sprintf("%s has no path for %s", app.Name, report.Platform))
+		}
+		if app.LaunchIssue != "" {
+			problems = append(problems, fmt.Sprintf("%s can't launch: %s", app.Name, app.LaunchIssue))
+		}
+		if app.ArchMismatch != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", app.Name, app.ArchMismatch))
+		}
+	}
+	for _, issue := range report.DanglingAliases {
+		problems = append(problems, fmt.Sprintf("alias %s → %s: %s", issue.Alias, issue.Target, issue.Problem))
+	}
+	for _, conflict := range report.Conflicts {
+		problems = append(problems, conflict.Detail)
+	}
+	return problems
+}
+
+// RunStatus prints the status of the environment on one screen
+func RunStatus(jsonOutput bool) error {
+	status, err := BuildStatus()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(status)
+	}
+
+	fmt.Printf("openx status (%s)\n", status.Platform)
+	fmt.Printf("Config: %s\n\n", status.ConfigPath)
+
+	summary := status.Summary
+	fmt.Printf("Apps: %d configured, %d available", summary.Total, summary.Available)
+	if summary.Missing > 0 {
+		fmt.Printf(", %s%d missing%s", ColorRed, summary.Missing, ColorReset)
+	}
+	fmt.Printf(", %d aliases\n", status.Aliases)
+	if len(status.Running) > 0 {
+		fmt.Printf("Running: %s%s%s\n", ColorGreen, strings.Join(status.Running, ", "), ColorReset)
+	} else {
+		fmt.Println("Running: none")
+	}
+
+	if len(status.Groups) > 0 {
+		fmt.Println("\nGroups:")
+	}
+	for _, group := range status.Groups {
+		total := len(group.Running) + len(group.Stopped)
+		switch group.State {
+		case GroupStateRunning:
+			fmt.Printf("  %s● %-15s running (%d/%d)%s\n", ColorGreen, group.Name, total, total, ColorReset)
+		case GroupStatePartial:
+			fmt.Printf("  %s◐ %-15s partial (%d/%d), not running: %s%s\n", ColorYellow, group.Name, len(group.Running), total, strings.Join(group.Stopped, ", "), ColorReset)
+		default:
+			fmt.Printf("  %s○ %-15s stopped%s\n", ColorGray, group.Name, ColorReset)
+		}
+	}
+
+	if len(status.Problems) == 0 {
+		fmt.Printf("\n%sNo problems found%s\n", ColorGreen, ColorReset)
+		return nil
+	}
+	fmt.Printf("\nProblems (openx doctor for details):\n")
+	for _, problem := range status.Problems {
+		fmt.Printf("  %s✗ %s%s\n", ColorRed, problem, ColorReset)
+	}
+	return nil
+}
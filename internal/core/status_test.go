@@ -0,0 +1,79 @@
+package core
+
+import (
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestBuildStatus(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping process tests on Windows")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// A process of its own for the running app to match
+	sleeper := exec.Command("sleep", "47.125")
+	if err := sleeper.Start(); err != nil {
+		t.Skipf("sleep unavailable: %v", err)
+	}
+	defer func() {
+		sleeper.Process.Kill()
+		sleeper.Wait()
+	}()
+
+	testContent := `
+apps:
+  up:
+    linux: sleep
+    darwin: sleep
+    kill: ["sleep 47.125"]
+  down:
+    linux: "true"
+    darwin: "true"
+    kill: ["openx-status-test-nothing"]
+  gone:
+    linux: /nonexistent/app
+    darwin: /nonexistent/app
+    kill: ["openx-status-test-nothing"]
+aliases:
+  u: up
+  dead: nothing
+groups:
+  all: [u]
+  some: [up, down]
+  none: [down, gone]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	status, err := BuildStatus()
+	if err != nil {
+		t.Fatalf("BuildStatus() unexpected error: %v", err)
+	}
+	if status.Summary.Total != 3 || status.Summary.Missing != 1 || status.Aliases != 2 {
+		t.Errorf("BuildStatus() summary %+v with %d aliases, want 3 apps, 1 missing, 2 aliases", status.Summary, status.Aliases)
+	}
+	if !reflect.DeepEqual(status.Running, []string{"up"}) {
+		t.Errorf("BuildStatus() running = %v, want [up]", status.Running)
+	}
+
+	want := []GroupStatus{
+		{Name: "all", State: GroupStateRunning, Running: []string{"u"}},
+		{Name: "none", State: GroupStateStopped, Stopped: []string{"down", "gone"}},
+		{Name: "some", State: GroupStatePartial, Running: []string{"up"}, Stopped: []string{"down"}},
+	}
+	if !reflect.DeepEqual(status.Groups, want) {
+		t.Errorf("BuildStatus() groups = %+v, want %+v", status.Groups, want)
+	}
+
+	problems := []string{
+		"gone is missing: /nonexistent/app",
+		"alias dead → nothing: points to unknown app nothing",
+	}
+	if !reflect.DeepEqual(status.Problems, problems) {
+		t.Errorf("BuildStatus() problems = %q, want %q", status.Problems, problems)
+	}
+}
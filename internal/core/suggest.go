@@ -0,0 +1,236 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestions caps how many names a did-you-mean hint lists
+const maxSuggestions = 3
+
+// SuggestAliases returns the configured names closest to an unknown alias
+func SuggestAliases(alias string) ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return closestNames(config, alias), nil
+}
+
+// UnknownNameError is returned for a name the config doesn't define
+type UnknownNameError struct {
+	Kind        string // app, alias, group or project
+	Name        string
+	Suggestions []string // the closest names of that kind, closest first
+}
+
+func (e *UnknownNameError) Error() string {
+	return fmt.Sprintf("unknown %s: %s%s", e.Kind, e.Name, didYouMean(e.Suggestions))
+}
+
+// Is matches the ErrUnknown error of the name's kind
+func (e *UnknownNameError) Is(target error) bool {
+	switch e.Kind {
+	case ListKindApp:
+		return target == ErrUnknownApp
+	case ListKindAlias:
+		return target == ErrUnknownAlias
+	case ListKindGroup:
+		return target == ErrUnknownGroup
+	case ListKindProject:
+		return target == ErrUnknownProject
+	}
+	return target == ErrUnknownApp || target == ErrUnknownGroup
+}
+
+// UnknownName is the error for a name cfg has no kind of, an app, alias,
+// group or project, hinting at the closest names of that kind. Anything
+// else, as an app, is looked for among everything that launches.
+func UnknownName(cfg *Config, kind, name string) error {
+	var known []string
+	switch kind {
+	case ListKindAlias:
+		known = slices.Collect(maps.Keys(cfg.Aliases))
+	case ListKindGroup:
+		known = slices.Collect(maps.Keys(cfg.Groups))
+	case ListKindProject:
+		known = slices.Collect(maps.Keys(cfg.Projects))
+	default:
+		known = slices.Collect(maps.Keys(knownNames(cfg)))
+	}
+	return &UnknownNameError{Kind: kind, Name: name, Suggestions: closest(name, known)}
+}
+
+// closestNames returns the app, alias, group and synonym names within a
+// small edit distance of name, or starting with it, closest first
+func closestNames(cfg *Config, name string) []string {
+	return closest(name, slices.Collect(maps.Keys(knownNames(cfg))))
+}
+
+// closest returns the candidates within a small edit distance of name,
+// or starting with it, closest first
+func closest(name string, candidates []string) []string {
+	name = strings.ToLower(name)
+	if name == "" {
+		return nil
+	}
+
+	// Allow about one typo per three characters, and at least one
+	maxDistance := max(1, len(name)/3)
+
+	distances := map[string]int{}
+	for _, candidate := range candidates {
+		distance := levenshtein(name, strings.ToLower(candidate))
+		prefix := len(name) >= 3 && strings.HasPrefix(strings.ToLower(candidate), name)
+		if candidate != name && (distance <= maxDistance || prefix) {
+			distances[candidate] = distance
+		}
+	}
+
+	suggestions := make([]string, 0, len(distances))
+	for candidate := range distances {
+		suggestions = append(suggestions, candidate)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// knownNames maps every name that launches something to the app or group
+// it launches: apps, groups, and the aliases and synonyms of configured
+// apps
+func knownNames(cfg *Config) map[string]string {
+	names := map[string]string{}
+	for app := range cfg.Apps {
+		names[app] = app
+	}
+	for alias, app := range cfg.Aliases {
+		if _, ok := cfg.Apps[app]; ok {
+			names[alias] = app
+		}
+	}
+	for group := range cfg.Groups {
+		names[group] = group
+	}
+	// Synonyms only count when the app they stand for is configured
+	for synonym, app := range newAliasResolver(cfg).synonyms {
+		if _, ok := cfg.Apps[app]; ok {
+			if _, taken := names[synonym]; !taken {
+				names[synonym] = app
+			}
+		}
+	}
+	return names
+}
+
+// ResolveFuzzy finds the app or group a mistyped name means, as in chrm
+// for chrome. One match is taken, with a note on stderr; several are
+// offered to choose from on a terminal.
+func ResolveFuzzy(name string) (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	matches := fuzzyMatches(config, name)
+	switch len(matches) {
+	case 0:
+		return "", UnknownName(config, "alias or group", name)
+	case 1:
+		fmt.Fprintf(os.Stderr, "%s → %s\n", name, matches[0])
+		return matches[0], nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s could be:\n", name)
+	for i, match := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, match)
+	}
+	answer := prompt(fmt.Sprintf("Which one? [1-%d]", len(matches)))
+	if choice, err := strconv.Atoi(answer); err == nil && choice >= 1 && choice <= len(matches) {
+		return matches[choice-1], nil
+	}
+	return "", fmt.Errorf("%q is ambiguous%s", name, didYouMean(matches))
+}
+
+// fuzzyMatches returns the apps and groups name could mean, best first:
+// those with a known name within a typo or two of it, or holding its
+// letters in order, as vscod and chrm do. One match a single typo away
+// stands alone.
+func fuzzyMatches(cfg *Config, name string) []string {
+	name = strings.ToLower(name)
+	if name == "" {
+		return nil
+	}
+	maxDistance := max(1, len(name)/3)
+
+	// The closest of the names leading to each app or group ranks it
+	distances := map[string]int{}
+	for known, target := range knownNames(cfg) {
+		distance := levenshtein(name, strings.ToLower(known))
+		_, inOrder := fuzzyScore(name, known)
+		if distance > maxDistance && !(inOrder && len([]rune(name)) >= 3) {
+			continue
+		}
+		if best, seen := distances[target]; !seen || distance < best {
+			distances[target] = distance
+		}
+	}
+
+	matches := slices.Collect(maps.Keys(distances))
+	sort.Slice(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+
+	// A single match one typo away is what was meant
+	if len(matches) > 1 && distances[matches[0]] <= 1 && distances[matches[1]] > 1 {
+		return matches[:1]
+	}
+	return matches
+}
+
+// didYouMean formats suggestions as a hint to append to an error
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean: %s?)", strings.Join(suggestions, ", "))
+}
+
+// levenshtein returns the number of single-character edits that turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"chrome", "chrome", 0},
+		{"chrme", "chrome", 1},
+		{"chrome", "chromium", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosestNames(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"chrome":   {},
+			"chromium": {},
+			"firefox":  {},
+			"slack":    {},
+		},
+		Aliases: map[string]string{"browser": "firefox", "dangling": "missing"},
+		Groups:  map[string][]GroupMember{"morning": nil},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"chrom", "chrome, chromium"},
+		{"chrme", "chrome"},
+		{"CHROMEE", "chrome"},
+		{"browsr", "browser"},
+		{"mornin", "morning"},
+		{"fx", "ff"}, // synonym of a configured app
+		{"dangle", ""},
+		{"zzzzzz", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(closestNames(cfg, tt.name), ", "); got != tt.want {
+				t.Errorf("closestNames(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupApp_Suggestions(t *testing.T) {
+	cfg := &Config{Apps: map[string]*App{"chrome": {}}}
+
+	_, _, err := lookupApp(cfg, "chrme")
+	if err == nil || err.Error() != "unknown app: chrme (did you mean: chrome?)" {
+		t.Errorf("lookupApp(chrme) error = %v, want a did-you-mean hint", err)
+	}
+}
+
+func TestFuzzyMatches(t *testing.T) {
+	cfg := &Config{
+		Apps: map[string]*App{
+			"chrome":   {},
+			"chromium": {},
+			"vscode":   {},
+			"slack":    {},
+		},
+		Aliases: map[string]string{"editor": "vscode"},
+		Groups:  map[string][]GroupMember{"morning": nil},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"vscod", "vscode"},
+		{"edtor", "vscode"},          // through its alias
+		{"chrm", "chrome, chromium"}, // ambiguous
+		{"chrmium", "chromium"},
+		{"chromum", "chromium"}, // one typo beats chrome's two
+		{"slk", "slack"},
+		{"mrning", "morning"},
+		{"ck", ""}, // too short to match letters in order
+		{"zzz", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(fuzzyMatches(cfg, tt.name), ", "); got != tt.want {
+				t.Errorf("fuzzyMatches(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveFuzzy(t *testing.T) {
+	configPath := setupTestConfig(t, `
+apps:
+  chrome:
+    linux: google-chrome
+  chromium:
+    linux: chromium`)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	answer := ""
+	oldPrompt := prompt
+	prompt = func(string) string { return answer }
+	defer func() { prompt = oldPrompt }()
+
+	tests := []struct {
+		name, answer string
+		want         string
+		wantErr      string
+	}{
+		{"chromum", "", "chromium", ""},
+		{"chrm", "2", "chromium", ""},
+		{"chrm", "", "", "ambiguous"},
+		{"chrm", "3", "", "ambiguous"},
+		{"zzz", "", "", "unknown alias or group"},
+	}
+	for _, tt := range tests {
+		answer = tt.answer
+		got, err := ResolveFuzzy(tt.name)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveFuzzy(%q) with %q error = %v, want %q", tt.name, tt.answer, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveFuzzy(%q) with %q = %q, %v, want %q", tt.name, tt.answer, got, err, tt.want)
+		}
+	}
+}
+
+func TestUnknownName(t *testing.T) {
+	cfg := &Config{
+		Apps:     map[string]*App{"chrome": {}, "slack": {}},
+		Aliases:  map[string]string{"browser": "chrome"},
+		Groups:   map[string][]GroupMember{"morning": nil, "evening": nil},
+		Projects: map[string]Project{"webapp": {}},
+	}
+
+	tests := []struct {
+		kind, name string
+		want       string
+	}{
+		{ListKindApp, "chrme", "unknown app: chrme (did you mean: chrome?)"},
+		{ListKindApp, "mornin", "unknown app: mornin (did you mean: morning?)"},
+		{ListKindGroup, "evenin", "unknown group: evenin (did you mean: evening?)"},
+		{ListKindGroup, "chrme", "unknown group: chrme"}, // apps aren't groups
+		{ListKindAlias, "browsr", "unknown alias: browsr (did you mean: browser?)"},
+		{"project", "webap", "unknown project: webap (did you mean: webapp?)"},
+		{ListKindApp, "zzzzzz", "unknown app: zzzzzz"},
+	}
+	for _, tt := range tests {
+		if got := UnknownName(cfg, tt.kind, tt.name).Error(); got != tt.want {
+			t.Errorf("UnknownName(%s, %q) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package core
+
+// starterTemplates maps template names accepted by InitConfig to their content
+var starterTemplates = map[string]func() string{
+	"default":  getStarterTemplate,
+	"frontend": getFrontendTemplate,
+	"backend":  getBackendTemplate,
+	"devops":   getDevOpsTemplate,
+	"designer": getDesignerTemplate,
+}
+
+// getFrontendTemplate returns the starter config for frontend developers
+func getFrontendTemplate() string {
+	return `# openx configuration - frontend template
+# Edit this file to customize your development environment
+
+apps:
+  # Code Editors & IDEs
+  vscode:
+    darwin: "/Applications/Visual Studio Code.app"
+    linux: "code"
+    windows: "Code.exe"
+
+  webstorm:
+    darwin: "/Applications/WebStorm.app"
+    linux: "webstorm"
+    windows: "webstorm64.exe"
+
+  # Browsers
+  chrome:
+    darwin: "/Applications/Google Chrome.app"
+    linux: "google-chrome"
+    windows: "chrome.exe"
+
+  firefox:
+    darwin: "/Applications/Firefox.app"
+    linux: "firefox"
+    windows: "firefox.exe"
+
+  edge:
+    darwin: "/Applications/Microsoft Edge.app"
+    linux: "microsoft-edge"
+    windows: "msedge.exe"
+
+  safari:
+    darwin: "Safari"
+
+  # Design & API Tools
+  figma:
+    darwin: "/Applications/Figma.app"
+    linux: "figma-linux"
+    windows: "Figma.exe"
+
+  postman:
+    darwin: "/Applications/Postman.app"
+    linux: "postman"
+    windows: "Postman.exe"
+
+  # Communication
+  slack:
+    darwin: "/Applications/Slack.app"
+    linux: "slack"
+    windows: "slack.exe"
+
+aliases:
+  code: vscode
+  ws: webstorm
+  gc: chrome
+  ff: firefox
+  fig: figma
+  pm: postman
+`
+}
+
+// getBackendTemplate returns the starter config for backend developers
+func getBackendTemplate() string {
+	return `# openx configuration - backend template
+# Edit this file to customize your development environment
+
+apps:
+  # Code Editors & IDEs
+  vscode:
+    darwin: "/Applications/Visual Studio Code.app"
+    linux: "code"
+    windows: "Code.exe"
+
+  goland:
+    darwin: "/Applications/GoLand.app"
+    linux: "goland"
+    windows: "goland64.exe"
+
+  intellij:
+    darwin: "/Applications/IntelliJ IDEA.app"
+    linux: "idea"
+    windows: "idea64.exe"
+
+  # Databases
+  datagrip:
+    darwin: "/Applications/DataGrip.app"
+    linux: "datagrip"
+    windows: "datagrip64.exe"
+
+  dbeaver:
+    darwin: "/Applications/DBeaver.app"
+    linux: "dbeaver"
+    windows: "dbeaver.exe"
+
+  # API Tools
+  postman:
+    darwin: "/Applications/Postman.app"
+    linux: "postman"
+    windows: "Postman.exe"
+
+  insomnia:
+    darwin: "/Applications/Insomnia.app"
+    linux: "insomnia"
+    windows: "Insomnia.exe"
+
+  # Containers
+  docker:
+    darwin: "/Applications/Docker.app"
+    linux: "docker-desktop"
+    windows: "Docker Desktop.exe"
+
+  # Browsers
+  chrome:
+    darwin: "/Applications/Google Chrome.app"
+    linux: "google-chrome"
+    windows: "chrome.exe"
+
+  # Communication
+  slack:
+    darwin: "/Applications/Slack.app"
+    linux: "slack"
+    windows: "slack.exe"
+
+aliases:
+  code: vscode
+  idea: intellij
+  ij: intellij
+  dg: datagrip
+  db: dbeaver
+  pm: postman
+  gc: chrome
+`
+}
+
+// getDevOpsTemplate returns the starter config for DevOps engineers
+func getDevOpsTemplate() string {
+	return `# openx configuration - devops template
+# Edit this file to customize your development environment
+
+apps:
+  # Code Editors
+  vscode:
+    darwin: "/Applications/Visual Studio Code.app"
+    linux: "code"
+    windows: "Code.exe"
+
+  # Terminals
+  iterm:
+    darwin: "/Applications/iTerm.app"
+
+  wezterm:
+    darwin: "/Applications/WezTerm.app"
+    linux: "wezterm"
+    windows: "wezterm-gui.exe"
+
+  alacritty:
+    darwin: "/Applications/Alacritty.app"
+    linux: "alacritty"
+    windows: "alacritty.exe"
+
+  windowsterminal:
+    windows: "wt.exe"
+
+  # Containers & Clusters
+  docker:
+    darwin: "/Applications/Docker.app"
+    linux: "docker-desktop"
+    windows: "Docker Desktop.exe"
+
+  lens:
+    darwin: "/Applications/Lens.app"
+    linux: "lens-desktop"
+    windows: "Lens.exe"
+
+  # Browsers
+  chrome:
+    darwin: "/Applications/Google Chrome.app"
+    linux: "google-chrome"
+    windows: "chrome.exe"
+
+  firefox:
+    darwin: "/Applications/Firefox.app"
+    linux: "firefox"
+    windows: "firefox.exe"
+
+  # Communication
+  slack:
+    darwin: "/Applications/Slack.app"
+    linux: "slack"
+    windows: "slack.exe"
+
+aliases:
+  code: vscode
+  it: iterm
+  wez: wezterm
+  al: alacritty
+  wt: windowsterminal
+  gc: chrome
+  ff: firefox
+`
+}
+
+// getDesignerTemplate returns the starter config for designers
+func getDesignerTemplate() string {
+	return `# openx configuration - designer template
+# Edit this file to customize your environment
+
+apps:
+  # Design Tools
+  figma:
+    darwin: "/Applications/Figma.app"
+    linux: "figma-linux"
+    windows: "Figma.exe"
+
+  sketch:
+    darwin: "/Applications/Sketch.app"
+
+  photoshop:
+    darwin: "/Applications/Adobe Photoshop 2024/Adobe Photoshop 2024.app"
+    windows: "Photoshop.exe"
+
+  illustrator:
+    darwin: "/Applications/Adobe Illustrator 2024/Adobe Illustrator.app"
+    windows: "Illustrator.exe"
+
+  inkscape:
+    darwin: "/Applications/Inkscape.app"
+    linux: "inkscape"
+    windows: "inkscape.exe"
+
+  gimp:
+    darwin: "/Applications/GIMP.app"
+    linux: "gimp"
+    windows: "gimp-2.10.exe"
+
+  blender:
+    darwin: "/Applications/Blender.app"
+    linux: "blender"
+    windows: "blender.exe"
+
+  # Browsers
+  chrome:
+    darwin: "/Applications/Google Chrome.app"
+    linux: "google-chrome"
+    windows: "chrome.exe"
+
+  # Notes & Communication
+  notion:
+    darwin: "/Applications/Notion.app"
+    windows: "Notion.exe"
+
+  slack:
+    darwin: "/Applications/Slack.app"
+    linux: "slack"
+    windows: "slack.exe"
+
+aliases:
+  fig: figma
+  sk: sketch
+  psd: photoshop
+  ai: illustrator
+  gc: chrome
+  not: notion
+`
+}
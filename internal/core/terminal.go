@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// terminalExecArgs maps terminal emulators to the arguments that come
+// before the command they should run
+var terminalExecArgs = map[string][]string{
+	"alacritty":           {"-e"},
+	"foot":                {},
+	"ghostty":             {"-e"},
+	"gnome-terminal":      {"--"},
+	"kitty":               {},
+	"konsole":             {"-e"},
+	"terminator":          {"-x"},
+	"tilix":               {"-e"},
+	"wezterm":             {"start", "--"},
+	"wt":                  {"new-tab"},
+	"x-terminal-emulator": {"-e"},
+	"xfce4-terminal":      {"-x"},
+	"xterm":               {"-e"},
+}
+
+// resolveTerminal returns the launch path of the terminal configured with
+// the top-level terminal setting, which may name a configured app or a
+// terminal command. Empty means the platform default.
+func resolveTerminal(config *Config) string {
+	if config.Terminal == "" {
+		return ""
+	}
+	if _, app, err := lookupApp(config, config.Terminal); err == nil && app.GetLaunchPath() != "" {
+		return app.GetLaunchPath()
+	}
+	return config.Terminal
+}
+
+// defaultTerminal picks a terminal when none is configured
+func defaultTerminal() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "Terminal"
+	case "windows":
+		if _, err := exec.LookPath("wt"); err == nil {
+			return "wt"
+		}
+		return "cmd"
+	default:
+		if terminal := os.Getenv("TERMINAL"); terminal != "" {
+			return terminal
+		}
+		if _, err := exec.LookPath("x-terminal-emulator"); err == nil {
+			return "x-terminal-emulator"
+		}
+		return "xterm"
+	}
+}
+
+// terminalKind returns the lowercase name a terminal is known by
+func terminalKind(terminalPath string) string {
+	base := filepath.Base(strings.ReplaceAll(terminalPath, `\`, "/"))
+	base = strings.TrimSuffix(strings.TrimSuffix(base, ".app"), ".exe")
+	kind := strings.ToLower(base)
+	if kind == "windowsterminal" {
+		return "wt"
+	}
+	return kind
+}
+
+// terminalCommand builds the command that runs program inside a terminal
+// emulator
+func terminalCommand(terminalPath, program string, args []string) *exec.Cmd {
+	if terminalPath == "" {
+		terminalPath = defaultTerminal()
+	}
+	commandLine := append([]string{program}, args...)
+
+	switch kind := terminalKind(terminalPath); kind {
+	case "terminal":
+		script := fmt.Sprintf("tell application \"Terminal\"\nactivate\ndo script %s\nend tell", appleScriptQuote(shellQuote(commandLine)))
+		return exec.Command("osascript", "-e", script)
+	case "iterm", "iterm2":
+		script := fmt.Sprintf("tell application \"iTerm\"\nactivate\ncreate window with default profile command %s\nend tell", appleScriptQuote(shellQuote(commandLine)))
+		return exec.Command("osascript", "-e", script)
+	case "cmd":
+		// A new console window that stays open after the tool exits
+		return exec.Command("cmd", append([]string{"/c", "start", "", "cmd", "/k"}, commandLine...)...)
+	default:
+		// macOS terminal bundles are started through their executable
+		if strings.HasSuffix(strings.ToLower(terminalPath), ".app") {
+			if execPath, err := findAppExecutable(terminalPath); err == nil {
+				terminalPath = execPath
+			}
+		}
+
+		execArgs, known := terminalExecArgs[kind]
+		if !known {
+			execArgs = []string{"-e"}
+		}
+		return exec.Command(terminalPath, append(append([]string{}, execArgs...), commandLine...)...)
+	}
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerminalCommand(t *testing.T) {
+	tests := []struct {
+		terminal string
+		want     string
+	}{
+		{"/usr/bin/alacritty", "/usr/bin/alacritty -e lazygit -p my repo"},
+		{"kitty", "kitty lazygit -p my repo"},
+		{"gnome-terminal", "gnome-terminal -- lazygit -p my repo"},
+		{"wezterm", "wezterm start -- lazygit -p my repo"},
+		{`C:\Users\me\AppData\Local\Microsoft\WindowsApps\wt.exe`, `C:\Users\me\AppData\Local\Microsoft\WindowsApps\wt.exe new-tab lazygit -p my repo`},
+		{"my-terminal", "my-terminal -e lazygit -p my repo"},
+		{"cmd", "cmd /c start  cmd /k lazygit -p my repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.terminal, func(t *testing.T) {
+			cmd := terminalCommand(tt.terminal, "lazygit", []string{"-p", "my repo"})
+			if got := strings.Join(cmd.Args, " "); got != tt.want {
+				t.Errorf("terminalCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalCommand_MacOS(t *testing.T) {
+	tests := []struct {
+		terminal string
+		want     string
+	}{
+		{"Terminal", `do script "'lazygit' 'my repo'"`},
+		{"/Applications/iTerm.app", `create window with default profile command "'lazygit' 'my repo'"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.terminal, func(t *testing.T) {
+			cmd := terminalCommand(tt.terminal, "lazygit", []string{"my repo"})
+			if cmd.Args[0] != "osascript" || !strings.Contains(cmd.Args[2], tt.want) {
+				t.Errorf("terminalCommand() = %q, want osascript with %s", cmd.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTerminal(t *testing.T) {
+	config := &Config{
+		Apps: map[string]*App{
+			"alacritty": {Paths: map[string]string{"darwin": "/usr/bin/alacritty", "linux": "/usr/bin/alacritty", "windows": "/usr/bin/alacritty"}},
+		},
+		Aliases: map[string]string{"al": "alacritty"},
+	}
+
+	tests := []struct {
+		terminal string
+		want     string
+	}{
+		{"", ""},
+		{"al", "/usr/bin/alacritty"},
+		{"kitty", "kitty"},
+	}
+
+	for _, tt := range tests {
+		config.Terminal = tt.terminal
+		if got := resolveTerminal(config); got != tt.want {
+			t.Errorf("resolveTerminal(%q) = %q, want %q", tt.terminal, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// urlPlaceholder matches {1}, {2}, ... and {args} in a URL launch path
+var urlPlaceholder = regexp.MustCompile(`\{(\d+|args)\}`)
+
+// expandURLTemplate fills a URL launch path with the launch arguments.
+// {N} is the Nth argument, {args} all of them separated by spaces; values
+// are URL-escaped.
+func expandURLTemplate(template string, args []string) (string, error) {
+	used := map[int]bool{}
+	var missing error
+
+	expanded := urlPlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+		key := strings.Trim(match, "{}")
+		if key == "args" {
+			for i := range args {
+				used[i] = true
+			}
+			return urlEscape(strings.Join(args, " "))
+		}
+
+		n, _ := strconv.Atoi(key)
+		if n < 1 || n > len(args) {
+			if missing == nil {
+				missing = fmt.Errorf("%s needs argument %s", template, match)
+			}
+			return match
+		}
+		used[n-1] = true
+		return urlEscape(args[n-1])
+	})
+	if missing != nil {
+		return "", missing
+	}
+
+	if len(used) < len(args) {
+		fmt.Fprintf(os.Stderr, "Warning: %s has no placeholder for some arguments, ignoring them\n", template)
+	}
+	return expanded, nil
+}
+
+// urlEscape escapes s for use anywhere in a URL, spaces as %20
+func urlEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// urlOpenerCommand hands a URL to the handler registered for its scheme
+func urlOpenerCommand(target string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		// cmd's start would interpret & and other characters in the URL
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	}
+
+	opener, openerArgs := getSystemOpener()
+	return exec.Command(opener, append(openerArgs, target)...)
+}
@@ -0,0 +1,38 @@
+package core
+
+import "testing"
+
+func TestExpandURLTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		args      []string
+		expected  string
+		expectErr bool
+	}{
+		{"no placeholders", "slack://open", nil, "slack://open", false},
+		{"positional", "obsidian://open?vault={1}&file={2}", []string{"notes", "daily/today"}, "obsidian://open?vault=notes&file=daily%2Ftoday", false},
+		{"all args", "raycast://extensions/search?q={args}", []string{"hello", "world"}, "raycast://extensions/search?q=hello%20world", false},
+		{"escapes query characters", "app://x?q={1}", []string{"a&b=c"}, "app://x?q=a%26b%3Dc", false},
+		{"missing argument", "obsidian://open?vault={1}", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandURLTemplate(tt.template, tt.args)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expandURLTemplate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("expandURLTemplate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLOpenerCommand(t *testing.T) {
+	cmd := urlOpenerCommand("slack://open")
+	if last := cmd.Args[len(cmd.Args)-1]; last != "slack://open" {
+		t.Errorf("urlOpenerCommand() should pass the URL last, got %v", cmd.Args)
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ResourceUsage is what an app's processes use, summed over all of them
+type ResourceUsage struct {
+	Processes int     `json:"processes"`
+	CPU       float64 `json:"cpu"`                 // percent of one core, as ps reports it
+	RSS       int64   `json:"rss"`                 // resident memory in bytes
+	OpenFiles int     `json:"openFiles,omitempty"` // open files, handles on Windows; omitted when unknown
+}
+
+// processUsage is the resource usage of a single process. OpenFiles is -1
+// when it couldn't be counted.
+type processUsage struct {
+	CPU       float64
+	RSS       int64
+	OpenFiles int
+}
+
+// processUsages returns the resource usage of the given processes. Pids
+// that exited meanwhile are missing from the result.
+func processUsages(pids []int) (map[int]processUsage, error) {
+	if len(pids) == 0 {
+		return map[int]processUsage{}, nil
+	}
+
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(pid)
+	}
+
+	switch runtime.GOOS {
+	case "darwin", "linux":
+		output, err := exec.Command("ps", "-o", "pid=,pcpu=,rss=", "-p", strings.Join(ids, ",")).Output()
+		if err != nil && len(output) == 0 {
+			// ps exits non-zero when none of the pids exist
+			return map[int]processUsage{}, nil
+		}
+		usages := parsePSUsage(string(output))
+		countOpenFiles(usages)
+		return usages, nil
+	case "windows":
+		script := fmt.Sprintf(`$ids = @(%s); Get-CimInstance Win32_PerfFormattedData_PerfProc_Process | Where-Object { $ids -contains $_.IDProcess } | ForEach-Object { "$($_.IDProcess)`+"`t"+`$($_.PercentProcessorTime)`+"`t"+`$($_.WorkingSet)`+"`t"+`$($_.HandleCount)" }`, strings.Join(ids, ","))
+		output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read process usage: %w", err)
+		}
+		return parseWindowsUsage(string(output)), nil
+	default:
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// parsePSUsage reads `ps -o pid=,pcpu=,rss=` output; ps reports rss in KiB
+func parsePSUsage(output string) map[int]processUsage {
+	usages := map[int]processUsage{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		cpu, err2 := strconv.ParseFloat(fields[1], 64)
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		usages[pid] = processUsage{CPU: cpu, RSS: rss * 1024, OpenFiles: -1}
+	}
+	return usages
+}
+
+// parseWindowsUsage reads tab-separated pid, CPU percent, working set and
+// handle count rows
+func parseWindowsUsage(output string) map[int]processUsage {
+	usages := map[int]processUsage{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		cpu, err2 := strconv.ParseFloat(fields[1], 64)
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		handles, err4 := strconv.Atoi(fields[3])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		usages[pid] = processUsage{CPU: cpu, RSS: rss, OpenFiles: handles}
+	}
+	return usages
+}
+
+// countOpenFiles fills in the open file counts: from /proc on Linux, from
+// a single lsof call on macOS. Processes of other users stay unknown.
+func countOpenFiles(usages map[int]processUsage) {
+	switch runtime.GOOS {
+	case "linux":
+		for pid, usage := range usages {
+			if fds, err := os.ReadDir(filepath.Join("/proc", strconv.Itoa(pid), "fd")); err == nil {
+				usage.OpenFiles = len(fds)
+				usages[pid] = usage
+			}
+		}
+	case "darwin":
+		ids := make([]string, 0, len(usages))
+		for pid := range usages {
+			ids = append(ids, strconv.Itoa(pid))
+		}
+		output, _ := exec.Command("lsof", "-n", "-P", "-p", strings.Join(ids, ",")).Output()
+		for pid, count := range parseLsofCounts(string(output)) {
+			if usage, ok := usages[pid]; ok {
+				usage.OpenFiles = count
+				usages[pid] = usage
+			}
+		}
+	}
+}
+
+// parseLsofCounts counts the lsof rows of each pid, skipping the header
+func parseLsofCounts(output string) map[int]int {
+	counts := map[int]int{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[1]); err == nil {
+			counts[pid]++
+		}
+	}
+	return counts
+}
+
+// sumUsage adds up the usage of the given processes. The open file count
+// is left out when any process's count is unknown.
+func sumUsage(pids []int, usages map[int]processUsage) ResourceUsage {
+	var total ResourceUsage
+	filesKnown := true
+	for _, pid := range pids {
+		usage, ok := usages[pid]
+		if !ok {
+			continue
+		}
+		total.Processes++
+		total.CPU += usage.CPU
+		total.RSS += usage.RSS
+		if usage.OpenFiles < 0 {
+			filesKnown = false
+		} else {
+			total.OpenFiles += usage.OpenFiles
+		}
+	}
+	if !filesKnown {
+		total.OpenFiles = 0
+	}
+	return total
+}
+
+// formatBytes renders a byte count for tables, e.g. 512M or 1.5G
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%dM", n>>20)
+	default:
+		return fmt.Sprintf("%dK", n>>10)
+	}
+}
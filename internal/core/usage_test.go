@@ -0,0 +1,90 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestParsePSUsage(t *testing.T) {
+	output := `  812  12.5 204800
+  830   0.0   1024
+ junk
+`
+
+	got := parsePSUsage(output)
+	if len(got) != 2 {
+		t.Fatalf("parsePSUsage() = %+v, want 2 processes", got)
+	}
+	if got[812] != (processUsage{CPU: 12.5, RSS: 200 << 20, OpenFiles: -1}) {
+		t.Errorf("parsePSUsage()[812] = %+v", got[812])
+	}
+}
+
+func TestParseWindowsUsage(t *testing.T) {
+	output := "5120\t25\t104857600\t812\r\n5121\tbad\t1\t1\r\n"
+
+	got := parseWindowsUsage(output)
+	if len(got) != 1 || got[5120] != (processUsage{CPU: 25, RSS: 100 << 20, OpenFiles: 812}) {
+		t.Errorf("parseWindowsUsage() = %+v", got)
+	}
+}
+
+func TestParseLsofCounts(t *testing.T) {
+	output := `COMMAND   PID USER   FD   TYPE DEVICE SIZE/OFF NODE NAME
+Slack     812 dev  cwd    DIR    1,4      640    2 /
+Slack     812 dev  txt    REG    1,4   123456  100 /Applications/Slack.app
+Helper    830 dev  cwd    DIR    1,4      640    2 /
+`
+
+	got := parseLsofCounts(output)
+	if got[812] != 2 || got[830] != 1 || len(got) != 2 {
+		t.Errorf("parseLsofCounts() = %v, want 812:2 830:1", got)
+	}
+}
+
+func TestSumUsage(t *testing.T) {
+	usages := map[int]processUsage{
+		1: {CPU: 10, RSS: 100, OpenFiles: 5},
+		2: {CPU: 2.5, RSS: 50, OpenFiles: 3},
+		3: {CPU: 1, RSS: 10, OpenFiles: -1},
+	}
+
+	got := sumUsage([]int{1, 2, 4}, usages)
+	if got != (ResourceUsage{Processes: 2, CPU: 12.5, RSS: 150, OpenFiles: 8}) {
+		t.Errorf("sumUsage() = %+v", got)
+	}
+
+	// One unknown count makes the total unknown
+	if got := sumUsage([]int{1, 3}, usages); got.OpenFiles != 0 || got.Processes != 2 {
+		t.Errorf("sumUsage() with an unknown count = %+v", got)
+	}
+}
+
+func TestProcessUsages(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Reads /proc on Linux")
+	}
+
+	usages, err := processUsages([]int{os.Getpid()})
+	if err != nil {
+		t.Fatalf("processUsages() unexpected error: %v", err)
+	}
+	usage, ok := usages[os.Getpid()]
+	if !ok || usage.RSS <= 0 || usage.OpenFiles <= 0 {
+		t.Errorf("processUsages() = %+v, want the test's memory and open files", usages)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512 << 10: "512K",
+		300 << 20: "300M",
+		3 << 29:   "1.5G",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
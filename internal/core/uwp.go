@@ -0,0 +1,88 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// uwpAppID returns the AppUserModelId of a uwp:<AUMID> launch path
+func uwpAppID(launchPath string) (string, bool) {
+	if !strings.HasPrefix(launchPath, uwpPrefix) {
+		return "", false
+	}
+	aumid := strings.TrimPrefix(launchPath, uwpPrefix)
+	return aumid, aumid != ""
+}
+
+// uwpPackageFamily returns the package family name part of an AUMID
+// (PackageFamilyName!AppId)
+func uwpPackageFamily(aumid string) string {
+	family, _, _ := strings.Cut(aumid, "!")
+	return family
+}
+
+// uwpCommand launches a Store app through the shell's AppsFolder. Store
+// apps are activated by the shell, so arguments can't be passed along.
+func uwpCommand(aumid string) *exec.Cmd {
+	return exec.Command("explorer.exe", `shell:AppsFolder\`+aumid)
+}
+
+// uwpProcessesScript selects the package's running processes into $procs,
+// exiting 2 when the package isn't installed and 1 when nothing runs
+func uwpProcessesScript(aumid string) string {
+	return fmt.Sprintf(`$pkg = Get-AppxPackage | Where-Object { $_.PackageFamilyName -eq %s } | Select-Object -First 1; `+
+		`if (-not $pkg) { exit 2 }; `+
+		`$procs = Get-Process | Where-Object { $_.Path -and $_.Path.StartsWith($pkg.InstallLocation, [StringComparison]::OrdinalIgnoreCase) }; `+
+		`if (-not $procs) { exit 1 }`,
+		powerShellQuote(uwpPackageFamily(aumid)))
+}
+
+// killUWPApp force-stops every process running from the app's package
+// and reports whether any were running. A package that isn't installed or
+// processes that can't be stopped are errors, nothing running is not.
+func killUWPApp(aumid string) (bool, error) {
+	output, err := exec.Command("powershell", "-NoProfile", "-Command", uwpKillScript(aumid)).CombinedOutput()
+	return uwpKillResult(uwpPackageFamily(aumid), output, err)
+}
+
+// uwpKillScript stops the package's processes, exiting 3 with the error
+// when Stop-Process fails
+func uwpKillScript(aumid string) string {
+	return uwpProcessesScript(aumid) +
+		"; try { $procs | Stop-Process -Force -ErrorAction Stop } catch { [Console]::Error.WriteLine($_); exit 3 }"
+}
+
+// uwpKillResult reads the outcome of uwpKillScript from how PowerShell
+// exited
+func uwpKillResult(family string, output []byte, err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		case 1:
+			return false, nil
+		case 2:
+			return false, fmt.Errorf("package %s is not installed: %w", family, ErrAppNotFound)
+		case 3:
+			return true, fmt.Errorf("failed to stop package %s: %s", family, strings.TrimSpace(string(output)))
+		}
+	}
+	return false, fmt.Errorf("failed to stop package %s: %w: %s", family, err, strings.TrimSpace(string(output)))
+}
+
+// isUWPAppRunning checks if any process of the app's package is running
+func isUWPAppRunning(aumid string) bool {
+	return exec.Command("powershell", "-NoProfile", "-Command", uwpProcessesScript(aumid)).Run() == nil
+}
+
+// uwpAppInstalled checks if the app's package is installed for this user
+func uwpAppInstalled(aumid string) bool {
+	script := fmt.Sprintf(`if (-not (Get-AppxPackage | Where-Object { $_.PackageFamilyName -eq %s })) { exit 1 }`,
+		powerShellQuote(uwpPackageFamily(aumid)))
+	return exec.Command("powershell", "-NoProfile", "-Command", script).Run() == nil
+}
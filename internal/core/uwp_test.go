@@ -0,0 +1,103 @@
+package core
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUWPAppID(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"uwp:Microsoft.WindowsTerminal_8wekyb3d8bbwe!App", "Microsoft.WindowsTerminal_8wekyb3d8bbwe!App", true},
+		{"uwp:", "", false},
+		{`C:\Program Files\App\app.exe`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			aumid, ok := uwpAppID(tt.path)
+			if aumid != tt.expected || ok != tt.ok {
+				t.Errorf("uwpAppID(%q) = %q, %v, want %q, %v", tt.path, aumid, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestUWPCommand(t *testing.T) {
+	aumid := "Microsoft.WindowsTerminal_8wekyb3d8bbwe!App"
+
+	if family := uwpPackageFamily(aumid); family != "Microsoft.WindowsTerminal_8wekyb3d8bbwe" {
+		t.Errorf("uwpPackageFamily() = %q", family)
+	}
+
+	cmd := uwpCommand(aumid)
+	if got := cmd.Args; len(got) != 2 || got[0] != "explorer.exe" || got[1] != `shell:AppsFolder\`+aumid {
+		t.Errorf("uwpCommand() args = %v", got)
+	}
+
+	script := uwpProcessesScript(aumid)
+	if !strings.Contains(script, "'Microsoft.WindowsTerminal_8wekyb3d8bbwe'") {
+		t.Errorf("uwpProcessesScript() should match the package family, got %q", script)
+	}
+}
+
+func TestUWPKillResult(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Uses sh for the exit codes")
+	}
+	if !strings.Contains(uwpKillScript("Contoso.App_abc!App"), "exit 3") {
+		t.Errorf("uwpKillScript() should exit 3 when Stop-Process fails, got %q", uwpKillScript("Contoso.App_abc!App"))
+	}
+
+	tests := []struct {
+		name        string
+		exitCode    int
+		wantRunning bool
+		wantErr     string // empty for no error
+	}{
+		{"stopped", 0, true, ""},
+		{"nothing running", 1, false, ""},
+		{"not installed", 2, false, "not installed"},
+		{"stop failed", 3, true, "Access is denied"},
+		{"powershell failed", 5, false, "failed to stop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("sh", "-c", "echo 'Access is denied' >&2; exit "+strconv.Itoa(tt.exitCode)).CombinedOutput()
+			running, err := uwpKillResult("Contoso.App_abc", output, err)
+			if running != tt.wantRunning {
+				t.Errorf("uwpKillResult() running = %v, want %v", running, tt.wantRunning)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("uwpKillResult() unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("uwpKillResult() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := uwpKillResult("Contoso.App_abc", nil, exec.Command("sh", "-c", "exit 2").Run())
+	if !errors.Is(err, ErrAppNotFound) {
+		t.Errorf("uwpKillResult() for a missing package = %v, want ErrAppNotFound", err)
+	}
+}
+
+func TestUWPAppHasNoDerivedKillPatterns(t *testing.T) {
+	app := &App{Paths: map[string]string{
+		"darwin":  "uwp:Microsoft.WindowsTerminal_8wekyb3d8bbwe!App",
+		"linux":   "uwp:Microsoft.WindowsTerminal_8wekyb3d8bbwe!App",
+		"windows": "uwp:Microsoft.WindowsTerminal_8wekyb3d8bbwe!App",
+	}}
+
+	if patterns := app.GetKillPatterns(); len(patterns) != 0 {
+		t.Errorf("GetKillPatterns() = %v, want none for a Store app", patterns)
+	}
+}
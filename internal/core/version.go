@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionProbeTimeout bounds how long a `--version` probe may run, so a
+// tool that ignores the flag and waits for input doesn't stall doctor
+const versionProbeTimeout = 3 * time.Second
+
+// versionNumber matches the first dotted version in a tool's output
+var versionNumber = regexp.MustCompile(`\d+(\.\d+)+([-+.]?[0-9A-Za-z]+)*`)
+
+// plistVersion matches the short version string in an XML Info.plist
+var plistVersion = regexp.MustCompile(`<key>CFBundleShortVersionString</key>\s*<string>([^<]*)</string>`)
+
+// addDoctorVersions detects the version of every available app, a few
+// apps at a time since probes start processes
+func addDoctorVersions(config *Config, apps []AppStatus) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, defaultKillConcurrency)
+	for i := range apps {
+		app := config.Apps[apps[i].Name]
+		if apps[i].Status != "available" || app == nil || isDockerApp(app) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			apps[i].Version = appVersion(app)
+		}()
+	}
+	wg.Wait()
+}
+
+// appVersion detects the installed version of an app: the bundle's
+// CFBundleShortVersionString on macOS, the executable's VERSIONINFO on
+// Windows, and a `--version` probe for terminal apps and commands on PATH.
+// It returns "" when the version can't be told.
+func appVersion(app *App) string {
+	path := app.GetLaunchPath()
+	switch {
+	case runtime.GOOS == "darwin" && strings.HasSuffix(strings.TrimSuffix(path, "/"), ".app"):
+		return bundleVersion(path)
+	case runtime.GOOS == "windows" && strings.EqualFold(filepath.Ext(path), ".exe") && strings.ContainsAny(path, `/\`):
+		return exeVersion(path)
+	case app.Terminal || !strings.ContainsAny(path, `/\`):
+		if _, err := exec.LookPath(path); err != nil {
+			return ""
+		}
+		return probeVersion(path)
+	}
+	return ""
+}
+
+// bundleVersion reads a macOS bundle's version from its Info.plist,
+// asking plutil when the plist is binary
+func bundleVersion(bundle string) string {
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	data, err := os.ReadFile(plist)
+	if err != nil {
+		return ""
+	}
+	if version := parsePlistVersion(string(data)); version != "" {
+		return version
+	}
+	output, err := exec.Command("plutil", "-extract", "CFBundleShortVersionString", "raw", "-o", "-", plist).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// parsePlistVersion returns CFBundleShortVersionString from an XML plist
+func parsePlistVersion(plist string) string {
+	if match := plistVersion.FindStringSubmatch(plist); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// exeVersion reads the product version from a Windows executable's
+// VERSIONINFO resource
+func exeVersion(path string) string {
+	script := "(Get-Item -LiteralPath " + powerShellQuote(path) + ").VersionInfo.ProductVersion"
+	output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// probeVersion runs `command --version` and picks the version out of its
+// output
+func probeVersion(command string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, command, "--version")
+	cmd.WaitDelay = time.Second // children keeping the output open
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(output) == 0 {
+		return ""
+	}
+	return parseVersionOutput(string(output))
+}
+
+// parseVersionOutput finds the version in `--version` output, such as
+// "git version 2.43.0" or "Python 3.12.1"
+func parseVersionOutput(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if version := versionNumber.FindString(line); version != "" {
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,47 @@
+package core
+
+import "testing"
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"git", "git version 2.43.0\n", "2.43.0"},
+		{"python", "Python 3.12.1", "3.12.1"},
+		{"code", "1.95.3\nf1a4fb101478ce6ec82fe9627c43efbf9e98c813\nx64\n", "1.95.3"},
+		{"prerelease", "node v22.1.0-rc.1", "22.1.0-rc.1"},
+		{"banner first", "Docker tools\nDocker version 27.3.1, build ce12230", "27.3.1"},
+		{"no version", "usage: tool [options]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseVersionOutput(tt.output); got != tt.want {
+				t.Errorf("parseVersionOutput(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePlistVersion(t *testing.T) {
+	plist := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>Slack</string>
+	<key>CFBundleShortVersionString</key>
+	<string>4.41.97</string>
+	<key>CFBundleVersion</key>
+	<string>441097</string>
+</dict>
+</plist>`
+
+	if got := parsePlistVersion(plist); got != "4.41.97" {
+		t.Errorf("parsePlistVersion() = %q, want 4.41.97", got)
+	}
+	if got := parsePlistVersion("bplist00\x00\x01"); got != "" {
+		t.Errorf("parsePlistVersion(binary) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// WhichResult is what a name launches: an app with its path on this OS,
+// or a group with its members
+type WhichResult struct {
+	Name string `json:"name"` // as given
+	Kind string `json:"kind"` // ListKindApp or ListKindGroup
+	App  string `json:"app,omitempty"`
+
+	// LaunchPath is the app's configured path for this OS, Path the file
+	// it was found at, empty when it wasn't or isn't a file (URLs, Docker)
+	LaunchPath string `json:"launchPath,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Status     string `json:"status,omitempty"` // as doctor reports it: available, missing, no-path
+
+	Members []string `json:"members,omitempty"`
+}
+
+// Which resolves a name the way launching it would: apps, config aliases
+// and built-in synonyms lead to an app, other names to a group
+func Which(name string) (*WhichResult, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	key := strings.ToLower(name)
+	appName, app, err := lookupApp(config, key)
+	if err != nil {
+		if target, ok := SynonymTarget(key); ok && config.Apps[target] != nil {
+			appName, app, err = target, config.Apps[target], nil
+		}
+	}
+	if err != nil {
+		members, ok := config.Groups[key]
+		if !ok {
+			return nil, UnknownName(config, "alias or group", name)
+		}
+		result := &WhichResult{Name: name, Kind: ListKindGroup}
+		for _, member := range members {
+			result.Members = append(result.Members, member.App)
+		}
+		return result, nil
+	}
+
+	status := checkAppStatus(appName, app)
+	result := &WhichResult{Name: name, Kind: ListKindApp, App: appName, LaunchPath: status.LaunchPath, Status: status.Status}
+	if status.Status == "available" && !isDockerApp(app) {
+		result.Path = whichPath(status.LaunchPath)
+	}
+	debug("which", "name", name, "app", appName, "launchPath", result.LaunchPath, "path", result.Path)
+	return result, nil
+}
+
+// whichPath returns the file launchPath starts, empty for URLs and the
+// like that aren't files
+func whichPath(launchPath string) string {
+	switch {
+	case isURL(launchPath):
+		return ""
+	case strings.HasPrefix(launchPath, snapPrefix):
+		return "/snap/bin/" + strings.TrimPrefix(launchPath, snapPrefix)
+	case strings.HasPrefix(launchPath, appImagePrefix):
+		path, _ := findAppImage(strings.TrimPrefix(launchPath, appImagePrefix), appImageDirs())
+		return path
+	case strings.ContainsAny(launchPath, `/\`):
+		return expandTilde(launchPath)
+	}
+	path, err := exec.LookPath(launchPath)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// RunWhich prints what name launches: the app's path, or a group's
+// members one per line. A missing app is an error, printed as JSON first.
+func RunWhich(name string, jsonOutput bool) error {
+	result, err := Which(name)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	} else if result.Kind == ListKindGroup {
+		for _, member := range result.Members {
+			fmt.Println(member)
+		}
+	} else if result.Status == "available" {
+		fmt.Println(cmp.Or(result.Path, result.LaunchPath))
+	}
+
+	switch result.Status {
+	case "no-path":
+		return fmt.Errorf("%w for %s on %s", ErrNoPathForPlatform, result.App, runtime.GOOS)
+	case "missing":
+		return fmt.Errorf("%s is not installed: %w: %s", result.App, ErrAppNotFound, result.LaunchPath)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestWhich(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell path tests on Windows")
+	}
+
+	testContent := `
+apps:
+  shell:
+    darwin: sh
+    linux: sh
+  gone:
+    darwin: /nonexistent/app
+    linux: /nonexistent/app
+  nowhere:
+    windows: notepad.exe
+aliases:
+  term: shell
+groups:
+  work: [shell, gone]`
+
+	configPath := setupTestConfig(t, testContent)
+	cleanup := setTempConfigPath(t, configPath)
+	defer cleanup()
+
+	result, err := Which("Term")
+	if err != nil {
+		t.Fatalf("Which(Term) unexpected error: %v", err)
+	}
+	if result.Kind != ListKindApp || result.App != "shell" || result.LaunchPath != "sh" || result.Status != "available" {
+		t.Errorf("Which(Term) = %+v, want the available shell app", result)
+	}
+	if result.Path == "" || result.Path == "sh" {
+		t.Errorf("Which(Term) path = %q, want sh found on PATH", result.Path)
+	}
+
+	result, err = Which("gone")
+	if err != nil {
+		t.Fatalf("Which(gone) unexpected error: %v", err)
+	}
+	if result.Status != "missing" || result.Path != "" {
+		t.Errorf("Which(gone) = %+v, want missing without a path", result)
+	}
+
+	result, err = Which("nowhere")
+	if err != nil {
+		t.Fatalf("Which(nowhere) unexpected error: %v", err)
+	}
+	if result.Status != "no-path" {
+		t.Errorf("Which(nowhere) status = %q, want no-path", result.Status)
+	}
+
+	result, err = Which("work")
+	if err != nil {
+		t.Fatalf("Which(work) unexpected error: %v", err)
+	}
+	if result.Kind != ListKindGroup || !reflect.DeepEqual(result.Members, []string{"shell", "gone"}) {
+		t.Errorf("Which(work) = %+v, want the group's members", result)
+	}
+
+	_, err = Which("nope")
+	var unknown *UnknownNameError
+	if !errors.As(err, &unknown) {
+		t.Errorf("Which(nope) error = %v, want UnknownNameError", err)
+	}
+}
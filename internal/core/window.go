@@ -0,0 +1,409 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// windowWaitTimeout bounds how long openx waits for a launched app's window
+const windowWaitTimeout = 15 * time.Second
+
+// windowPollInterval is how often openx looks for the launched app's window
+var windowPollInterval = 500 * time.Millisecond
+
+// macOSDesktopKeyCodes are the key codes of the digits 1-9, used with the
+// "Switch to Desktop N" keyboard shortcuts
+var macOSDesktopKeyCodes = []int{18, 19, 20, 21, 23, 22, 26, 28, 25}
+
+// Window states accepted by the per-app window option
+const (
+	windowMinimized  = "minimized"
+	windowMaximized  = "maximized"
+	windowFullscreen = "fullscreen"
+)
+
+// hasWindowSettings reports whether the app needs post-launch window management
+func hasWindowSettings(app *App) bool {
+	return app.Desktop > 0 || app.Window != "" || app.Monitor > 0
+}
+
+// monitorGeometry is a display's position and size in the virtual screen
+type monitorGeometry struct {
+	X, Y, Width, Height int
+}
+
+// validateWindowState checks the app's window option
+func validateWindowState(state string) error {
+	switch state {
+	case "", windowMinimized, windowMaximized, windowFullscreen:
+		return nil
+	default:
+		return fmt.Errorf("unknown window state %q (use minimized, maximized or fullscreen)", state)
+	}
+}
+
+// prepareWindowPlacement runs the placement steps that must happen before
+// launch. macOS has no public API to move windows between Spaces, so openx
+// switches to the target Space and lets the app open there.
+func prepareWindowPlacement(app *App) error {
+	if runtime.GOOS != "darwin" || app.Desktop == 0 {
+		return nil
+	}
+	if app.Desktop > len(macOSDesktopKeyCodes) {
+		return fmt.Errorf("desktop %d is out of range (1-%d)", app.Desktop, len(macOSDesktopKeyCodes))
+	}
+
+	script := fmt.Sprintf(`tell application "System Events" to key code %d using control down`, macOSDesktopKeyCodes[app.Desktop-1])
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		return fmt.Errorf("failed to switch to desktop %d (enable \"Switch to Desktop %d\" in keyboard shortcuts): %w", app.Desktop, app.Desktop, err)
+	}
+	return nil
+}
+
+// applyWindowPlacement waits for the launched app's window and moves it
+// according to the app's window settings
+func applyWindowPlacement(app *App) error {
+	if err := validateWindowState(app.Window); err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return applyWindowPlacementMacOS(app)
+	case "linux":
+		return applyWindowPlacementLinux(app)
+	case "windows":
+		return applyWindowPlacementWindows(app)
+	default:
+		return nil
+	}
+}
+
+// applyWindowPlacementMacOS moves the window to its monitor and sets the
+// window state through System Events. Space placement already happened in
+// prepareWindowPlacement.
+func applyWindowPlacementMacOS(app *App) error {
+	if app.Window == "" && app.Monitor == 0 {
+		return nil
+	}
+
+	patterns := appKillPatterns(app)
+	if len(patterns) == 0 {
+		return fmt.Errorf("no process name to find the window by")
+	}
+
+	if app.Monitor > 0 {
+		// The monitor script sizes maximized windows to their own display
+		maximize := app.Window == windowMaximized
+		script := macOSMonitorScript(patterns[0], app.Monitor, maximize)
+		if err := exec.Command("osascript", "-l", "JavaScript", "-e", script).Run(); err != nil {
+			return fmt.Errorf("failed to move window to monitor %d: %w", app.Monitor, err)
+		}
+		if maximize {
+			return nil
+		}
+	}
+	if app.Window == "" {
+		return nil
+	}
+
+	script := macOSWindowStateScript(patterns[0], app.Window)
+	if err := exec.Command("osascript", "-e", script).Run(); err != nil {
+		return fmt.Errorf("failed to set window %s (openx needs accessibility access): %w", app.Window, err)
+	}
+	return nil
+}
+
+// macOSWindowStateScript waits for the process's first window and puts it
+// into the given state
+func macOSWindowStateScript(processName, state string) string {
+	process := "process " + appleScriptQuote(processName)
+
+	var action string
+	switch state {
+	case windowMinimized:
+		action = `set value of attribute "AXMinimized" of window 1 to true`
+	case windowFullscreen:
+		action = `set value of attribute "AXFullScreen" of window 1 to true`
+	case windowMaximized:
+		action = "set position of window 1 to {0, 0}\n" +
+			"set size of window 1 to {item 3 of screenBounds, item 4 of screenBounds}"
+	}
+
+	polls := int(windowWaitTimeout / windowPollInterval)
+	return fmt.Sprintf(`tell application "Finder" to set screenBounds to bounds of window of desktop
+tell application "System Events"
+repeat %d times
+if exists %s then
+if (count of windows of %s) > 0 then exit repeat
+end if
+delay %g
+end repeat
+tell %s
+%s
+end tell
+end tell`, polls, process, process, windowPollInterval.Seconds(), process, action)
+}
+
+// macOSMonitorScript is a JavaScript for Automation script that waits for
+// the process's first window and moves it to the top-left corner of the
+// given display, filling the display when maximize is set. AppKit measures
+// screens from the bottom-left of the main display, System Events from
+// the top-left.
+func macOSMonitorScript(processName string, monitor int, maximize bool) string {
+	resize := ""
+	if maximize {
+		resize = "\nw.size = [f.size.width, f.size.height];"
+	}
+
+	polls := int(windowWaitTimeout / windowPollInterval)
+	return fmt.Sprintf(`ObjC.import('AppKit');
+var screens = $.NSScreen.screens;
+if (screens.count < %d) { throw new Error('monitor %d not found'); }
+var main = screens.objectAtIndex(0).frame;
+var f = screens.objectAtIndex(%d).frame;
+var p = Application('System Events').processes.byName(%s);
+for (var i = 0; i < %d; i++) {
+try { if (p.windows.length > 0) break; } catch (e) {}
+delay(%g);
+}
+var w = p.windows[0];
+w.position = [f.origin.x, main.size.height - f.origin.y - f.size.height];%s`,
+		monitor, monitor, monitor-1, strconv.Quote(processName), polls, windowPollInterval.Seconds(), resize)
+}
+
+// applyWindowPlacementLinux uses wmctrl to place the app's window
+func applyWindowPlacementLinux(app *App) error {
+	if _, err := exec.LookPath("wmctrl"); err != nil {
+		return fmt.Errorf("window placement needs wmctrl installed")
+	}
+
+	windowID, err := waitForWindowLinux(appKillPatterns(app))
+	if err != nil {
+		return err
+	}
+
+	if app.Desktop > 0 {
+		// wmctrl numbers desktops from zero
+		desktop := fmt.Sprintf("%d", app.Desktop-1)
+		if err := exec.Command("wmctrl", "-i", "-r", windowID, "-t", desktop).Run(); err != nil {
+			return fmt.Errorf("failed to move window to desktop %d: %w", app.Desktop, err)
+		}
+	}
+
+	if app.Monitor > 0 {
+		output, err := exec.Command("xrandr", "--listmonitors").Output()
+		if err != nil {
+			return fmt.Errorf("failed to list monitors (monitor placement needs xrandr): %w", err)
+		}
+		geometry, err := findMonitorLinux(string(output), app.Monitor)
+		if err != nil {
+			return err
+		}
+
+		// Maximized windows ignore moves, the state is applied again below
+		exec.Command("wmctrl", "-i", "-r", windowID, "-b", "remove,maximized_vert,maximized_horz").Run()
+		position := fmt.Sprintf("0,%d,%d,-1,-1", geometry.X, geometry.Y)
+		if err := exec.Command("wmctrl", "-i", "-r", windowID, "-e", position).Run(); err != nil {
+			return fmt.Errorf("failed to move window to monitor %d: %w", app.Monitor, err)
+		}
+	}
+
+	if args := linuxWindowStateArgs(app.Window); args != nil {
+		if err := exec.Command("wmctrl", append([]string{"-i", "-r", windowID}, args...)...).Run(); err != nil {
+			return fmt.Errorf("failed to set window %s: %w", app.Window, err)
+		}
+	}
+	return nil
+}
+
+// linuxWindowStateArgs returns the wmctrl arguments that apply a window state
+func linuxWindowStateArgs(state string) []string {
+	switch state {
+	case windowMinimized:
+		return []string{"-b", "add,hidden"}
+	case windowMaximized:
+		return []string{"-b", "add,maximized_vert,maximized_horz"}
+	case windowFullscreen:
+		return []string{"-b", "add,fullscreen"}
+	default:
+		return nil
+	}
+}
+
+// hasWindow reports whether a process matching one of the patterns has a
+// window open. It errors when this platform's window tools are missing.
+func hasWindow(patterns []string) (bool, error) {
+	switch runtime.GOOS {
+	case "linux":
+		output, err := exec.Command("wmctrl", "-lx").Output()
+		if err != nil {
+			return false, fmt.Errorf("failed to list windows (needs wmctrl): %w", err)
+		}
+		return findWindowLinux(string(output), patterns) != "", nil
+	case "darwin":
+		process := "process " + appleScriptQuote(patterns[0])
+		script := fmt.Sprintf(`tell application "System Events" to if exists %s then return count of windows of %s`, process, process)
+		output, err := exec.Command("osascript", "-e", script).Output()
+		if err != nil {
+			return false, fmt.Errorf("failed to count windows: %w", err)
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+		return count > 0, nil
+	case "windows":
+		script := fmt.Sprintf(`@(Get-Process -Name %s -ErrorAction SilentlyContinue | Where-Object { $_.MainWindowHandle -ne 0 }).Count`, powerShellQuote(patterns[0]))
+		output, err := exec.Command("powershell", "-NoProfile", "-Command", script).Output()
+		if err != nil {
+			return false, fmt.Errorf("failed to list windows: %w", err)
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+		return count > 0, nil
+	default:
+		return false, fmt.Errorf("window lookup not supported on %s", runtime.GOOS)
+	}
+}
+
+// findMonitorLinux returns the geometry of the numbered monitor (1-based)
+// from `xrandr --listmonitors` output, whose lines look like
+// " 0: +*DP-1 2560/597x1440/336+0+0  DP-1"
+func findMonitorLinux(xrandrOutput string, monitor int) (monitorGeometry, error) {
+	var monitors []monitorGeometry
+	for _, line := range strings.Split(xrandrOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		var g monitorGeometry
+		var widthMM, heightMM int
+		if _, err := fmt.Sscanf(fields[2], "%d/%dx%d/%d+%d+%d", &g.Width, &widthMM, &g.Height, &heightMM, &g.X, &g.Y); err != nil {
+			continue
+		}
+		monitors = append(monitors, g)
+	}
+
+	if monitor < 1 || monitor > len(monitors) {
+		return monitorGeometry{}, fmt.Errorf("monitor %d not found (%d connected)", monitor, len(monitors))
+	}
+	return monitors[monitor-1], nil
+}
+
+// waitForWindowLinux polls wmctrl until a window whose class matches one
+// of the patterns appears
+func waitForWindowLinux(patterns []string) (string, error) {
+	deadline := time.Now().Add(windowWaitTimeout)
+	for {
+		output, err := exec.Command("wmctrl", "-lx").Output()
+		if err == nil {
+			if id := findWindowLinux(string(output), patterns); id != "" {
+				return id, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("no window appeared within %s", windowWaitTimeout)
+		}
+		sleep(windowPollInterval)
+	}
+}
+
+// findWindowLinux returns the id of the first window in `wmctrl -lx`
+// output whose WM_CLASS contains one of the patterns
+func findWindowLinux(wmctrlOutput string, patterns []string) string {
+	for _, line := range strings.Split(wmctrlOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		class := strings.ToLower(fields[2])
+		for _, pattern := range patterns {
+			if pattern != "" && strings.Contains(class, strings.ToLower(pattern)) {
+				return fields[0]
+			}
+		}
+	}
+	return ""
+}
+
+// windowsOwnedBy returns the ids of the windows in `wmctrl -lp` output
+// that belong to one of the processes
+func windowsOwnedBy(wmctrlOutput string, pids []int) []string {
+	var windows []string
+	for _, line := range strings.Split(wmctrlOutput, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[2]); err == nil && slices.Contains(pids, pid) {
+			windows = append(windows, fields[0])
+		}
+	}
+	return windows
+}
+
+// applyWindowPlacementWindows places the app's main window with PowerShell
+func applyWindowPlacementWindows(app *App) error {
+	patterns := appKillPatterns(app)
+	if len(patterns) == 0 {
+		return fmt.Errorf("no process name to find the window by")
+	}
+
+	script := windowsWaitForWindowScript(patterns[0])
+	if app.Desktop > 0 {
+		// The VirtualDesktop module numbers desktops from zero
+		script += fmt.Sprintf("; Import-Module VirtualDesktop; Move-Window -Desktop (Get-Desktop %d) -Hwnd $p.MainWindowHandle | Out-Null", app.Desktop-1)
+	}
+	if app.Monitor > 0 {
+		script += "; " + windowsMonitorScript(app.Monitor)
+	}
+	if code := windowsShowWindowCode(app.Window); code != 0 {
+		script += "; Add-Type -Name Window -Namespace OpenX -MemberDefinition '[DllImport(\"user32.dll\")] public static extern bool ShowWindow(IntPtr hWnd, int nCmdShow);'" +
+			fmt.Sprintf("; [OpenX.Window]::ShowWindow($p.MainWindowHandle, %d) | Out-Null", code)
+	}
+
+	if err := exec.Command("powershell", "-NoProfile", "-Command", script).Run(); err != nil {
+		return fmt.Errorf("failed to place window: %w", err)
+	}
+	return nil
+}
+
+// windowsMonitorScript moves the window in $p to the top-left corner of
+// the numbered screen's working area, keeping its size. Maximizing
+// afterwards fills that screen.
+func windowsMonitorScript(monitor int) string {
+	return fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms; `+
+		`$screens = [System.Windows.Forms.Screen]::AllScreens; `+
+		`if ($screens.Count -lt %d) { exit 2 }; `+
+		`$area = $screens[%d].WorkingArea; `+
+		`Add-Type -Name Monitor -Namespace OpenX -MemberDefinition '[DllImport("user32.dll")] public static extern bool SetWindowPos(IntPtr hWnd, IntPtr after, int x, int y, int cx, int cy, uint flags);'; `+
+		// SWP_NOSIZE | SWP_NOZORDER
+		`[OpenX.Monitor]::SetWindowPos($p.MainWindowHandle, [IntPtr]::Zero, $area.X, $area.Y, 0, 0, 5) | Out-Null`,
+		monitor, monitor-1)
+}
+
+// windowsShowWindowCode returns the ShowWindow command for a window state.
+// Windows has no generic fullscreen, so fullscreen maximizes.
+func windowsShowWindowCode(state string) int {
+	switch state {
+	case windowMinimized:
+		return 6 // SW_MINIMIZE
+	case windowMaximized, windowFullscreen:
+		return 3 // SW_MAXIMIZE
+	default:
+		return 0
+	}
+}
+
+// windowsWaitForWindowScript waits for a process with a main window and
+// leaves it in $p
+func windowsWaitForWindowScript(processName string) string {
+	return fmt.Sprintf(`$deadline = (Get-Date).AddSeconds(%d); `+
+		`do { $p = Get-Process -Name %s -ErrorAction SilentlyContinue | Where-Object { $_.MainWindowHandle -ne 0 } | Select-Object -First 1; `+
+		`if (-not $p) { Start-Sleep -Milliseconds 500 } } until ($p -or (Get-Date) -gt $deadline); `+
+		`if (-not $p) { exit 1 }`,
+		int(windowWaitTimeout.Seconds()), powerShellQuote(processName))
+}
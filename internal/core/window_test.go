@@ -0,0 +1,160 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindWindowLinux(t *testing.T) {
+	output := `0x01e00003  0 gnome-terminal-server.Gnome-terminal  host Terminal
+0x04000007  1 google-chrome.Google-chrome  host New Tab - Google Chrome
+0x05200004 -1 plank.Plank  host plank
+`
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected string
+	}{
+		{"match by class", []string{"chrome"}, "0x04000007"},
+		{"case insensitive", []string{"Gnome-Terminal"}, "0x01e00003"},
+		{"first pattern without window", []string{"firefox", "plank"}, "0x05200004"},
+		{"no match", []string{"firefox"}, ""},
+		{"empty pattern ignored", []string{""}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findWindowLinux(output, tt.patterns); got != tt.expected {
+				t.Errorf("findWindowLinux() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWindowsOwnedBy(t *testing.T) {
+	output := `0x01e00003  0 2210  host Terminal
+0x04000007  1 812  host New Tab - Google Chrome
+0x04000012  1 812  host Settings - Google Chrome
+0x05200004 -1 903  host plank
+`
+
+	got := windowsOwnedBy(output, []int{812, 4000})
+	if strings.Join(got, ",") != "0x04000007,0x04000012" {
+		t.Errorf("windowsOwnedBy() = %v, want both Chrome windows", got)
+	}
+	if got := windowsOwnedBy(output, []int{4000}); len(got) != 0 {
+		t.Errorf("windowsOwnedBy() = %v, want no windows", got)
+	}
+}
+
+func TestWindowsWaitForWindowScript(t *testing.T) {
+	script := windowsWaitForWindowScript("Code")
+
+	if !strings.Contains(script, "Get-Process -Name 'Code'") {
+		t.Errorf("script should look up the process by name, got %q", script)
+	}
+	if !strings.Contains(script, "AddSeconds(15)") {
+		t.Errorf("script should wait up to the window timeout, got %q", script)
+	}
+}
+
+func TestHasWindowSettings(t *testing.T) {
+	if hasWindowSettings(&App{}) {
+		t.Error("app without placement should not need window management")
+	}
+	if !hasWindowSettings(&App{Desktop: 2}) {
+		t.Error("app with a desktop should need window management")
+	}
+	if !hasWindowSettings(&App{Window: "maximized"}) {
+		t.Error("app with a window state should need window management")
+	}
+	if !hasWindowSettings(&App{Monitor: 2}) {
+		t.Error("app with a monitor should need window management")
+	}
+}
+
+func TestWindowStates(t *testing.T) {
+	tests := []struct {
+		state     string
+		valid     bool
+		wmctrl    string
+		showCode  int
+		macOSAttr string
+	}{
+		{"minimized", true, "add,hidden", 6, "AXMinimized"},
+		{"maximized", true, "add,maximized_vert,maximized_horz", 3, "set size of window 1"},
+		{"fullscreen", true, "add,fullscreen", 3, "AXFullScreen"},
+		{"tiny", false, "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			err := validateWindowState(tt.state)
+			if tt.valid != (err == nil) {
+				t.Fatalf("validateWindowState(%q) error = %v, want valid %v", tt.state, err, tt.valid)
+			}
+			if !tt.valid {
+				return
+			}
+
+			if args := linuxWindowStateArgs(tt.state); len(args) != 2 || args[1] != tt.wmctrl {
+				t.Errorf("linuxWindowStateArgs() = %v, want -b %s", args, tt.wmctrl)
+			}
+			if code := windowsShowWindowCode(tt.state); code != tt.showCode {
+				t.Errorf("windowsShowWindowCode() = %d, want %d", code, tt.showCode)
+			}
+			script := macOSWindowStateScript("Code", tt.state)
+			if !strings.Contains(script, tt.macOSAttr) || !strings.Contains(script, `process "Code"`) {
+				t.Errorf("macOSWindowStateScript() = %q, want %s on process Code", script, tt.macOSAttr)
+			}
+		})
+	}
+}
+
+func TestFindMonitorLinux(t *testing.T) {
+	output := `Monitors: 2
+ 0: +*eDP-1 1920/344x1080/193+0+0  eDP-1
+ 1: +HDMI-1 2560/597x1440/336+1920+0  HDMI-1
+`
+
+	tests := []struct {
+		monitor int
+		want    monitorGeometry
+		wantErr bool
+	}{
+		{1, monitorGeometry{X: 0, Y: 0, Width: 1920, Height: 1080}, false},
+		{2, monitorGeometry{X: 1920, Y: 0, Width: 2560, Height: 1440}, false},
+		{3, monitorGeometry{}, true},
+		{0, monitorGeometry{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := findMonitorLinux(output, tt.monitor)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("findMonitorLinux(%d) error = %v, wantErr %v", tt.monitor, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("findMonitorLinux(%d) = %+v, want %+v", tt.monitor, got, tt.want)
+		}
+	}
+}
+
+func TestMonitorScripts(t *testing.T) {
+	script := macOSMonitorScript("Code", 2, false)
+	if !strings.Contains(script, "screens.objectAtIndex(1)") || !strings.Contains(script, `byName("Code")`) {
+		t.Errorf("macOSMonitorScript() = %q, want screen index 1 and process Code", script)
+	}
+	if strings.Contains(script, "w.size") {
+		t.Errorf("macOSMonitorScript() should keep the window size unless maximizing")
+	}
+	if !strings.Contains(macOSMonitorScript("Code", 2, true), "w.size = [f.size.width, f.size.height]") {
+		t.Errorf("macOSMonitorScript() should fill the display when maximizing")
+	}
+
+	script = windowsMonitorScript(2)
+	if !strings.Contains(script, "$screens.Count -lt 2") || !strings.Contains(script, "$screens[1].WorkingArea") {
+		t.Errorf("windowsMonitorScript() = %q, want screen index 1", script)
+	}
+}
@@ -1,14 +1,20 @@
 package lib
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"openx/internal/core"
 	"openx/shared/config"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -37,38 +43,236 @@ func (ox *OpenX) EnsureConfig() error {
 	return core.EnsureConfig()
 }
 
+// InitConfig creates a starter configuration from the named template
+// (default, frontend, backend, devops, designer)
+func (ox *OpenX) InitConfig(template string, force bool) error {
+	return core.InitConfig(template, force)
+}
+
+// Discover prints the applications installed on this machine
+func (ox *OpenX) Discover(jsonOutput bool) error {
+	return core.RunDiscover(jsonOutput)
+}
+
+// InitScannedConfig creates a configuration from the applications
+// installed on this machine after listing them. Unless yes is set the
+// user is asked which to leave out.
+func (ox *OpenX) InitScannedConfig(force, yes bool) error {
+	return core.InitScannedConfig(force, yes)
+}
+
 // RunAlias runs an application by alias with optional arguments
 func (ox *OpenX) RunAlias(alias string, args ...string) error {
 	return core.LaunchApp(alias, args)
 }
 
+// RunAliasWithOptions runs an application by alias with launch options
+// such as output logging
+func (ox *OpenX) RunAliasWithOptions(alias string, opts core.LaunchOptions, args ...string) error {
+	return core.LaunchAppWithOptions(alias, args, opts)
+}
+
+// RunAliasReport runs an application by alias with launch options and
+// reports what was started, its pid and how the launch went
+func (ox *OpenX) RunAliasReport(alias string, opts core.LaunchOptions, args ...string) (*core.LaunchReport, error) {
+	return core.LaunchAppReport(alias, args, opts)
+}
+
+// RunAliasWait runs an application by alias and blocks until it exits,
+// returning the application's exit code
+func (ox *OpenX) RunAliasWait(alias string, args ...string) (int, error) {
+	err := core.LaunchAppWithOptions(alias, args, core.LaunchOptions{Wait: true})
+
+	var exitErr *core.AppExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// RunGroup launches every application in a workspace group
+func (ox *OpenX) RunGroup(name string) error {
+	return core.LaunchGroup(name)
+}
+
+// RunGroupWithConcurrency launches a workspace group with at most
+// concurrency apps starting at once and returns per-app results
+func (ox *OpenX) RunGroupWithConcurrency(name string, concurrency int) ([]core.LaunchResult, error) {
+	return core.LaunchGroupWithConcurrency(name, concurrency)
+}
+
+// IsGroup reports whether name is a configured workspace group
+func (ox *OpenX) IsGroup(name string) bool {
+	return core.IsGroup(name)
+}
+
+// OpenFile opens a file with the app configured for its extension and
+// reports whether a mapping applied
+func (ox *OpenX) OpenFile(path string) (bool, error) {
+	return core.OpenFile(path)
+}
+
+// Open opens a file or URL with the given app, or with the app configured
+// for its extension or the system default when with is empty
+func (ox *OpenX) Open(target, with string) error {
+	return core.OpenTarget(target, with)
+}
+
+// SuggestAliases returns configured names close to a mistyped alias
+func (ox *OpenX) SuggestAliases(alias string) ([]string, error) {
+	return core.SuggestAliases(alias)
+}
+
+// ResolveFuzzy returns the app or group a mistyped name means, asking on
+// the terminal when it could mean several
+func (ox *OpenX) ResolveFuzzy(name string) (string, error) {
+	return core.ResolveFuzzy(name)
+}
+
+// RunProject opens a configured project directory with its editor
+func (ox *OpenX) RunProject(name string) error {
+	return core.LaunchProject(name)
+}
+
+// ListProjects returns the configured project names
+func (ox *OpenX) ListProjects() ([]string, error) {
+	return core.ProjectNames()
+}
+
+// Completions returns the names shell completion offers, read from the
+// config
+func (ox *OpenX) Completions() (*core.Completions, error) {
+	return core.LoadCompletions()
+}
+
 // RunDirect runs an application by direct path with optional arguments
 func (ox *OpenX) RunDirect(path string, args ...string) error {
 	return ox.executeDirectPath(path, args...)
 }
 
-// Kill terminates an application by alias
+// Kill terminates an application by alias. It errors with
+// core.ErrKillNoMatch when the application isn't running.
 func (ox *OpenX) Kill(alias string) error {
-	return core.CloseApp(alias)
+	_, err := core.CloseApp(alias)
+	return err
+}
+
+// KillWithOptions terminates an application by alias with kill options
+// and reports what each kill pattern matched and how it was stopped
+func (ox *OpenX) KillWithOptions(alias string, opts core.KillOptions) (*core.KillReport, error) {
+	return core.CloseAppWithOptions(alias, opts)
+}
+
+// KillSession closes every application openx launched since the last
+// KillSession, leaving other running applications alone
+func (ox *OpenX) KillSession(opts core.KillOptions) error {
+	return core.CloseSession(opts)
+}
+
+// KillIdle closes the running applications that used next to no CPU over
+// the last threshold, judged from the samples earlier calls recorded
+func (ox *OpenX) KillIdle(threshold time.Duration, opts core.KillOptions) ([]core.CloseResult, error) {
+	return core.CloseIdleApps(threshold, opts)
+}
+
+// KillAll closes every configured application that is running
+func (ox *OpenX) KillAll(opts core.KillOptions) ([]core.CloseResult, error) {
+	return core.CloseAllApps(opts)
+}
+
+// InstallLogoutHook registers a system hook that closes the group at logout
+func (ox *OpenX) InstallLogoutHook(group string) error {
+	return core.InstallLogoutHook(group)
+}
+
+// UninstallLogoutHook removes the logout hook
+func (ox *OpenX) UninstallLogoutHook() error {
+	return core.UninstallLogoutHook()
+}
+
+// KillTest prints an application's kill patterns and the processes they
+// match right now, without killing anything
+func (ox *OpenX) KillTest(alias string, jsonOutput bool) error {
+	return core.RunKillTest(alias, jsonOutput)
 }
 
-// AddAlias adds a new alias to the configuration
+// KillApps closes several applications at once and reports on each
+func (ox *OpenX) KillApps(aliases []string, opts core.KillOptions) ([]core.CloseResult, error) {
+	return core.CloseApps(aliases, opts)
+}
+
+// KillTag closes every application carrying the tag
+func (ox *OpenX) KillTag(tag string, opts core.KillOptions) ([]core.CloseResult, error) {
+	return core.CloseTaggedApps(tag, opts)
+}
+
+// KillGroup closes every application in a workspace group and reports
+// which of them were running
+func (ox *OpenX) KillGroup(name string, opts core.KillOptions) ([]core.CloseResult, error) {
+	return core.CloseGroup(name, opts)
+}
+
+// Restart closes an application and launches it again with args
+func (ox *OpenX) Restart(alias string, opts core.KillOptions, args ...string) error {
+	return core.RestartApp(alias, args, opts)
+}
+
+// RestartGroup closes a workspace group in reverse launch order and
+// launches it again in dependency order
+func (ox *OpenX) RestartGroup(name string, opts core.KillOptions, concurrency int) ([]core.LaunchResult, error) {
+	return core.RestartGroup(name, opts, concurrency)
+}
+
+// AddApp adds an application to the configuration with its path for this
+// OS, found among the installed applications when opts has none
+func (ox *OpenX) AddApp(name string, opts core.AddAppOptions) error {
+	return core.AddApp(name, opts)
+}
+
+// RemoveApp deletes an application from the configuration with the
+// aliases and group members pointing at it, asking first unless yes is set
+func (ox *OpenX) RemoveApp(name string, yes bool) error {
+	return core.RemoveApp(name, yes)
+}
+
+// AddAlias adds a new alias to the configuration, or points an existing
+// one elsewhere. appName may also be an alias or a built-in synonym such
+// as code; the alias then points at its app, since aliases don't chain.
 func (ox *OpenX) AddAlias(alias, appName string) error {
 	config, err := ox.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	alias, appName = strings.ToLower(alias), strings.ToLower(appName)
+	target := appName
+	if _, exists := config.Apps[target]; !exists {
+		if app, isAlias := config.Aliases[target]; isAlias {
+			target = app
+		} else if app, isSynonym := core.SynonymTarget(target); isSynonym {
+			target = app
+		}
+	}
+
 	// Check if the app exists in the configuration
-	if _, exists := config.Apps[appName]; !exists {
-		return fmt.Errorf("application '%s' is not configured", appName)
+	if _, exists := config.Apps[target]; !exists {
+		return core.UnknownName(config, core.ListKindApp, appName)
+	}
+	if _, exists := config.Apps[alias]; exists {
+		return fmt.Errorf("'%s' is an application, an alias can't hide it", alias)
+	}
+	if _, exists := config.Groups[alias]; exists {
+		return fmt.Errorf("'%s' is a group, an alias can't hide it", alias)
 	}
 
 	// Add the alias
 	if config.Aliases == nil {
 		config.Aliases = make(map[string]string)
 	}
-	config.Aliases[alias] = appName
+	config.Aliases[alias] = target
 
 	return ox.saveConfig(config)
 }
@@ -79,13 +283,10 @@ func (ox *OpenX) RemoveAlias(alias string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-
-	if config.Aliases == nil {
-		return fmt.Errorf("alias '%s' not found", alias)
-	}
+	alias = strings.ToLower(alias)
 
 	if _, exists := config.Aliases[alias]; !exists {
-		return fmt.Errorf("alias '%s' not found", alias)
+		return core.UnknownName(config, core.ListKindAlias, alias)
 	}
 
 	delete(config.Aliases, alias)
@@ -113,16 +314,315 @@ func (ox *OpenX) ListAliases() (map[string]string, error) {
 	return aliases, nil
 }
 
+// CreateGroup adds a workspace group of the given apps to the
+// configuration. Members may be apps, aliases or built-in synonyms.
+func (ox *OpenX) CreateGroup(name string, members ...string) error {
+	config, err := ox.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name = strings.ToLower(name)
+	if _, exists := config.Groups[name]; exists {
+		return fmt.Errorf("group '%s' already exists, add to it with group add", name)
+	}
+	if _, exists := config.Apps[name]; exists {
+		return fmt.Errorf("'%s' is an application, a group can't hide it", name)
+	}
+	if _, exists := config.Aliases[name]; exists {
+		return fmt.Errorf("'%s' is an alias, a group can't hide it", name)
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("group '%s' needs at least one app", name)
+	}
+
+	if config.Groups == nil {
+		config.Groups = make(map[string][]core.GroupMember)
+	}
+	config.Groups[name] = nil
+	if err := addGroupMembers(config, name, members); err != nil {
+		return err
+	}
+	return ox.saveConfig(config)
+}
+
+// AddGroupMembers appends apps to a workspace group, skipping those
+// already in it
+func (ox *OpenX) AddGroupMembers(name string, members ...string) error {
+	config, err := ox.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name = strings.ToLower(name)
+	if _, exists := config.Groups[name]; !exists {
+		return core.UnknownName(config, core.ListKindGroup, name)
+	}
+	if err := addGroupMembers(config, name, members); err != nil {
+		return err
+	}
+	return ox.saveConfig(config)
+}
+
+// addGroupMembers appends the members missing from a group, as the apps,
+// config aliases or the apps of built-in synonyms they name
+func addGroupMembers(config *core.Config, name string, members []string) error {
+	for _, member := range members {
+		member = strings.ToLower(member)
+		if _, exists := config.Apps[member]; !exists {
+			if _, isAlias := config.Aliases[member]; !isAlias {
+				app, isSynonym := core.SynonymTarget(member)
+				if _, exists := config.Apps[app]; !isSynonym || !exists {
+					return core.UnknownName(config, core.ListKindApp, member)
+				}
+				member = app
+			}
+		}
+
+		if !slices.ContainsFunc(config.Groups[name], func(m core.GroupMember) bool { return m.App == member }) {
+			config.Groups[name] = append(config.Groups[name], core.GroupMember{App: member})
+		}
+	}
+	return nil
+}
+
+// RemoveGroupMembers takes apps out of a workspace group. A group left
+// without members is removed.
+func (ox *OpenX) RemoveGroupMembers(name string, members ...string) error {
+	config, err := ox.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name = strings.ToLower(name)
+	group, exists := config.Groups[name]
+	if !exists {
+		return core.UnknownName(config, core.ListKindGroup, name)
+	}
+	for _, member := range members {
+		member = strings.ToLower(member)
+		i := slices.IndexFunc(group, func(m core.GroupMember) bool { return m.App == member })
+		if i < 0 {
+			return fmt.Errorf("%s is not in group '%s'", member, name)
+		}
+		group = slices.Delete(group, i, i+1)
+	}
+
+	if len(group) == 0 {
+		delete(config.Groups, name)
+	} else {
+		config.Groups[name] = group
+	}
+	return ox.saveConfig(config)
+}
+
+// DeleteGroup removes a workspace group from the configuration, leaving
+// its apps alone
+func (ox *OpenX) DeleteGroup(name string) error {
+	config, err := ox.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	name = strings.ToLower(name)
+
+	if _, exists := config.Groups[name]; !exists {
+		return core.UnknownName(config, core.ListKindGroup, name)
+	}
+
+	delete(config.Groups, name)
+
+	return ox.saveConfig(config)
+}
+
+// ListGroups returns every workspace group with the apps in it, in
+// launch order
+func (ox *OpenX) ListGroups() (map[string][]string, error) {
+	config, err := ox.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	groups := make(map[string][]string, len(config.Groups))
+	for name, members := range config.Groups {
+		apps := make([]string, len(members))
+		for i, member := range members {
+			apps[i] = member.App
+		}
+		groups[name] = apps
+	}
+	return groups, nil
+}
+
 // Doctor performs a health check on all configured applications
 func (ox *OpenX) Doctor() error {
 	return core.RunDoctor(false)
 }
 
+// DoctorFix finds the applications doctor reports missing and rewrites
+// their config paths, asking first unless yes is set
+func (ox *OpenX) DoctorFix(yes bool) error {
+	return core.RepairPaths(yes)
+}
+
 // DoctorJSON performs a health check and returns results in JSON format
 func (ox *OpenX) DoctorJSON() error {
 	return core.RunDoctor(true)
 }
 
+// DoctorReport performs a health check and returns the report instead of
+// printing it, including the resource usage of running apps
+func (ox *OpenX) DoctorReport() (*core.DoctorReport, error) {
+	return core.BuildDoctorReport(true)
+}
+
+// DoctorDiff performs a health check and prints what changed since the
+// last doctor run
+func (ox *OpenX) DoctorDiff() error {
+	return core.RunDoctorDiff(os.Stdout)
+}
+
+// DoctorTo performs a health check and writes it to w as human, json,
+// yaml, csv, markdown or prometheus, colored only when w is a terminal
+func (ox *OpenX) DoctorTo(w io.Writer, format string) error {
+	return core.WriteDoctor(w, format)
+}
+
+// DoctorWatch re-runs the health check every interval and on config
+// changes, redrawing it in place until interrupted
+func (ox *OpenX) DoctorWatch(format string, interval time.Duration) error {
+	return core.WatchDoctor(format, interval)
+}
+
+// DoctorProblems performs a health check and prints only the missing,
+// unconfigured, blocked and conflicting entries, in any DoctorFormat format
+func (ox *OpenX) DoctorProblems(format string) error {
+	return core.RunDoctorProblems(format)
+}
+
+// DoctorWatchProblems is DoctorWatch showing only what needs attention
+func (ox *OpenX) DoctorWatchProblems(format string, interval time.Duration) error {
+	return core.WatchDoctorProblems(format, interval)
+}
+
+// DoctorFormat performs a health check and prints it as human, json, yaml,
+// csv, markdown or prometheus
+func (ox *OpenX) DoctorFormat(format string) error {
+	return core.RunDoctorFormat(format)
+}
+
+// Status prints a one-screen glance at the environment: the config, app
+// counts, running apps and groups, and the doctor's problems
+func (ox *OpenX) Status(jsonOutput bool) error {
+	return core.RunStatus(jsonOutput)
+}
+
+// StatusReport returns what Status prints
+func (ox *OpenX) StatusReport() (*core.StatusReport, error) {
+	return core.BuildStatus()
+}
+
+// List prints every configured application, alias and group
+func (ox *OpenX) List(jsonOutput bool) error {
+	return core.RunList(jsonOutput)
+}
+
+// SetLogLevel sets how much openx reports: slog.LevelDebug adds how names
+// resolve and the commands run, slog.LevelWarn silences progress lines
+func (ox *OpenX) SetLogLevel(level slog.Level) {
+	core.SetLogLevel(level)
+}
+
+// SetColor turns colored output on or off. It starts out off when
+// NO_COLOR is set or stdout isn't a terminal.
+func (ox *OpenX) SetColor(enabled bool) {
+	core.SetColor(enabled)
+}
+
+// Pick lets the user search the configured apps and groups on the
+// terminal and choose one to launch or kill. It returns nil when nothing
+// was chosen, and core.ErrNotTerminal when there is no terminal.
+func (ox *OpenX) Pick() (*core.PickerChoice, error) {
+	return core.Pick()
+}
+
+// Search prints the applications whose name, aliases, paths or tags
+// contain query
+func (ox *OpenX) Search(query string, jsonOutput bool) error {
+	return core.RunSearch(query, jsonOutput)
+}
+
+// Recent prints the latest distinct launches, newest first
+func (ox *OpenX) Recent(jsonOutput bool) error {
+	return core.RunRecent(jsonOutput)
+}
+
+// RecentLaunches returns the latest distinct launches, newest first
+func (ox *OpenX) RecentLaunches() ([]core.RecentLaunch, error) {
+	return core.RecentLaunches()
+}
+
+// History prints the launches and kills matching filter, oldest first
+func (ox *OpenX) History(filter core.HistoryFilter, jsonOutput bool) error {
+	return core.RunHistory(filter, jsonOutput)
+}
+
+// HistoryEntries returns the launches and kills matching filter, oldest first
+func (ox *OpenX) HistoryEntries(filter core.HistoryFilter) ([]core.HistoryEntry, error) {
+	return core.History(filter)
+}
+
+// RelaunchLast launches the most recent app again with the same arguments
+func (ox *OpenX) RelaunchLast(opts core.LaunchOptions) error {
+	return core.RelaunchLast(opts)
+}
+
+// ConfigPath returns the path of the configuration file
+func (ox *OpenX) ConfigPath() string {
+	if ox.configPath != "" {
+		return ox.configPath
+	}
+	return core.ConfigPath()
+}
+
+// ConfigEntryLine returns the line of the config file defining the app,
+// group or project name stands for
+func (ox *OpenX) ConfigEntryLine(name string) (int, error) {
+	return core.ConfigEntryLine(name)
+}
+
+// Which prints the path of the app a name launches, or a group's members
+func (ox *OpenX) Which(name string, jsonOutput bool) error {
+	return core.RunWhich(name, jsonOutput)
+}
+
+// Resolve returns the app and path, or group members, a name launches
+func (ox *OpenX) Resolve(name string) (*core.WhichResult, error) {
+	return core.Which(name)
+}
+
+// PS prints every configured application with its running status, and
+// with stats the resources the running ones use
+func (ox *OpenX) PS(stats bool) error {
+	return core.RunPS(false, stats)
+}
+
+// PSJSON prints the running status of every application in JSON format
+func (ox *OpenX) PSJSON(stats bool) error {
+	return core.RunPS(true, stats)
+}
+
+// ProcessStatuses returns the running status of every configured
+// application, with resource usage when stats is set
+func (ox *OpenX) ProcessStatuses(stats bool) ([]core.ProcessStatus, error) {
+	return core.ProcessStatuses(stats)
+}
+
+// WatchConfig emits the reloaded configuration whenever the config file
+// changes, until ctx is cancelled
+func (ox *OpenX) WatchConfig(ctx context.Context) (<-chan *core.Config, error) {
+	return config.Watch(ctx)
+}
+
 // Helper methods for internal use
 
 // loadConfig loads the configuration from the default location
@@ -172,14 +672,8 @@ func (ox *OpenX) getConfigPath() string {
 		return ox.configPath
 	}
 
-	// Use XDG config directory or fallback to home directory
-	configDir := os.Getenv("XDG_CONFIG_HOME")
-	if configDir == "" {
-		homeDir, _ := os.UserHomeDir()
-		configDir = filepath.Join(homeDir, ".config")
-	}
-
-	return filepath.Join(configDir, "openx", "config.yaml")
+	// The file the rest of openx reads, ~/.openx without XDG_CONFIG_HOME
+	return core.ConfigPath()
 }
 
 // executeDirectPath executes an application by direct path
@@ -195,7 +689,7 @@ func (ox *OpenX) executeDirectPath(appPath string, args ...string) error {
 
 	// Check if the path exists
 	if _, err := os.Stat(appPath); os.IsNotExist(err) {
-		return fmt.Errorf("application not found: %s", appPath)
+		return fmt.Errorf("%w: %s", core.ErrAppNotFound, appPath)
 	}
 
 	// For macOS .app bundles, we need special handling
@@ -1,6 +1,10 @@
 package lib
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -68,6 +72,16 @@ func TestLibraryAPI(t *testing.T) {
 
 	// Test method existence (these will fail if config doesn't exist, but that's expected)
 	_ = ox.RunAlias
+	_ = ox.RunAliasWait
+	_ = ox.RunGroup
+	_ = ox.OpenFile
+	_ = ox.Open
+	_ = ox.KillWithOptions
+	_ = ox.KillGroup
+	_ = ox.KillSession
+	_ = ox.SuggestAliases
+	_ = ox.RunProject
+	_ = ox.ListProjects
 	_ = ox.RunDirect
 	_ = ox.Kill
 	_ = ox.AddAlias
@@ -79,3 +93,139 @@ func TestLibraryAPI(t *testing.T) {
 	// If we get here, all methods exist with correct signatures
 	t.Log("All library methods exist with correct signatures")
 }
+
+func TestAddAlias(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	configPath := filepath.Join(dir, "openx", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `apps:
+  vscode:
+    linux: code
+aliases:
+  vs: vscode
+groups:
+  work:
+    - vscode
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ox := NewWithConfig(configPath)
+
+	tests := []struct {
+		alias, app string
+		want       string // the app the alias points at, "" for an error
+	}{
+		{"ed", "vscode", "vscode"},
+		{"VSC", "VS", "vscode"}, // aliases don't chain
+		{"editor", "code", "vscode"},
+		{"term", "iterm", ""},
+		{"vscode", "vscode", ""},
+		{"work", "vscode", ""},
+	}
+	for _, tt := range tests {
+		err := ox.AddAlias(tt.alias, tt.app)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("AddAlias(%q, %q) succeeded, want an error", tt.alias, tt.app)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("AddAlias(%q, %q) unexpected error: %v", tt.alias, tt.app, err)
+			continue
+		}
+		aliases, err := ox.ListAliases()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := aliases[strings.ToLower(tt.alias)]; got != tt.want {
+			t.Errorf("AddAlias(%q, %q) points at %q, want %q", tt.alias, tt.app, got, tt.want)
+		}
+	}
+
+	if err := ox.RemoveAlias("VSC"); err != nil {
+		t.Errorf("RemoveAlias(VSC) unexpected error: %v", err)
+	}
+	if err := ox.RemoveAlias("vsc"); err == nil {
+		t.Error("RemoveAlias(vsc) twice succeeded, want an error")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	configPath := filepath.Join(dir, "openx", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `apps:
+  vscode:
+    linux: code
+  chrome:
+    linux: google-chrome
+  slack:
+    linux: slack
+aliases:
+  vs: vscode
+groups:
+  web:
+    - chrome
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ox := NewWithConfig(configPath)
+
+	// Aliases stay as written, synonyms lead to their app
+	if err := ox.CreateGroup("Work", "VS", "gc", "vs"); err != nil {
+		t.Fatalf("CreateGroup(Work) unexpected error: %v", err)
+	}
+	for _, name := range []string{"work", "vscode", "vs"} {
+		if err := ox.CreateGroup(name, "slack"); err == nil {
+			t.Errorf("CreateGroup(%s) succeeded, want an error", name)
+		}
+	}
+	if err := ox.CreateGroup("empty"); err == nil {
+		t.Error("CreateGroup(empty) without apps succeeded, want an error")
+	}
+	if err := ox.AddGroupMembers("work", "slack", "chrome"); err != nil {
+		t.Errorf("AddGroupMembers(work) unexpected error: %v", err)
+	}
+	if err := ox.AddGroupMembers("work", "teams"); err == nil {
+		t.Error("AddGroupMembers(work, teams) succeeded, want an error")
+	}
+	if err := ox.AddGroupMembers("play", "slack"); err == nil {
+		t.Error("AddGroupMembers(play) succeeded, want an error")
+	}
+
+	groups, err := ox.ListGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"vs", "chrome", "slack"}; !reflect.DeepEqual(groups["work"], want) {
+		t.Errorf("group work = %v, want %v", groups["work"], want)
+	}
+
+	if err := ox.RemoveGroupMembers("work", "VS", "slack"); err != nil {
+		t.Errorf("RemoveGroupMembers(work) unexpected error: %v", err)
+	}
+	if err := ox.RemoveGroupMembers("work", "slack"); err == nil {
+		t.Error("RemoveGroupMembers(work, slack) twice succeeded, want an error")
+	}
+	// Removing the last app deletes the group
+	if err := ox.RemoveGroupMembers("web", "chrome"); err != nil {
+		t.Errorf("RemoveGroupMembers(web) unexpected error: %v", err)
+	}
+	if err := ox.DeleteGroup("work"); err != nil {
+		t.Errorf("DeleteGroup(work) unexpected error: %v", err)
+	}
+	if groups, _ := ox.ListGroups(); len(groups) != 0 {
+		t.Errorf("groups left = %v, want none", groups)
+	}
+}
@@ -2,12 +2,14 @@ package config
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,14 +24,111 @@ func GetVersion() string {
 
 // Config represents the entire configuration
 type Config struct {
-	Apps    map[string]*App   `yaml:"apps"`
-	Aliases map[string]string `yaml:"aliases"`
+	Apps    map[string]*App          `yaml:"apps"`
+	Aliases map[string]string        `yaml:"aliases"`
+	Groups  map[string][]GroupMember `yaml:"groups,omitempty"`
+
+	// Projects bind a name to an editor and a directory
+	Projects map[string]Project `yaml:"projects,omitempty"`
+
+	// Extensions maps file extensions to the app that opens them, e.g. .psd: photoshop
+	Extensions map[string]string `yaml:"extensions,omitempty"`
+
+	// Protected processes are never killed, whatever an app's kill
+	// patterns match, e.g. code-server
+	Protected []string `yaml:"protected,omitempty"`
+
+	// Terminal runs terminal-mode apps: a configured app or a terminal command
+	Terminal string `yaml:"terminal,omitempty"`
+}
+
+// Project is a directory opened with an editor app: openx proj <name>
+type Project struct {
+	Editor string `yaml:"editor"` // app or alias that opens the project
+	Path   string `yaml:"path"`   // project directory, ~ is expanded
 }
 
 // App represents a single application configuration
 type App struct {
-	Paths map[string]string `yaml:",inline"`
-	Kill  []string          `yaml:"kill,omitempty"`
+	Paths        map[string]string `yaml:",inline"`
+	Type         string            `yaml:"type,omitempty"` // empty for a local app, docker for a container
+	Kill         []string          `yaml:"kill,omitempty"`
+	KillTimeout  time.Duration     `yaml:"kill_timeout,omitempty"`  // grace period after quitting before a force kill
+	Signal       string            `yaml:"signal,omitempty"`        // TERM, INT, HUP or KILL sent by openx --kill
+	KillExclude  []string          `yaml:"kill_exclude,omitempty"`  // processes the kill patterns must not touch
+	Protected    bool              `yaml:"protected,omitempty"`     // kills refuse to close the app without --force
+	Needs        []string          `yaml:"needs,omitempty"`         // apps that must be up before this one launches
+	Health       string            `yaml:"health,omitempty"`        // http(s):// or tcp:// readiness check
+	StartTimeout time.Duration     `yaml:"start_timeout,omitempty"` // how long dependents wait for this app
+	Log          bool              `yaml:"log,omitempty"`           // capture stdout/stderr to the app's log file
+	NewInstance  []string          `yaml:"new_instance,omitempty"`  // args that force a new window/instance
+	Elevated     bool              `yaml:"elevated,omitempty"`      // launch with administrator privileges
+	Desktop      int               `yaml:"desktop,omitempty"`       // macOS Space / virtual desktop to open on (1-based)
+	Window       string            `yaml:"window,omitempty"`        // minimized, maximized or fullscreen
+	Monitor      int               `yaml:"monitor,omitempty"`       // display to open the window on (1-based)
+	Retries      int               `yaml:"retries,omitempty"`       // extra launch attempts when starting fails
+	RetryBackoff time.Duration     `yaml:"retry_backoff,omitempty"` // wait before the first retry, doubled each time
+	StartupCheck time.Duration     `yaml:"startup_check,omitempty"` // fail the launch if the app exits or shows no window within this time
+	Mode         string            `yaml:"mode,omitempty"`          // detached or attached
+	User         string            `yaml:"user,omitempty"`          // launch as this user (sudo -u / runas)
+	Terminal     bool              `yaml:"terminal,omitempty"`      // run inside the configured terminal emulator
+	Priority     string            `yaml:"priority,omitempty"`      // low, below_normal, normal, above_normal or high
+	Limits       *Limits           `yaml:"limits,omitempty"`        // resource limits applied at launch
+	Profiles     map[string]string `yaml:"profiles,omitempty"`      // browser profile names, e.g. work: "Profile 1"
+	ProfileArgs  []string          `yaml:"profile_args,omitempty"`  // args selecting a profile, {profile} is replaced
+	Tags         []string          `yaml:"tags,omitempty"`          // labels selecting apps in bulk, e.g. browsers
+
+	// SaveBeforeKill saves the app's open documents through AppleScript
+	// before a kill quits it (macOS)
+	SaveBeforeKill bool `yaml:"save_before_kill,omitempty"`
+
+	// Docker apps (type: docker)
+	Image      string   `yaml:"image,omitempty"`       // image to run when the container doesn't exist
+	Container  string   `yaml:"container,omitempty"`   // container name, defaults to the app name
+	DockerArgs []string `yaml:"docker_args,omitempty"` // extra docker run arguments, e.g. ports
+}
+
+// AppTypeDocker marks an app that runs as a Docker container
+const AppTypeDocker = "docker"
+
+// Limits caps the resources a launched app may use
+type Limits struct {
+	Memory string `yaml:"memory,omitempty"` // e.g. 2GB, 512MB
+	Files  int    `yaml:"files,omitempty"`  // maximum open file descriptors (Unix)
+}
+
+// Launch path prefixes for apps that aren't started from an executable path
+const (
+	UWPPrefix      = "uwp:"      // Windows Store app: uwp:<AppUserModelId>
+	DesktopPrefix  = "desktop:"  // Linux .desktop entry: desktop:firefox.desktop
+	SnapPrefix     = "snap:"     // Linux snap: snap:<name>
+	AppImagePrefix = "appimage:" // Linux AppImage found by name: appimage:<name>
+)
+
+// Kill pattern prefixes choosing how a pattern matches processes. A plain
+// pattern matches every command line containing it.
+const (
+	KillExactPrefix = "exact:" // the process name equals the pattern
+	KillWordPrefix  = "word:"  // the pattern is a whole word of the command line
+	KillRegexPrefix = "regex:" // a regular expression over the command line
+)
+
+// SnapName returns the snap a launch path runs, for snap:<name> paths and
+// /snap/bin/<name> commands. Commands of multi-app snaps (/snap/bin/foo.bar)
+// belong to snap foo.
+func SnapName(launchPath string) (string, bool) {
+	var command string
+	switch {
+	case strings.HasPrefix(launchPath, SnapPrefix):
+		command = strings.TrimPrefix(launchPath, SnapPrefix)
+	case strings.HasPrefix(launchPath, "/snap/bin/"):
+		command = strings.TrimPrefix(launchPath, "/snap/bin/")
+	default:
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(command, ".")
+	return name, name != ""
 }
 
 // GetLaunchPath returns the launch path for the current OS
@@ -62,6 +161,28 @@ func (a *App) DeriveKillPatterns() []string {
 		return []string{}
 	}
 
+	// Store apps and desktop entries have no executable name in their
+	// path, they are matched by package or by the entry's Exec line
+	if strings.HasPrefix(launchPath, UWPPrefix) || strings.HasPrefix(launchPath, DesktopPrefix) {
+		return []string{}
+	}
+
+	// Deep links don't say which process handles them
+	if strings.Contains(launchPath, "://") {
+		return []string{}
+	}
+
+	// Snap apps run from /snap/<name>/<revision>/ under their own binary
+	// names, so match on the snap's install path
+	if name, ok := SnapName(launchPath); ok {
+		return []string{"/snap/" + name + "/"}
+	}
+
+	// AppImages are looked up by name, which their processes carry too
+	if strings.HasPrefix(launchPath, AppImagePrefix) {
+		return []string{strings.TrimPrefix(launchPath, AppImagePrefix)}
+	}
+
 	baseName := filepath.Base(launchPath)
 
 	switch runtime.GOOS {
@@ -87,6 +208,49 @@ func (a *App) DeriveKillPatterns() []string {
 	}
 }
 
+// GetNewInstanceArgs returns the arguments that make the app open a new
+// window or instance instead of reusing a running one
+func (a *App) GetNewInstanceArgs(name string) []string {
+	if len(a.NewInstance) > 0 {
+		return a.NewInstance
+	}
+	return DefaultNewInstanceArgs[name]
+}
+
+// DefaultNewInstanceArgs maps well-known single-instance apps to the
+// arguments that open a new window
+var DefaultNewInstanceArgs = map[string][]string{
+	"chrome":   {"--new-window"},
+	"chromium": {"--new-window"},
+	"edge":     {"--new-window"},
+	"brave":    {"--new-window"},
+	"vivaldi":  {"--new-window"},
+	"firefox":  {"--new-window"},
+	"vscode":   {"--new-window"},
+	"cursor":   {"--new-window"},
+	"sublime":  {"--new-window"},
+}
+
+// GetProfileArgs returns the argument template that selects a browser
+// profile, with {profile} standing for the profile
+func (a *App) GetProfileArgs(name string) []string {
+	if len(a.ProfileArgs) > 0 {
+		return a.ProfileArgs
+	}
+	return DefaultProfileArgs[name]
+}
+
+// DefaultProfileArgs maps well-known browsers to the arguments that open
+// a given profile
+var DefaultProfileArgs = map[string][]string{
+	"chrome":   {"--profile-directory={profile}"},
+	"chromium": {"--profile-directory={profile}"},
+	"edge":     {"--profile-directory={profile}"},
+	"brave":    {"--profile-directory={profile}"},
+	"vivaldi":  {"--profile-directory={profile}"},
+	"firefox":  {"-P", "{profile}"},
+}
+
 // ProcessNameExceptions maps app bundle names to actual process names
 var ProcessNameExceptions = map[string]string{
 	"Visual Studio Code": "Code",
@@ -94,6 +258,9 @@ var ProcessNameExceptions = map[string]string{
 	"IntelliJ IDEA":      "idea",
 }
 
+// ErrConfigNotFound is returned by LoadConfig when there is no config file
+var ErrConfigNotFound = errors.New("config file not found")
+
 // LoadConfig loads the configuration from file
 func LoadConfig() (*Config, error) {
 	configPath := getConfigPath()
@@ -101,14 +268,14 @@ func LoadConfig() (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("config file not found at %s (run 'openx doctor' to create it)", configPath)
+			return nil, fmt.Errorf("%w at %s (run 'openx doctor' to create it)", ErrConfigNotFound, configPath)
 		}
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, &ParseError{Path: configPath, Problems: diagnoseYAML(data, err), Err: err}
 	}
 
 	// Initialize empty maps if not present
@@ -118,6 +285,12 @@ func LoadConfig() (*Config, error) {
 	if config.Aliases == nil {
 		config.Aliases = make(map[string]string)
 	}
+	if config.Groups == nil {
+		config.Groups = make(map[string][]GroupMember)
+	}
+	if config.Projects == nil {
+		config.Projects = make(map[string]Project)
+	}
 
 	return &config, nil
 }
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorLine matches the location yaml.v3 puts in its messages, as in
+// "yaml: line 3: found character that cannot start any token" or a
+// TypeError entry "line 5: cannot unmarshal !!str `x` into int"
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (?:column (\d+): )?(.*)$`)
+
+// Problem is one thing wrong in the config file; Column is 0 when the YAML
+// parser doesn't say
+type Problem struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	Snippet string `json:"snippet,omitempty"` // the offending line
+}
+
+// ParseError is a config file that doesn't parse, with every problem
+// found in it
+type ParseError struct {
+	Path     string
+	Problems []Problem
+	Err      error // the YAML parser's error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to parse config file %s", e.Path)
+	if len(e.Problems) == 0 {
+		fmt.Fprintf(&b, ": %v", e.Err)
+		return b.String()
+	}
+	for _, p := range e.Problems {
+		if p.Column > 0 {
+			fmt.Fprintf(&b, "\n  line %d, column %d: %s", p.Line, p.Column, p.Message)
+		} else {
+			fmt.Fprintf(&b, "\n  line %d: %s", p.Line, p.Message)
+		}
+		if p.Snippet == "" {
+			continue
+		}
+		gutter := fmt.Sprintf("    %d | ", p.Line)
+		fmt.Fprintf(&b, "\n%s%s", gutter, p.Snippet)
+		if p.Column > 0 {
+			fmt.Fprintf(&b, "\n%s| %s^", strings.Repeat(" ", len(gutter)-2), strings.Repeat(" ", p.Column-1))
+		}
+	}
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// diagnoseYAML lists the problems behind a YAML parse error: every tab
+// indenting a line, which YAML forbids and the parser reports only the
+// first of, and each location the parser names. Problems are sorted by
+// line, a tab taking the place of the parser's message for its line.
+func diagnoseYAML(data []byte, err error) []Problem {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	snippet := func(line int) string {
+		if line < 1 || line > len(lines) {
+			return ""
+		}
+		// A tab shows as one space so the caret lines up with the column
+		return strings.ReplaceAll(lines[line-1], "\t", " ")
+	}
+
+	byLine := map[int]Problem{}
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if col := strings.IndexByte(indent, '\t'); col >= 0 {
+			byLine[i+1] = Problem{Line: i + 1, Column: col + 1, Message: "tab in indentation, YAML indents with spaces", Snippet: snippet(i + 1)}
+		}
+	}
+
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	for _, message := range messages {
+		match := yamlErrorLine.FindStringSubmatch(strings.TrimSpace(message))
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[1])
+		if _, tab := byLine[line]; tab {
+			continue
+		}
+		column, _ := strconv.Atoi(match[2])
+		byLine[line] = Problem{Line: line, Column: column, Message: match[3], Snippet: snippet(line)}
+	}
+
+	problems := make([]Problem, 0, len(byLine))
+	for _, p := range byLine {
+		problems = append(problems, p)
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_ParseProblems(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Problem
+	}{
+		{
+			name:    "tabs",
+			content: "apps:\n  code:\n\tlinux: code\n  slack:\n  \tlinux: slack\n",
+			want: []Problem{
+				{Line: 3, Column: 1, Message: "tab in indentation, YAML indents with spaces", Snippet: " linux: code"},
+				{Line: 5, Column: 3, Message: "tab in indentation, YAML indents with spaces", Snippet: "   linux: slack"},
+			},
+		},
+		{
+			name:    "type errors",
+			content: "apps:\n  code:\n    linux: code\n    retries: lots\n  slack:\n    kill_timeout: [5s]\n",
+			want: []Problem{
+				{Line: 4, Message: "cannot unmarshal !!str `lots` into int", Snippet: "    retries: lots"},
+				{Line: 6, Message: "cannot unmarshal !!seq into time.Duration", Snippet: "    kill_timeout: [5s]"},
+			},
+		},
+		{
+			name:    "syntax error",
+			content: "apps:\n  code:\n    kill: [code\n",
+			want: []Problem{
+				// yaml.v3 places unclosed flow sequences at the enclosing key
+				{Line: 2, Message: "did not find expected ',' or ']'", Snippet: "  code:"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "openx", "config.yaml")
+			os.MkdirAll(filepath.Dir(configPath), 0755)
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+			_, err := LoadConfig()
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("LoadConfig() error = %v, want a ParseError", err)
+			}
+			if !reflect.DeepEqual(parseErr.Problems, tt.want) {
+				t.Errorf("LoadConfig() problems = %+v, want %+v", parseErr.Problems, tt.want)
+			}
+			if !strings.HasPrefix(err.Error(), "failed to parse config file "+configPath) {
+				t.Errorf("LoadConfig() error = %q, want the config path", err)
+			}
+		})
+	}
+}
+
+func TestParseError_Snippet(t *testing.T) {
+	err := &ParseError{
+		Path:     "config.yaml",
+		Problems: []Problem{{Line: 3, Column: 1, Message: "tab in indentation, YAML indents with spaces", Snippet: " linux: code"}},
+	}
+	want := "failed to parse config file config.yaml\n" +
+		"  line 3, column 1: tab in indentation, YAML indents with spaces\n" +
+		"    3 |  linux: code\n" +
+		"      | ^"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GroupMember is a single entry of a workspace group. It is written either
+// as a bare app name or as a mapping with launch options:
+//
+//	work:
+//	  - docker
+//	  - app: dbclient
+//	    delay: 10s
+type GroupMember struct {
+	App   string        `yaml:"app"`
+	Delay time.Duration `yaml:"delay,omitempty"` // wait before launching this member
+}
+
+// UnmarshalYAML accepts both the scalar and the mapping form
+func (m *GroupMember) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		m.App = node.Value
+		return nil
+	}
+
+	type plain GroupMember
+	var member plain
+	if err := node.Decode(&member); err != nil {
+		return err
+	}
+	if member.App == "" {
+		return fmt.Errorf("line %d: group member needs an app", node.Line)
+	}
+
+	*m = GroupMember(member)
+	return nil
+}
+
+// MarshalYAML writes members without options in the short scalar form
+func (m GroupMember) MarshalYAML() (interface{}, error) {
+	if m.Delay == 0 {
+		return m.App, nil
+	}
+
+	type plain GroupMember
+	return plain(m), nil
+}
+
+// GroupMembers builds group members without launch options
+func GroupMembers(apps ...string) []GroupMember {
+	members := make([]GroupMember, len(apps))
+	for i, app := range apps {
+		members[i] = GroupMember{App: app}
+	}
+	return members
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EntryLine returns the line of the config file where name is defined
+// under section, as in apps: chrome:, counting from 1. Zero means the
+// file has no such entry.
+func EntryLine(section, name string) (int, error) {
+	configPath := getConfigPath()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, &ParseError{Path: configPath, Problems: diagnoseYAML(data, err), Err: err}
+	}
+	if len(doc.Content) == 0 {
+		return 0, nil
+	}
+	entries := mappingValue(doc.Content[0], section)
+	if entries == nil {
+		return 0, nil
+	}
+	for i := 0; i+1 < len(entries.Content); i += 2 {
+		if key := entries.Content[i]; key.Value == name {
+			return key.Line, nil
+		}
+	}
+	return 0, nil
+}
+
+// mappingValue returns the value of key in a mapping node, nil when node
+// isn't a mapping or has no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEntryLine(t *testing.T) {
+	content := `# openx configuration
+apps:
+  chrome:
+    linux: google-chrome
+    kill: [chrome]
+
+  "code":
+    linux: code
+aliases:
+  chrome: code
+groups:
+  web: [chrome, code]
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "openx", "config.yaml")
+	os.MkdirAll(filepath.Dir(configPath), 0755)
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	tests := []struct {
+		section, name string
+		want          int
+	}{
+		{"apps", "chrome", 3},
+		{"apps", "code", 7},
+		{"aliases", "chrome", 10},
+		{"groups", "web", 12},
+		{"apps", "slack", 0},
+		{"projects", "site", 0},
+	}
+	for _, tt := range tests {
+		got, err := EntryLine(tt.section, tt.name)
+		if err != nil {
+			t.Fatalf("EntryLine(%q, %q) unexpected error: %v", tt.section, tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("EntryLine(%q, %q) = %d, want %d", tt.section, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces the burst of events editors emit on save
+const watchDebounce = 100 * time.Millisecond
+
+// Watch watches the config file and emits the reloaded configuration each
+// time it changes. Edits that fail to parse are skipped so consumers keep
+// the last good config. The channel is closed when ctx is cancelled.
+func Watch(ctx context.Context) (<-chan *Config, error) {
+	configPath := getConfigPath()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Watch the directory rather than the file so atomic saves
+	// (write to temp file, rename over config) are picked up
+	if err := watcher.Add(filepath.Dir(configPath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	changes := make(chan *Config)
+	go func() {
+		defer close(changes)
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(configPath) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				debounce = time.After(watchDebounce)
+			case <-debounce:
+				debounce = nil
+				cfg, err := LoadConfig()
+				if err != nil {
+					continue
+				}
+				select {
+				case changes <- cfg:
+				case <-ctx.Done():
+					return
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "openx", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("apps:\n  one:\n    linux: one\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+
+	// Invalid YAML is skipped, the following valid edit is delivered
+	os.WriteFile(configPath, []byte("apps: [\n"), 0644)
+	time.Sleep(2 * watchDebounce)
+	os.WriteFile(configPath, []byte("apps:\n  one:\n    linux: one\n  two:\n    linux: two\n"), 0644)
+
+	select {
+	case cfg := <-changes:
+		if _, ok := cfg.Apps["two"]; !ok {
+			t.Errorf("Watch() emitted config without new app: %v", cfg.Apps)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not emit a change")
+	}
+
+	cancel()
+	for range changes {
+	}
+}